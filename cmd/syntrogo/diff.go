@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/syntropysoft/syntrogo/src/application"
+	"github.com/syntropysoft/syntrogo/src/infrastructure"
+)
+
+// diff implements `syntrogo diff old.json new.json`: reports changes between two
+// specs and fails when any of them is breaking, so it can gate CI.
+func diff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	breakingOnly := flags.Bool("breaking", false, "only report breaking changes")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// Guard clause: two specs to compare
+	if flags.NArg() != 2 {
+		return fmt.Errorf("usage: syntrogo diff [-breaking] old.json new.json")
+	}
+
+	specs := make([]map[string]interface{}, 2)
+	for i, path := range flags.Args() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if specs[i], err = infrastructure.DecodeSpec(data); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	changes := application.DiffSpecs(specs[0], specs[1])
+	breaking := 0
+	for _, change := range changes {
+		if change.Breaking {
+			breaking++
+		}
+		if change.Breaking || !*breakingOnly {
+			fmt.Println(change)
+		}
+	}
+
+	if breaking > 0 {
+		return fmt.Errorf("%d breaking change(s)", breaking)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+
+	"github.com/syntropysoft/syntrogo/src/codegen"
+)
+
+// genBinders implements `syntrogo gen binders`.
+func genBinders(args []string) error {
+	flags := flag.NewFlagSet("gen binders", flag.ContinueOnError)
+	dir := flags.String("dir", ".", "package directory to scan")
+	out := flags.String("out", codegen.BindersFile, "output file, relative to -dir")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := codegen.GenerateBinders(*dir, &buf); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(*dir, *out), buf.Bytes(), 0o644)
+}
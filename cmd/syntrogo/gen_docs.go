@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+
+	"github.com/syntropysoft/syntrogo/src/codegen"
+)
+
+// genDocs implements `syntrogo gen docs`.
+func genDocs(args []string) error {
+	flags := flag.NewFlagSet("gen docs", flag.ContinueOnError)
+	dir := flags.String("dir", ".", "package directory to scan")
+	out := flags.String("out", codegen.DocsFile, "output file, relative to -dir")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := codegen.GenerateDocs(*dir, &buf); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(*dir, *out), buf.Bytes(), 0o644)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/syntropysoft/syntrogo/src/codegen"
+	"github.com/syntropysoft/syntrogo/src/infrastructure"
+)
+
+// genServer implements `syntrogo gen server spec.yaml`: DTOs, a Handlers interface
+// and a Register function from an OpenAPI document (JSON or YAML).
+func genServer(args []string) error {
+	flags := flag.NewFlagSet("gen server", flag.ContinueOnError)
+	pkg := flags.String("pkg", "api", "package name of the generated file")
+	out := flags.String("out", "server_gen.go", "output Go file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// Guard clause: exactly one spec file
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: syntrogo gen server [-pkg api] [-out server_gen.go] spec.yaml")
+	}
+	specPath := flags.Arg(0)
+
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return err
+	}
+	spec, err := infrastructure.DecodeSpec(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", specPath, err)
+	}
+
+	var buf bytes.Buffer
+	if err := codegen.GenerateServer(*pkg, spec, &buf); err != nil {
+		return err
+	}
+	return os.WriteFile(*out, buf.Bytes(), 0o644)
+}
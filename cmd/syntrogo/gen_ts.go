@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/syntropysoft/syntrogo/src/codegen"
+	"github.com/syntropysoft/syntrogo/src/infrastructure"
+)
+
+// genTS implements `syntrogo gen ts`: TypeScript types and client from a spec
+// written by App.WriteSpec (or served at /swagger.json), as JSON or YAML.
+func genTS(args []string) error {
+	flags := flag.NewFlagSet("gen ts", flag.ContinueOnError)
+	specPath := flags.String("spec", "openapi.json", "OpenAPI document (JSON or YAML)")
+	out := flags.String("out", "api.ts", "output TypeScript file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(*specPath)
+	if err != nil {
+		return err
+	}
+	spec, err := infrastructure.DecodeSpec(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *specPath, err)
+	}
+
+	var buf bytes.Buffer
+	if err := codegen.GenerateTypeScript(spec, &buf); err != nil {
+		return err
+	}
+	return os.WriteFile(*out, buf.Bytes(), 0o644)
+}
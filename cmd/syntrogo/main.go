@@ -0,0 +1,66 @@
+// Command syntrogo is the SyntroGo command line tool.
+//
+// Usage:
+//
+//	syntrogo gen docs [-dir .] [-out syntrogo_docs.go]
+//	syntrogo gen binders [-dir .] [-out syntrogo_binders.go]
+//	syntrogo gen ts [-spec openapi.json] [-out api.ts]
+//	syntrogo gen server [-pkg api] [-out server_gen.go] spec.yaml
+//	syntrogo diff [-breaking] old.json new.json
+//	syntrogo mock [-port 3000] [-seed 0] [-latency 0s] [-error-rate 0] spec.yaml
+//
+// Designed to run from go:generate:
+//
+//	//go:generate go run github.com/syntropysoft/syntrogo/cmd/syntrogo gen docs
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// generators maps `syntrogo gen <target>` to its implementation.
+var generators = map[string]func(args []string) error{
+	"docs":    genDocs,
+	"binders": genBinders,
+	"ts":      genTS,
+	"server":  genServer,
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "syntrogo:", err)
+		os.Exit(1)
+	}
+}
+
+// run dispatches the subcommand.
+func run(args []string) error {
+	// Guard clause: subcommand required
+	if len(args) == 0 {
+		return usageError()
+	}
+
+	switch args[0] {
+	case "gen":
+		if len(args) < 2 {
+			return usageError()
+		}
+		generate, ok := generators[args[1]]
+		if !ok {
+			return fmt.Errorf("unknown generator %q", args[1])
+		}
+		return generate(args[2:])
+	case "diff":
+		return diff(args[1:])
+	case "mock":
+		return mock(args[1:])
+	default:
+		return usageError()
+	}
+}
+
+// usageError describes the available commands.
+func usageError() error {
+	return fmt.Errorf("usage: syntrogo gen <docs|binders|ts|server> [flags]\n       syntrogo diff [-breaking] old.json new.json\n       syntrogo mock [flags] spec.yaml")
+}
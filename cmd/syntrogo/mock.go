@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/syntropysoft/syntrogo/src/application"
+	"github.com/syntropysoft/syntrogo/src/domain"
+	"github.com/syntropysoft/syntrogo/src/infrastructure"
+)
+
+// mock implements `syntrogo mock spec.yaml`: serves every operation of a spec
+// with synthesized responses, plus the spec itself at /swagger.json.
+func mock(args []string) error {
+	flags := flag.NewFlagSet("mock", flag.ContinueOnError)
+	port := flags.String("port", "3000", "port to listen on")
+	config := domain.MockConfig{}
+	flags.Int64Var(&config.Seed, "seed", 0, "seed for synthesized data")
+	flags.DurationVar(&config.Latency, "latency", 0, "delay added to every response")
+	flags.DurationVar(&config.Jitter, "jitter", 0, "random extra delay, up to this value")
+	flags.Float64Var(&config.ErrorRate, "error-rate", 0, "fraction of requests failed (0 to 1)")
+	flags.IntVar(&config.ErrorStatus, "error-status", 500, "status of injected errors")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// Guard clause: exactly one spec file
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: syntrogo mock [-port 3000] [-seed 0] [-latency 0s] [-jitter 0s] [-error-rate 0] [-error-status 500] spec.yaml")
+	}
+	specPath := flags.Arg(0)
+
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return err
+	}
+	spec, err := infrastructure.DecodeSpec(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", specPath, err)
+	}
+
+	routes := application.NewRouteRegistry()
+	if err := application.NewMockServer(spec, config).Register(routes); err != nil {
+		return err
+	}
+
+	adapter := infrastructure.NewHTTPAdapter(routes, application.NewMiddlewareRegistry())
+	adapter.SetSwaggerEnabled(true)
+	adapter.SetSwaggerSpec(spec)
+
+	fmt.Fprintf(os.Stderr, "mock server for %s on :%s (%d routes)\n", specPath, *port, len(routes.GetRoutes()))
+	return adapter.StartServer(*port)
+}
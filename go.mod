@@ -2,11 +2,18 @@ module github.com/syntropysoft/syntrogo
 
 go 1.21
 
-require github.com/go-playground/validator/v10 v10.0.0
+require (
+	github.com/fxamacker/cbor/v2 v2.7.0
+	github.com/go-playground/validator/v10 v10.0.0
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	google.golang.org/protobuf v1.36.5
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/go-playground/locales v0.13.0 // indirect
 	github.com/go-playground/universal-translator v0.17.0 // indirect
 	github.com/leodido/go-urn v1.2.0 // indirect
-	golang.org/x/text v0.3.2 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
 )
@@ -0,0 +1,194 @@
+package application
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// CodecRegistry holds the codecs an app speaks, in order of preference.
+// SOLID: Single Responsibility - only selects codecs for media types
+type CodecRegistry struct {
+	codecs []domain.Codec
+}
+
+// acceptRange is one entry of an Accept header: "text/*;q=0.5".
+type acceptRange struct {
+	mediaType string
+	quality   float64
+}
+
+// NewCodecRegistry creates a registry; the first codec is the default.
+func NewCodecRegistry(codecs ...domain.Codec) *CodecRegistry {
+	registry := &CodecRegistry{}
+	for _, codec := range codecs {
+		registry.Register(codec)
+	}
+	return registry
+}
+
+// Register adds a codec, replacing the one registered for the same media type.
+func (r *CodecRegistry) Register(codec domain.Codec) {
+	for i, existing := range r.codecs {
+		if existing.MediaType() == codec.MediaType() {
+			r.codecs[i] = codec
+			return
+		}
+	}
+	r.codecs = append(r.codecs, codec)
+}
+
+// MediaTypes returns the registered media types in order of preference.
+func (r *CodecRegistry) MediaTypes() []string {
+	types := make([]string, len(r.codecs))
+	for i, codec := range r.codecs {
+		types[i] = codec.MediaType()
+	}
+	return types
+}
+
+// ForContentType returns the decoder for a Content-Type header. An empty header
+// selects the default codec; structured suffixes fall back to their base type,
+// so "application/problem+json" is read as JSON.
+func (r *CodecRegistry) ForContentType(contentType string) (domain.Codec, bool) {
+	// Guard clause: nothing registered
+	if len(r.codecs) == 0 {
+		return nil, false
+	}
+
+	mediaType := baseMediaType(contentType)
+	if mediaType == "" {
+		return r.codecs[0], true
+	}
+	if codec, ok := r.find(mediaType); ok {
+		return codec, true
+	}
+	if i := strings.LastIndex(mediaType, "+"); i >= 0 {
+		return r.find("application/" + mediaType[i+1:])
+	}
+	return nil, false
+}
+
+// Negotiate returns the encoder for an Accept header, following q-values.
+// Among equally acceptable codecs the registration order wins. An empty header
+// selects the default codec. Browsers list text/html and rank application/xml
+// above */* ("text/html,application/xml;q=0.9,*/*;q=0.8") for page navigations,
+// so headers listing text/html that also accept */* get the default codec too,
+// unless it is excluded. False means nothing acceptable (406).
+func (r *CodecRegistry) Negotiate(accept string) (domain.Codec, bool) {
+	// Guard clause: nothing registered
+	if len(r.codecs) == 0 {
+		return nil, false
+	}
+
+	ranges := parseAccept(accept)
+	if len(ranges) == 0 {
+		return r.codecs[0], true
+	}
+	if isBrowserAccept(ranges) && acceptQuality(r.codecs[0].MediaType(), ranges) > 0 {
+		return r.codecs[0], true
+	}
+
+	var best domain.Codec
+	bestQuality := 0.0
+	for _, codec := range r.codecs {
+		if quality := acceptQuality(codec.MediaType(), ranges); quality > bestQuality {
+			best, bestQuality = codec, quality
+		}
+	}
+	return best, best != nil
+}
+
+// Acceptable reports whether an Accept header allows a media type; an empty
+// header allows anything.
+func (r *CodecRegistry) Acceptable(accept, mediaType string) bool {
+	ranges := parseAccept(accept)
+	return len(ranges) == 0 || acceptQuality(mediaType, ranges) > 0
+}
+
+// Default returns the first registered codec.
+func (r *CodecRegistry) Default() (domain.Codec, bool) {
+	if len(r.codecs) == 0 {
+		return nil, false
+	}
+	return r.codecs[0], true
+}
+
+// find returns the codec registered for a media type.
+func (r *CodecRegistry) find(mediaType string) (domain.Codec, bool) {
+	for _, codec := range r.codecs {
+		if codec.MediaType() == mediaType {
+			return codec, true
+		}
+	}
+	return nil, false
+}
+
+// acceptQuality returns the q-value of the most specific range matching a media
+// type: exact beats "type/*", which beats "*/*". Zero when nothing matches.
+func acceptQuality(mediaType string, ranges []acceptRange) float64 {
+	mainType := strings.SplitN(mediaType, "/", 2)[0]
+
+	quality, specificity := 0.0, 0
+	for _, candidate := range ranges {
+		level := 0
+		switch {
+		case candidate.mediaType == mediaType:
+			level = 3
+		case candidate.mediaType == mainType+"/*":
+			level = 2
+		case candidate.mediaType == "*/*":
+			level = 1
+		}
+		if level > specificity {
+			quality, specificity = candidate.quality, level
+		}
+	}
+	return quality
+}
+
+// isBrowserAccept reports whether the ranges look like a browser navigation:
+// text/html listed and */* accepted with a non-zero q-value.
+func isBrowserAccept(ranges []acceptRange) bool {
+	html, anything := false, false
+	for _, candidate := range ranges {
+		switch {
+		case candidate.mediaType == "text/html":
+			html = true
+		case candidate.mediaType == "*/*" && candidate.quality > 0:
+			anything = true
+		}
+	}
+	return html && anything
+}
+
+// parseAccept splits an Accept header into media ranges with their q-values.
+func parseAccept(accept string) []acceptRange {
+	ranges := []acceptRange{}
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+		if mediaType == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || strings.ToLower(strings.TrimSpace(key)) != "q" {
+				continue
+			}
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && q >= 0 && q <= 1 {
+				quality = q
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, quality: quality})
+	}
+	return ranges
+}
+
+// baseMediaType drops parameters: "application/json; charset=utf-8" becomes "application/json".
+func baseMediaType(contentType string) string {
+	return strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+}
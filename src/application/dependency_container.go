@@ -0,0 +1,425 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+var (
+	domainContextType = reflect.TypeOf((*domain.Context)(nil))
+	stdContextType    = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType         = reflect.TypeOf((*error)(nil)).Elem()
+	cleanupType       = reflect.TypeOf(func() {})
+)
+
+// requestScopeKey stores the per-request dependency cache on the Context.
+var requestScopeKey = domain.NewKey[*requestScope]("application.dependencies")
+
+// DependencyContainer resolves handler arguments from registered providers (like FastAPI's Depends).
+//
+// A provider is a function whose parameters are other dependencies and whose results are
+// the value, an optional cleanup func() and an optional error:
+//
+//	func(ctx *domain.Context, db *sql.DB) (*sql.Tx, func(), error)
+//
+// *domain.Context and context.Context are always available. A struct (or pointer to
+// struct) with no provider of its own is built field by field; tag a field inject:"-" to skip it.
+type DependencyContainer struct {
+	providers map[reflect.Type]*provider
+	handlers  []injectedHandler
+	problems  []string // Registration errors, reported by Validate
+
+	mu      sync.Mutex
+	closers []func() // Singleton cleanups, run by Close
+}
+
+// provider is one registered constructor.
+type provider struct {
+	fn         reflect.Value
+	out        reflect.Type
+	in         []reflect.Type
+	scope      domain.Scope
+	hasCleanup bool
+	hasError   bool
+
+	mu    sync.Mutex // Singletons only
+	built bool
+	value reflect.Value
+}
+
+// injectedHandler is a handler signature kept for Validate.
+type injectedHandler struct {
+	name string
+	in   []reflect.Type
+}
+
+// requestScope caches the request-scoped values of one request. Their cleanups
+// are registered on the Context, which runs them after the response.
+type requestScope struct {
+	values map[reflect.Type]reflect.Value
+}
+
+// resolution is the state of one resolve call.
+type resolution struct {
+	ctx        *domain.Context // nil while building a singleton
+	scope      *requestScope   // nil while building a singleton
+	addCleanup func(func())
+}
+
+// NewDependencyContainer creates an empty container.
+func NewDependencyContainer() *DependencyContainer {
+	return &DependencyContainer{
+		providers: make(map[reflect.Type]*provider),
+	}
+}
+
+// Provide registers a provider for the type of its first result.
+// Invalid providers are reported by Validate.
+func (d *DependencyContainer) Provide(fn interface{}, scope domain.Scope) {
+	value := reflect.ValueOf(fn)
+
+	// Guard clauses: provider signature
+	if value.Kind() != reflect.Func {
+		d.problems = append(d.problems, fmt.Sprintf("provider %T is not a function", fn))
+		return
+	}
+	typ := value.Type()
+	p := &provider{fn: value, scope: scope}
+	switch {
+	case typ.NumOut() == 1:
+	case typ.NumOut() == 2 && typ.Out(1) == errorType:
+		p.hasError = true
+	case typ.NumOut() == 2 && typ.Out(1) == cleanupType:
+		p.hasCleanup = true
+	case typ.NumOut() == 3 && typ.Out(1) == cleanupType && typ.Out(2) == errorType:
+		p.hasCleanup, p.hasError = true, true
+	default:
+		d.problems = append(d.problems, fmt.Sprintf("provider %s must return T, (T, error), (T, func()) or (T, func(), error)", typ))
+		return
+	}
+	p.out = typ.Out(0)
+	if p.out == domainContextType || p.out == stdContextType {
+		d.problems = append(d.problems, fmt.Sprintf("provider %s cannot replace the built-in %s", typ, p.out))
+		return
+	}
+	if _, exists := d.providers[p.out]; exists {
+		d.problems = append(d.problems, fmt.Sprintf("duplicate provider for %s", p.out))
+		return
+	}
+	for i := 0; i < typ.NumIn(); i++ {
+		p.in = append(p.in, typ.In(i))
+	}
+	d.providers[p.out] = p
+}
+
+// Inject turns a function taking dependencies into a handler. The function returns
+// error, or (T, error) to answer with T as JSON. The status is the route's declared
+// ResponseStatus, or one the function sets with ctx.Status; 200 by default.
+// Request-scoped and transient cleanups run once the response is written, so T
+// may still read from them.
+func (d *DependencyContainer) Inject(fn interface{}) domain.HandlerFunc {
+	value := reflect.ValueOf(fn)
+
+	// Guard clauses: handler signature
+	if value.Kind() != reflect.Func {
+		d.problems = append(d.problems, fmt.Sprintf("handler %T is not a function", fn))
+		return func(*domain.Context) error { return domain.InternalError }
+	}
+	typ := value.Type()
+	if typ.NumOut() < 1 || typ.NumOut() > 2 || typ.Out(typ.NumOut()-1) != errorType {
+		d.problems = append(d.problems, fmt.Sprintf("handler %s must return error or (T, error)", typ))
+		return func(*domain.Context) error { return domain.InternalError }
+	}
+
+	in := make([]reflect.Type, typ.NumIn())
+	for i := range in {
+		in[i] = typ.In(i)
+	}
+	d.handlers = append(d.handlers, injectedHandler{name: typ.String(), in: in})
+
+	return func(c *domain.Context) error {
+		r := &resolution{ctx: c, scope: d.requestScope(c), addCleanup: c.Cleanup}
+
+		args := make([]reflect.Value, len(in))
+		for i, argType := range in {
+			arg, err := d.resolve(r, argType)
+			if err != nil {
+				return err
+			}
+			args[i] = arg
+		}
+
+		out := value.Call(args)
+		if err, _ := out[len(out)-1].Interface().(error); err != nil {
+			return err
+		}
+		if len(out) == 2 {
+			return c.JSON(c.StatusCode, out[0].Interface())
+		}
+		return nil
+	}
+}
+
+// Validate checks that every dependency has a provider, that there are no cycles and
+// that singletons don't capture request-scoped values. The app calls it at startup.
+func (d *DependencyContainer) Validate() error {
+	problems := append([]string{}, d.problems...)
+
+	for _, typ := range d.providerTypes() {
+		p := d.providers[typ]
+		for _, dep := range p.in {
+			if problem := d.check(dep, p.scope == domain.ScopeSingleton, []reflect.Type{typ}); problem != "" {
+				problems = append(problems, problem)
+			}
+		}
+	}
+	for _, h := range d.handlers {
+		for _, dep := range h.in {
+			if problem := d.check(dep, false, nil); problem != "" {
+				problems = append(problems, fmt.Sprintf("handler %s: %s", h.name, problem))
+			}
+		}
+	}
+
+	// Guard clause: all dependencies resolvable
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("dependency injection: %s", strings.Join(problems, "; "))
+}
+
+// Close runs singleton cleanups in reverse creation order.
+func (d *DependencyContainer) Close() {
+	d.mu.Lock()
+	closers := d.closers
+	d.closers = nil
+	d.mu.Unlock()
+
+	for i := len(closers) - 1; i >= 0; i-- {
+		closers[i]()
+	}
+}
+
+// check walks the dependencies of typ. path holds the providers being resolved.
+func (d *DependencyContainer) check(typ reflect.Type, singleton bool, path []reflect.Type) string {
+	if typ == domainContextType || typ == stdContextType {
+		if singleton {
+			return fmt.Sprintf("singleton %s captures request-scoped %s", formatTypePath(path), typ)
+		}
+		return ""
+	}
+
+	for i, seen := range path {
+		if seen == typ {
+			cycle := append(append([]reflect.Type{}, path[i:]...), typ)
+			return "dependency cycle: " + formatTypePath(cycle)
+		}
+	}
+
+	p, ok := d.providers[typ]
+	if !ok {
+		fields, isStruct := injectableFields(typ)
+		if !isStruct && len(path) > 0 {
+			return fmt.Sprintf("no provider for %s (needed by %s)", typ, path[len(path)-1])
+		}
+		if !isStruct {
+			return fmt.Sprintf("no provider for %s", typ)
+		}
+		// Struct types join the path so self-referencing structs are reported, not followed
+		for _, field := range fields {
+			if problem := d.check(field.Type, singleton, append(path[:len(path):len(path)], typ)); problem != "" {
+				return problem
+			}
+		}
+		return ""
+	}
+
+	if singleton && p.scope == domain.ScopeRequest {
+		return fmt.Sprintf("singleton %s captures request-scoped %s", formatTypePath(path), typ)
+	}
+	// Values built inside a singleton live as long as it does
+	singleton = singleton || p.scope == domain.ScopeSingleton
+	for _, dep := range p.in {
+		if problem := d.check(dep, singleton, append(path[:len(path):len(path)], typ)); problem != "" {
+			return problem
+		}
+	}
+	return ""
+}
+
+// resolve returns the value of one dependency.
+func (d *DependencyContainer) resolve(r *resolution, typ reflect.Type) (reflect.Value, error) {
+	switch typ {
+	case domainContextType, stdContextType:
+		// Guard clause: Validate rejects this, but providers can be added after startup
+		if r.ctx == nil {
+			return reflect.Value{}, fmt.Errorf("dependency injection: %s is not available to singletons", typ)
+		}
+		if typ == stdContextType {
+			return reflect.ValueOf(r.ctx.Context()), nil
+		}
+		return reflect.ValueOf(r.ctx), nil
+	}
+
+	p, ok := d.providers[typ]
+	if !ok {
+		return d.resolveStruct(r, typ)
+	}
+
+	switch p.scope {
+	case domain.ScopeSingleton:
+		return d.singleton(p)
+	case domain.ScopeTransient:
+		return d.build(r, p)
+	}
+
+	// Guard clause: request scope while building a singleton
+	if r.scope == nil {
+		return reflect.Value{}, fmt.Errorf("dependency injection: request-scoped %s is not available to singletons", typ)
+	}
+	if value, ok := r.scope.values[typ]; ok {
+		return value, nil
+	}
+	value, err := d.build(r, p)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	r.scope.values[typ] = value
+	return value, nil
+}
+
+// singleton builds a singleton once. Failed builds are retried on the next request.
+func (d *DependencyContainer) singleton(p *provider) (reflect.Value, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.built {
+		return p.value, nil
+	}
+	r := &resolution{addCleanup: func(fn func()) {
+		d.mu.Lock()
+		d.closers = append(d.closers, fn)
+		d.mu.Unlock()
+	}}
+	value, err := d.build(r, p)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	p.value, p.built = value, true
+	return value, nil
+}
+
+// build calls a provider with its resolved dependencies.
+func (d *DependencyContainer) build(r *resolution, p *provider) (reflect.Value, error) {
+	args := make([]reflect.Value, len(p.in))
+	for i, typ := range p.in {
+		arg, err := d.resolve(r, typ)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		args[i] = arg
+	}
+
+	out := p.fn.Call(args)
+	if p.hasError {
+		if err, _ := out[len(out)-1].Interface().(error); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+	if p.hasCleanup {
+		if cleanup, _ := out[1].Interface().(func()); cleanup != nil {
+			r.addCleanup(cleanup)
+		}
+	}
+	return out[0], nil
+}
+
+// resolveStruct builds a struct (or pointer to struct) by resolving its fields.
+func (d *DependencyContainer) resolveStruct(r *resolution, typ reflect.Type) (reflect.Value, error) {
+	fields, ok := injectableFields(typ)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("dependency injection: no provider for %s", typ)
+	}
+
+	structType := typ
+	if typ.Kind() == reflect.Ptr {
+		structType = typ.Elem()
+	}
+	value := reflect.New(structType).Elem()
+	for _, field := range fields {
+		fieldValue, err := d.resolve(r, field.Type)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value.FieldByIndex(field.Index).Set(fieldValue)
+	}
+
+	if typ.Kind() == reflect.Ptr {
+		return value.Addr(), nil
+	}
+	return value, nil
+}
+
+// requestScope returns the request's dependency cache, creating it on first use.
+func (d *DependencyContainer) requestScope(c *domain.Context) *requestScope {
+	if scope, ok := requestScopeKey.Get(c); ok {
+		return scope
+	}
+	scope := &requestScope{values: make(map[reflect.Type]reflect.Value)}
+	requestScopeKey.Set(c, scope)
+	return scope
+}
+
+// providerTypes returns the provided types in a stable order.
+func (d *DependencyContainer) providerTypes() []reflect.Type {
+	names := make([]string, 0, len(d.providers))
+	byName := make(map[string]reflect.Type, len(d.providers))
+	for typ := range d.providers {
+		name := typ.PkgPath() + "." + typ.String()
+		names = append(names, name)
+		byName[name] = typ
+	}
+	sort.Strings(names)
+
+	types := make([]reflect.Type, len(names))
+	for i, name := range names {
+		types[i] = byName[name]
+	}
+	return types
+}
+
+// injectableFields returns the fields filled for a struct dependency.
+// The boolean is false when typ is not a struct or pointer to struct.
+func injectableFields(typ reflect.Type) ([]reflect.StructField, bool) {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	fields := []reflect.StructField{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() || field.Tag.Get("inject") == "-" {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields, true
+}
+
+// formatTypePath renders a dependency chain: "*A -> *B -> *A".
+func formatTypePath(path []reflect.Type) string {
+	names := make([]string, len(path))
+	for i, typ := range path {
+		names[i] = typ.String()
+	}
+	return strings.Join(names, " -> ")
+}
@@ -3,9 +3,22 @@
 package application
 
 // Modules:
-// - RouteRegistry: Manages HTTP routes
+// - RouteRegistry: Manages HTTP routes and matches :param and trailing *rest paths
 // - SchemaValidator: Validates structs with go-playground/validator
 // - OpenAPIGenerator: Generates OpenAPI 3.0 specs from reflection
+// - SchemaBuilder: Builds JSON Schemas from Go types and struct tags
+// - ParamBinder: Binds path, query, header and cookie values to structs
+// - Union registry: Polymorphic (oneOf) types decoded by discriminator
+// - CompareSpecs: Checks an app's spec against a source spec (spec-first)
+// - DiffSpecs: Detects breaking changes between two spec versions
+// - MockServer: Serves synthesized responses from a spec (mock mode)
+// - Timeout: Per-route deadlines on the request context (503/504)
+// - DependencyContainer: Providers injected into handlers (request, singleton, transient)
+// - TaskPool: Bounded workers for background tasks (Context.AddTask)
+// - CodecRegistry: Content-Type/Accept negotiation between body codecs
+// - TypeInfo: Parsed struct tags cached per type, shared by binders and schemas
+// - TypeCodec registry: Generated decoders, encoders and validators (syntrogo gen binders)
+// - JSONReader/JSONWriter: encoding/json-compatible primitives for generated code
 // - MiddlewareRegistry: Manages middleware chain
 //
 // Principles:
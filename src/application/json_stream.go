@@ -0,0 +1,580 @@
+package application
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// JSONReader is a pull parser over a JSON document, used by generated decoders
+// (`syntrogo gen binders`) instead of reflection. Semantics follow encoding/json:
+// null leaves scalars untouched and unknown keys are skipped.
+type JSONReader struct {
+	data []byte
+	pos  int
+}
+
+// JSONWriter appends JSON to a buffer, used by generated encoders. Output is
+// byte-for-byte what encoding/json produces, HTML escaping included.
+type JSONWriter struct {
+	buf []byte
+	err error
+}
+
+// NewJSONReader creates a reader over data.
+func NewJSONReader(data []byte) *JSONReader {
+	return &JSONReader{data: data}
+}
+
+// Object reads an object, calling field for each key with the reader positioned
+// on its value; field must consume the value. null is accepted and reads nothing.
+func (r *JSONReader) Object(field func(key string) error) error {
+	if r.Null() {
+		return nil
+	}
+	if err := r.expect('{'); err != nil {
+		return err
+	}
+	if r.peek() == '}' {
+		r.pos++
+		return nil
+	}
+
+	for {
+		key, err := r.String()
+		if err != nil {
+			return err
+		}
+		if err := r.expect(':'); err != nil {
+			return err
+		}
+		if err := field(key); err != nil {
+			return err
+		}
+
+		switch r.peek() {
+		case ',':
+			r.pos++
+		case '}':
+			r.pos++
+			return nil
+		default:
+			return r.syntaxError("after object value")
+		}
+	}
+}
+
+// Array reads an array, calling item with the reader positioned on each element.
+func (r *JSONReader) Array(item func() error) error {
+	if err := r.expect('['); err != nil {
+		return err
+	}
+	if r.peek() == ']' {
+		r.pos++
+		return nil
+	}
+
+	for {
+		if err := item(); err != nil {
+			return err
+		}
+
+		switch r.peek() {
+		case ',':
+			r.pos++
+		case ']':
+			r.pos++
+			return nil
+		default:
+			return r.syntaxError("after array element")
+		}
+	}
+}
+
+// Null consumes a null literal; false leaves the reader where it was.
+func (r *JSONReader) Null() bool {
+	if r.peek() == 'n' && bytes.HasPrefix(r.data[r.pos:], []byte("null")) {
+		r.pos += 4
+		return true
+	}
+	return false
+}
+
+// String reads a string, decoding escapes; invalid UTF-8 becomes U+FFFD.
+func (r *JSONReader) String() (string, error) {
+	if err := r.expect('"'); err != nil {
+		return "", err
+	}
+
+	// Fast path: no escapes and valid UTF-8
+	start := r.pos
+	for r.pos < len(r.data) {
+		c := r.data[r.pos]
+		if c == '"' {
+			s := r.data[start:r.pos]
+			r.pos++
+			if utf8.Valid(s) {
+				return string(s), nil
+			}
+			return strings.ToValidUTF8(string(s), "\ufffd"), nil
+		}
+		if c == '\\' || c < 0x20 {
+			break
+		}
+		r.pos++
+	}
+
+	buf := append([]byte(nil), r.data[start:r.pos]...)
+	for r.pos < len(r.data) {
+		c := r.data[r.pos]
+		switch {
+		case c == '"':
+			r.pos++
+			return strings.ToValidUTF8(string(buf), "\ufffd"), nil
+		case c < 0x20:
+			return "", r.syntaxError("in string literal")
+		case c != '\\':
+			buf = append(buf, c)
+			r.pos++
+			continue
+		}
+
+		// Escape sequence
+		if r.pos+1 >= len(r.data) {
+			return "", r.syntaxError("in string escape")
+		}
+		r.pos += 2
+		switch r.data[r.pos-1] {
+		case '"', '\\', '/':
+			buf = append(buf, r.data[r.pos-1])
+		case 'b':
+			buf = append(buf, '\b')
+		case 'f':
+			buf = append(buf, '\f')
+		case 'n':
+			buf = append(buf, '\n')
+		case 'r':
+			buf = append(buf, '\r')
+		case 't':
+			buf = append(buf, '\t')
+		case 'u':
+			rn, ok := r.hex4()
+			if !ok {
+				return "", r.syntaxError("in \\u escape")
+			}
+			if utf16.IsSurrogate(rn) {
+				rn = r.lowSurrogate(rn)
+			}
+			buf = utf8.AppendRune(buf, rn)
+		default:
+			return "", r.syntaxError("in string escape")
+		}
+	}
+	return "", r.syntaxError("in string literal")
+}
+
+// Int reads an integer that fits in bits.
+func (r *JSONReader) Int(bits int) (int64, error) {
+	literal, err := r.number()
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseInt(literal, 10, bits)
+	if err != nil {
+		return 0, fmt.Errorf("json: cannot unmarshal number %s into %s", literal, sizedType("int", bits))
+	}
+	return n, nil
+}
+
+// Uint reads an unsigned integer that fits in bits.
+func (r *JSONReader) Uint(bits int) (uint64, error) {
+	literal, err := r.number()
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseUint(literal, 10, bits)
+	if err != nil {
+		return 0, fmt.Errorf("json: cannot unmarshal number %s into %s", literal, sizedType("uint", bits))
+	}
+	return n, nil
+}
+
+// Float reads a number as a float of the given size.
+func (r *JSONReader) Float(bits int) (float64, error) {
+	literal, err := r.number()
+	if err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(literal, bits)
+	if err != nil {
+		return 0, fmt.Errorf("json: cannot unmarshal number %s into float%d", literal, bits)
+	}
+	return f, nil
+}
+
+// sizedType names a numeric type: int with 0 bits, int32 with 32.
+func sizedType(name string, bits int) string {
+	if bits == 0 {
+		return name
+	}
+	return name + strconv.Itoa(bits)
+}
+
+// Bool reads true or false.
+func (r *JSONReader) Bool() (bool, error) {
+	r.skipSpace()
+	rest := r.data[r.pos:]
+	switch {
+	case bytes.HasPrefix(rest, []byte("true")):
+		r.pos += 4
+		return true, nil
+	case bytes.HasPrefix(rest, []byte("false")):
+		r.pos += 5
+		return false, nil
+	}
+	return false, r.syntaxError("looking for a boolean")
+}
+
+// Bytes reads a base64 string, the encoding/json form of []byte.
+func (r *JSONReader) Bytes() ([]byte, error) {
+	s, err := r.String()
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(s)
+}
+
+// Raw returns the next value undecoded, for types with their own UnmarshalJSON.
+func (r *JSONReader) Raw() ([]byte, error) {
+	r.skipSpace()
+	start := r.pos
+	if err := r.Skip(); err != nil {
+		return nil, err
+	}
+	return r.data[start:r.pos], nil
+}
+
+// Skip consumes the next value whatever its type.
+func (r *JSONReader) Skip() error {
+	switch c := r.peek(); {
+	case c == '{':
+		return r.Object(func(string) error { return r.Skip() })
+	case c == '[':
+		return r.Array(r.Skip)
+	case c == '"':
+		_, err := r.String()
+		return err
+	case c == 't' || c == 'f':
+		_, err := r.Bool()
+		return err
+	case c == 'n':
+		if r.Null() {
+			return nil
+		}
+	case c == '-' || (c >= '0' && c <= '9'):
+		_, err := r.number()
+		return err
+	}
+	return r.syntaxError("looking for beginning of value")
+}
+
+// End checks that only whitespace follows the decoded value.
+func (r *JSONReader) End() error {
+	r.skipSpace()
+	if r.pos != len(r.data) {
+		return r.syntaxError("after top-level value")
+	}
+	return nil
+}
+
+// number reads a number literal following the JSON grammar.
+func (r *JSONReader) number() (string, error) {
+	r.skipSpace()
+	start := r.pos
+	if r.pos < len(r.data) && r.data[r.pos] == '-' {
+		r.pos++
+	}
+	if r.pos < len(r.data) && r.data[r.pos] == '0' {
+		r.pos++
+	} else if !r.digits() {
+		return "", r.syntaxError("in numeric literal")
+	}
+	if r.pos < len(r.data) && r.data[r.pos] == '.' {
+		r.pos++
+		if !r.digits() {
+			return "", r.syntaxError("after decimal point in numeric literal")
+		}
+	}
+	if r.pos < len(r.data) && (r.data[r.pos] == 'e' || r.data[r.pos] == 'E') {
+		r.pos++
+		if r.pos < len(r.data) && (r.data[r.pos] == '+' || r.data[r.pos] == '-') {
+			r.pos++
+		}
+		if !r.digits() {
+			return "", r.syntaxError("in exponent of numeric literal")
+		}
+	}
+	return string(r.data[start:r.pos]), nil
+}
+
+// digits consumes a run of decimal digits.
+func (r *JSONReader) digits() bool {
+	start := r.pos
+	for r.pos < len(r.data) && r.data[r.pos] >= '0' && r.data[r.pos] <= '9' {
+		r.pos++
+	}
+	return r.pos > start
+}
+
+// hex4 reads the four hex digits of a \u escape.
+func (r *JSONReader) hex4() (rune, bool) {
+	if r.pos+4 > len(r.data) {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(string(r.data[r.pos:r.pos+4]), 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	r.pos += 4
+	return rune(n), true
+}
+
+// lowSurrogate completes a UTF-16 pair; unpaired halves become U+FFFD.
+func (r *JSONReader) lowSurrogate(high rune) rune {
+	if r.pos+6 <= len(r.data) && r.data[r.pos] == '\\' && r.data[r.pos+1] == 'u' {
+		saved := r.pos
+		r.pos += 2
+		if low, ok := r.hex4(); ok {
+			if rn := utf16.DecodeRune(high, low); rn != utf8.RuneError {
+				return rn
+			}
+		}
+		r.pos = saved
+	}
+	return utf8.RuneError
+}
+
+// expect consumes one structural character.
+func (r *JSONReader) expect(c byte) error {
+	if r.peek() != c {
+		return r.syntaxError(fmt.Sprintf("looking for %q", c))
+	}
+	r.pos++
+	return nil
+}
+
+// peek skips whitespace and returns the next byte (0 at the end).
+func (r *JSONReader) peek() byte {
+	r.skipSpace()
+	if r.pos >= len(r.data) {
+		return 0
+	}
+	return r.data[r.pos]
+}
+
+// skipSpace advances past JSON whitespace.
+func (r *JSONReader) skipSpace() {
+	for r.pos < len(r.data) {
+		switch r.data[r.pos] {
+		case ' ', '\t', '\n', '\r':
+			r.pos++
+		default:
+			return
+		}
+	}
+}
+
+// syntaxError describes malformed input at the current offset.
+func (r *JSONReader) syntaxError(context string) error {
+	if r.pos >= len(r.data) {
+		return errors.New("json: unexpected end of JSON input")
+	}
+	return fmt.Errorf("json: invalid character %q %s at offset %d", r.data[r.pos], context, r.pos)
+}
+
+// Raw appends pre-encoded JSON (punctuation and quoted keys).
+func (w *JSONWriter) Raw(s string) {
+	w.buf = append(w.buf, s...)
+}
+
+// Field starts an object member, writing the separating comma when one precedes it.
+// key is the quoted name followed by a colon: `"name":`.
+func (w *JSONWriter) Field(more *bool, key string) {
+	if *more {
+		w.buf = append(w.buf, ',')
+	}
+	*more = true
+	w.buf = append(w.buf, key...)
+}
+
+// Null writes null.
+func (w *JSONWriter) Null() {
+	w.buf = append(w.buf, "null"...)
+}
+
+// Bool writes true or false.
+func (w *JSONWriter) Bool(b bool) {
+	w.buf = strconv.AppendBool(w.buf, b)
+}
+
+// Int writes a signed integer.
+func (w *JSONWriter) Int(n int64) {
+	w.buf = strconv.AppendInt(w.buf, n, 10)
+}
+
+// Uint writes an unsigned integer.
+func (w *JSONWriter) Uint(n uint64) {
+	w.buf = strconv.AppendUint(w.buf, n, 10)
+}
+
+// Float writes a float the way encoding/json does: exponent notation only for
+// very small or very large magnitudes. NaN and infinities fail the encoding.
+func (w *JSONWriter) Float(f float64, bits int) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		w.Fail(fmt.Errorf("json: unsupported value: %s", strconv.FormatFloat(f, 'g', -1, bits)))
+		return
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	start := len(w.buf)
+	w.buf = strconv.AppendFloat(w.buf, f, format, -1, bits)
+
+	// Clean up e-09 to e-9
+	if format == 'e' {
+		n := len(w.buf) - start
+		if n >= 4 && w.buf[len(w.buf)-4] == 'e' && w.buf[len(w.buf)-3] == '-' && w.buf[len(w.buf)-2] == '0' {
+			w.buf[len(w.buf)-2] = w.buf[len(w.buf)-1]
+			w.buf = w.buf[:len(w.buf)-1]
+		}
+	}
+}
+
+// String writes a quoted string with encoding/json's escaping (HTML-safe,
+// U+2028 and U+2029 escaped, invalid UTF-8 replaced).
+func (w *JSONWriter) String(s string) {
+	const hex = "0123456789abcdef"
+	w.buf = append(w.buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			w.buf = append(w.buf, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				w.buf = append(w.buf, '\\', c)
+			case '\b':
+				w.buf = append(w.buf, '\\', 'b')
+			case '\f':
+				w.buf = append(w.buf, '\\', 'f')
+			case '\n':
+				w.buf = append(w.buf, '\\', 'n')
+			case '\r':
+				w.buf = append(w.buf, '\\', 'r')
+			case '\t':
+				w.buf = append(w.buf, '\\', 't')
+			default:
+				w.buf = append(w.buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		rn, size := utf8.DecodeRuneInString(s[i:])
+		if rn == utf8.RuneError && size == 1 {
+			w.buf = append(w.buf, s[start:i]...)
+			w.buf = append(w.buf, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		if rn == '\u2028' || rn == '\u2029' {
+			w.buf = append(w.buf, s[start:i]...)
+			w.buf = append(w.buf, '\\', 'u', '2', '0', '2', hex[rn&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	w.buf = append(w.buf, s[start:]...)
+	w.buf = append(w.buf, '"')
+}
+
+// Bytes writes a []byte as a base64 string; nil is null.
+func (w *JSONWriter) Bytes(b []byte) {
+	if b == nil {
+		w.Null()
+		return
+	}
+	encoded := make([]byte, base64.StdEncoding.EncodedLen(len(b)))
+	base64.StdEncoding.Encode(encoded, b)
+	w.buf = append(w.buf, '"')
+	w.buf = append(w.buf, encoded...)
+	w.buf = append(w.buf, '"')
+}
+
+// JSON writes the result of a MarshalJSON method (time.Time).
+func (w *JSONWriter) JSON(data []byte, err error) {
+	if err != nil {
+		w.Fail(err)
+		return
+	}
+	w.buf = append(w.buf, data...)
+}
+
+// Fail records the first encoding error.
+func (w *JSONWriter) Fail(err error) {
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+// Result returns the encoded document or the first error.
+func (w *JSONWriter) Result() ([]byte, error) {
+	if w.err != nil {
+		return nil, w.err
+	}
+	return w.buf, nil
+}
+
+// SortedKeys returns the keys of a map in order, as encoding/json writes them.
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// MatchKey maps an object key to a declared name: exact match first, then
+// case-insensitive like encoding/json. "" means no field takes the key.
+func MatchKey(key string, names []string) string {
+	for _, name := range names {
+		if name == key {
+			return name
+		}
+	}
+	for _, name := range names {
+		if strings.EqualFold(name, key) {
+			return name
+		}
+	}
+	return ""
+}
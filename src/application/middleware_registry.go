@@ -8,6 +8,7 @@ import (
 // SOLID: Single Responsibility - only manages middleware
 type MiddlewareRegistry struct {
 	middlewares []domain.Middleware
+	security    []domain.SecurityScheme // Schemes enforced by secured middlewares
 }
 
 // NewMiddlewareRegistry creates a new middleware registry.
@@ -22,6 +23,12 @@ func (r *MiddlewareRegistry) Use(mw domain.Middleware) {
 	r.middlewares = append(r.middlewares, mw)
 }
 
+// UseSecured adds a security middleware to the chain and records its schemes.
+func (r *MiddlewareRegistry) UseSecured(mw domain.SecuredMiddleware) {
+	r.middlewares = append(r.middlewares, mw.Middleware)
+	r.security = append(r.security, mw.Schemes...)
+}
+
 // Apply applies all middleware to a handler in order.
 // Functional: Composes middleware functions
 func (r *MiddlewareRegistry) Apply(handler domain.HandlerFunc) domain.HandlerFunc {
@@ -33,3 +40,12 @@ func (r *MiddlewareRegistry) Apply(handler domain.HandlerFunc) domain.HandlerFun
 	return result
 }
 
+// GetMiddlewares returns all registered middlewares in order.
+func (r *MiddlewareRegistry) GetMiddlewares() []domain.Middleware {
+	return r.middlewares
+}
+
+// GetSecurity returns the schemes of the middlewares added with UseSecured.
+func (r *MiddlewareRegistry) GetSecurity() []domain.SecurityScheme {
+	return r.security
+}
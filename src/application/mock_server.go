@@ -0,0 +1,313 @@
+package application
+
+import (
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// MockStatusHeader lets a client force a response status in mock mode,
+// e.g. "X-Mock-Status: 404" to build error screens.
+const MockStatusHeader = "X-Mock-Status"
+
+// mockMaxDepth stops synthesis of recursive schemas.
+const mockMaxDepth = 6
+
+// mockWords are used for synthesized strings.
+var mockWords = []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel", "india", "juliet"}
+
+// MockServer answers every operation of a spec with synthesized responses.
+// Bodies are derived from the seed and the request's path and query parameters, so
+// the same URL returns the same data; latency and error injection draw from a shared seeded source.
+type MockServer struct {
+	spec   map[string]interface{}
+	config domain.MockConfig
+
+	mu    sync.Mutex
+	noise *rand.Rand // Latency jitter and error injection
+}
+
+// NewMockServer creates a mock server for a decoded OpenAPI document.
+func NewMockServer(spec map[string]interface{}, config domain.MockConfig) *MockServer {
+	if config.ErrorStatus == 0 {
+		config.ErrorStatus = 500
+	}
+	return &MockServer{
+		spec:   spec,
+		config: config,
+		noise:  rand.New(rand.NewSource(config.Seed)),
+	}
+}
+
+// Register adds a mock route for every operation in the spec.
+// "{id}" path parameters become ":id" routes.
+func (m *MockServer) Register(registry *RouteRegistry) error {
+	operations := specOperations(m.spec)
+	for _, key := range sortedOperationKeys(operations) {
+		parts := strings.SplitN(key, " ", 2)
+		method, path := parts[0], strings.NewReplacer("{", ":", "}", "").Replace(parts[1])
+		if err := registry.Register(method, path, m.handler(key, operations[key]), domain.RouteOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handler returns the mock handler of one operation ("GET /users/{id}").
+func (m *MockServer) handler(operation string, op map[string]interface{}) domain.HandlerFunc {
+	status, response := successResponse(m.spec, op)
+	return func(c *domain.Context) error {
+		m.delay()
+
+		// Guard clause: forced or injected errors
+		if forced, err := strconv.Atoi(c.Header(MockStatusHeader)); err == nil && forced >= 400 {
+			return domain.NewHTTPException(forced, fmt.Sprintf("mock %d response", forced))
+		}
+		if m.injectError() {
+			return domain.NewHTTPException(m.config.ErrorStatus, "mock error")
+		}
+
+		if response == nil {
+			return c.JSON(status, nil)
+		}
+
+		// Per-request source: the same seed and URL give the same body
+		rnd := rand.New(rand.NewSource(m.config.Seed ^ requestSeed(operation, c)))
+		return c.JSON(status, m.example(response, rnd))
+	}
+}
+
+// example returns the response example, or synthesizes one from the schema.
+func (m *MockServer) example(media map[string]interface{}, rnd *rand.Rand) interface{} {
+	if value, ok := media["example"]; ok {
+		return value
+	}
+	if examples := asObject(media["examples"]); len(examples) > 0 {
+		names := sortedMapKeys(examples)
+		return asObject(examples[names[rnd.Intn(len(names))]])["value"]
+	}
+	return m.value(asObject(media["schema"]), rnd, 0)
+}
+
+// value synthesizes a JSON value that fits a schema.
+func (m *MockServer) value(schema map[string]interface{}, rnd *rand.Rand, depth int) interface{} {
+	schema = resolveSpecRef(m.spec, schema)
+	if schema == nil || depth > mockMaxDepth {
+		return nil
+	}
+
+	if value, ok := schema["example"]; ok {
+		return value
+	}
+	if value, ok := schema["default"]; ok {
+		return value
+	}
+	if values, ok := schema["enum"].([]interface{}); ok && len(values) > 0 {
+		return values[rnd.Intn(len(values))]
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if variants, ok := schema[key].([]interface{}); ok && len(variants) > 0 {
+			return m.value(asObject(variants[rnd.Intn(len(variants))]), rnd, depth+1)
+		}
+	}
+	if _, ok := schema["allOf"]; ok {
+		return m.value(flattenSchema(m.spec, schema), rnd, depth)
+	}
+
+	switch schemaTypeName(schema) {
+	case "string":
+		return mockString(schema, rnd)
+	case "integer":
+		low, high := mockBounds(schema, 1, 1000)
+		span := int64(math.Floor(high) - math.Ceil(low))
+		if span < 0 {
+			return int64(math.Ceil(low))
+		}
+		return int64(math.Ceil(low)) + rnd.Int63n(span+1)
+	case "number":
+		low, high := mockBounds(schema, 0, 1000)
+		return math.Round((low+rnd.Float64()*(high-low))*100) / 100
+	case "boolean":
+		return rnd.Intn(2) == 1
+	case "array":
+		count := mockCount(schema, "minItems", "maxItems", 1, 3, rnd)
+		items := make([]interface{}, 0, count)
+		for i := 0; i < count && depth < mockMaxDepth; i++ {
+			items = append(items, m.value(asObject(schema["items"]), rnd, depth+1))
+		}
+		return items
+	}
+
+	// Objects: every declared property, plus a few keys for maps
+	object := map[string]interface{}{}
+	properties := asObject(schema["properties"])
+	for _, name := range sortedMapKeys(properties) {
+		if value := m.value(asObject(properties[name]), rnd, depth+1); value != nil {
+			object[name] = value
+		}
+	}
+	if additional := asObject(schema["additionalProperties"]); additional != nil {
+		for i := 1; i <= 2; i++ {
+			object["key"+strconv.Itoa(i)] = m.value(additional, rnd, depth+1)
+		}
+	}
+	if len(properties) == 0 && schemaTypeName(schema) != "object" && asObject(schema["additionalProperties"]) == nil {
+		return nil
+	}
+	return object
+}
+
+// delay sleeps for the configured latency plus jitter.
+func (m *MockServer) delay() {
+	latency := m.config.Latency
+	if m.config.Jitter > 0 {
+		m.mu.Lock()
+		latency += time.Duration(m.noise.Int63n(int64(m.config.Jitter)))
+		m.mu.Unlock()
+	}
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+}
+
+// injectError reports whether this request fails, following ErrorRate.
+func (m *MockServer) injectError() bool {
+	if m.config.ErrorRate <= 0 {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.noise.Float64() < m.config.ErrorRate
+}
+
+// successResponse returns the lowest 2xx status of an operation and its JSON media object.
+func successResponse(spec map[string]interface{}, op map[string]interface{}) (int, map[string]interface{}) {
+	responses := asObject(op["responses"])
+	for _, status := range sortedMapKeys(responses) {
+		code, err := strconv.Atoi(status)
+		if err != nil || code < 200 || code > 299 {
+			continue
+		}
+		content := asObject(resolveSpecRef(spec, responses[status])["content"])
+		if media := asObject(content["application/json"]); media != nil {
+			return code, media
+		}
+		for _, mediaType := range sortedMapKeys(content) {
+			return code, asObject(content[mediaType])
+		}
+		return code, nil
+	}
+	return 200, nil
+}
+
+// requestSeed hashes the operation with the request's path and query parameters.
+func requestSeed(operation string, c *domain.Context) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(operation))
+	for _, values := range []map[string]string{c.Params, c.QueryParams} {
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			hash.Write([]byte("|" + key + "=" + values[key]))
+		}
+	}
+	return int64(hash.Sum64())
+}
+
+// mockString synthesizes a string respecting format and length constraints.
+func mockString(schema map[string]interface{}, rnd *rand.Rand) string {
+	n := rnd.Intn(10000)
+	switch schema["format"] {
+	case "date-time":
+		return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(n) * time.Hour).Format(time.RFC3339)
+	case "date":
+		return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, n%365).Format("2006-01-02")
+	case "email":
+		return fmt.Sprintf("user%d@example.com", n)
+	case "uri", "url":
+		return fmt.Sprintf("https://example.com/%d", n)
+	case "uuid":
+		b := make([]byte, 16)
+		rnd.Read(b)
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	case "byte":
+		return base64.StdEncoding.EncodeToString([]byte(mockWords[n%len(mockWords)]))
+	}
+
+	words := []string{}
+	for i := mockCount(schema, "", "", 1, 3, rnd); i > 0; i-- {
+		words = append(words, mockWords[rnd.Intn(len(mockWords))])
+	}
+	text := strings.Join(words, " ")
+
+	// Fit minLength/maxLength
+	if min, ok := schema["minLength"].(float64); ok {
+		for len(text) < int(min) {
+			text += " " + mockWords[rnd.Intn(len(mockWords))]
+		}
+	}
+	if max, ok := schema["maxLength"].(float64); ok && len(text) > int(max) {
+		text = text[:int(max)]
+	}
+	return text
+}
+
+// mockBounds returns the numeric range of a schema (3.0 and 3.1 exclusive bounds).
+func mockBounds(schema map[string]interface{}, low, high float64) (float64, float64) {
+	if min, ok := schema["minimum"].(float64); ok {
+		low = min
+		if exclusive, _ := schema["exclusiveMinimum"].(bool); exclusive {
+			low++
+		}
+		if high < low {
+			high = low + 1000
+		}
+	}
+	if min, ok := schema["exclusiveMinimum"].(float64); ok {
+		low = min + 1
+	}
+	if max, ok := schema["maximum"].(float64); ok {
+		high = max
+		if exclusive, _ := schema["exclusiveMaximum"].(bool); exclusive {
+			high--
+		}
+	}
+	if max, ok := schema["exclusiveMaximum"].(float64); ok {
+		high = max - 1
+	}
+	if low > high {
+		low = high
+	}
+	return low, high
+}
+
+// mockCount picks a count within a schema's min/max keywords.
+func mockCount(schema map[string]interface{}, minKey, maxKey string, low, high int, rnd *rand.Rand) int {
+	if min, ok := schema[minKey].(float64); ok {
+		low = int(min)
+		if high < low {
+			high = low
+		}
+	}
+	if max, ok := schema[maxKey].(float64); ok {
+		high = int(max)
+		if low > high {
+			low = high
+		}
+	}
+	return low + rnd.Intn(high-low+1)
+}
@@ -1,172 +1,592 @@
 package application
 
 import (
+	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/syntropysoft/syntrogo/src/domain"
 )
 
-// OpenAPIGenerator generates OpenAPI 3.0 specifications from routes.
+// formFileType marks file fields of Form structs.
+var formFileType = reflect.TypeOf((*domain.FormFile)(nil))
+
+// OpenAPIGenerator generates OpenAPI 3.0 and 3.1 specifications from routes.
 // SOLID: Single Responsibility - only generates OpenAPI
 // Reflection-based: Reads struct tags to infer schemas
 type OpenAPIGenerator struct {
-	routes []*domain.Route
+	routes            []*domain.Route
+	globalSecurity    []domain.SecurityScheme
+	config            *domain.AppConfig
+	schemas           *SchemaBuilder
+	openAPIVersion    string
+	mediaTypes        []string // Body media types, from the registered codecs
 }
 
 // NewOpenAPIGenerator creates a new OpenAPI generator.
 func NewOpenAPIGenerator(routes []*domain.Route) *OpenAPIGenerator {
 	return &OpenAPIGenerator{
-		routes: routes,
+		routes:         routes,
+		schemas:        NewSchemaBuilder(),
+		openAPIVersion: domain.OpenAPI30,
+		mediaTypes:     []string{"application/json"},
+	}
+}
+
+// SetMediaTypes sets the media types listed for request and response bodies.
+func (g *OpenAPIGenerator) SetMediaTypes(mediaTypes []string) {
+	// Guard clause: keep JSON when no codecs are known
+	if len(mediaTypes) == 0 {
+		return
 	}
+	g.mediaTypes = mediaTypes
 }
 
-// Generate creates the OpenAPI 3.0 specification.
+// SetOpenAPIVersion selects the OpenAPI version of the generated document.
+// Supported: domain.OpenAPI30 (default) and domain.OpenAPI31.
+func (g *OpenAPIGenerator) SetOpenAPIVersion(version string) {
+	// Guard clause: keep the default for empty versions
+	if version == "" {
+		return
+	}
+	g.openAPIVersion = version
+	g.schemas.SetOpenAPIVersion(version)
+}
+
+// Generate creates the OpenAPI specification.
 // Uses reflection to infer schemas from struct tags
 func (g *OpenAPIGenerator) Generate(title, version string) (map[string]interface{}, error) {
 	spec := map[string]interface{}{
-		"openapi": "3.0.0",
-		"info": map[string]interface{}{
-			"title":   title,
-			"version": version,
-		},
-		"paths": map[string]interface{}{},
+		"openapi": g.openAPIVersion,
+		"info":    g.generateInfo(title, version),
+		"paths":   map[string]interface{}{},
+	}
+
+	if servers := g.generateServers(); len(servers) > 0 {
+		spec["servers"] = servers
+	}
+	if tags := g.generateTags(); len(tags) > 0 {
+		spec["tags"] = tags
 	}
 
-	// Add each route to the spec
+	// Add each route to the spec, grouping operations by path
+	pathSpec := spec["paths"].(map[string]interface{})
+	schemes := map[string]interface{}{}
 	for _, route := range g.routes {
-		pathSpec := spec["paths"].(map[string]interface{})
-		pathKey := strings.ToLower(route.Method) + " " + route.Path
-		
-		pathSpec[pathKey] = g.generatePathItem(route)
+		// Guard clause: routes that are not part of the API
+		if route.Options.Hidden {
+			continue
+		}
+		path := g.openAPIPath(route.Path)
+		pathItem, ok := pathSpec[path].(map[string]interface{})
+		if !ok {
+			pathItem = map[string]interface{}{}
+			pathSpec[path] = pathItem
+		}
+
+		operation := g.generatePathItem(route)
+		if parameters := g.generateParameters(route); len(parameters) > 0 {
+			operation["parameters"] = parameters
+		}
+		if requirement := g.securityRequirement(route, schemes); requirement != nil {
+			operation["security"] = []interface{}{requirement}
+		}
+		pathItem[strings.ToLower(route.Method)] = operation
+	}
+
+	// Global security middlewares apply to every operation
+	if requirement := g.collectSchemes(g.globalSecurity, schemes); len(requirement) > 0 {
+		spec["security"] = []interface{}{requirement}
+	}
+
+	components := map[string]interface{}{}
+	if len(schemes) > 0 {
+		components["securitySchemes"] = schemes
+	}
+	if schemas := g.schemas.Components(); len(schemas) > 0 {
+		components["schemas"] = schemas
+	}
+	if len(components) > 0 {
+		spec["components"] = components
 	}
 
 	return spec, nil
 }
 
-// generatePathItem creates the OpenAPI path item for a route.
+// SetAppConfig sets the app configuration used for info, servers and tags.
+func (g *OpenAPIGenerator) SetAppConfig(config *domain.AppConfig) {
+	g.config = config
+}
+
+// generateInfo creates the OpenAPI info object.
+func (g *OpenAPIGenerator) generateInfo(title, version string) map[string]interface{} {
+	info := map[string]interface{}{
+		"title":   title,
+		"version": version,
+	}
+
+	// Guard clause: no app config, only title and version
+	if g.config == nil {
+		return info
+	}
+
+	if g.config.Description != "" {
+		info["description"] = g.config.Description
+	}
+	if contact := g.config.Contact; contact != nil {
+		info["contact"] = nonEmpty(map[string]interface{}{
+			"name":  contact.Name,
+			"url":   contact.URL,
+			"email": contact.Email,
+		})
+	}
+	if license := g.config.License; license != nil {
+		info["license"] = nonEmpty(map[string]interface{}{
+			"name": license.Name,
+			"url":  license.URL,
+		})
+	}
+	return info
+}
+
+// generateServers creates the OpenAPI servers list.
+func (g *OpenAPIGenerator) generateServers() []interface{} {
+	if g.config == nil {
+		return nil
+	}
+
+	servers := []interface{}{}
+	for _, server := range g.config.Servers {
+		servers = append(servers, nonEmpty(map[string]interface{}{
+			"url":         server.URL,
+			"description": server.Description,
+		}))
+	}
+	return servers
+}
+
+// generateTags creates the top-level tag descriptions.
+func (g *OpenAPIGenerator) generateTags() []interface{} {
+	if g.config == nil {
+		return nil
+	}
+
+	tags := []interface{}{}
+	for _, tag := range g.config.Tags {
+		object := nonEmpty(map[string]interface{}{
+			"name":        tag.Name,
+			"description": tag.Description,
+		})
+		if tag.ExternalDocs != nil {
+			object["externalDocs"] = g.externalDocsObject(tag.ExternalDocs)
+		}
+		tags = append(tags, object)
+	}
+	return tags
+}
+
+// externalDocsObject converts external docs to their OpenAPI form.
+func (g *OpenAPIGenerator) externalDocsObject(docs *domain.ExternalDocs) map[string]interface{} {
+	return nonEmpty(map[string]interface{}{
+		"url":         docs.URL,
+		"description": docs.Description,
+	})
+}
+
+// nonEmpty drops empty string values so optional fields are omitted from the spec.
+func nonEmpty(object map[string]interface{}) map[string]interface{} {
+	for key, value := range object {
+		if str, ok := value.(string); ok && str == "" {
+			delete(object, key)
+		}
+	}
+	return object
+}
+
+// SetGlobalSecurity sets the schemes enforced by app-wide middlewares.
+// They become the spec's top-level security.
+func (g *OpenAPIGenerator) SetGlobalSecurity(schemes []domain.SecurityScheme) {
+	g.globalSecurity = schemes
+}
+
+// securityRequirement builds the security requirement for a route.
+// Global schemes are repeated because an operation-level requirement replaces the top-level one.
+// Returns nil when the route is not protected.
+func (g *OpenAPIGenerator) securityRequirement(route *domain.Route, schemes map[string]interface{}) map[string]interface{} {
+	routeRequirement := g.collectSchemes(route.Options.Security, schemes)
+
+	// Guard clause: no route-level security, top-level security applies
+	if len(routeRequirement) == 0 {
+		return nil
+	}
+
+	// Middlewares are chained, so every scheme must be satisfied (AND)
+	for name, scopes := range g.collectSchemes(g.globalSecurity, schemes) {
+		routeRequirement[name] = scopes
+	}
+	return routeRequirement
+}
+
+// collectSchemes registers the given schemes in components and returns them
+// as a single security requirement object.
+func (g *OpenAPIGenerator) collectSchemes(security []domain.SecurityScheme, schemes map[string]interface{}) map[string]interface{} {
+	requirement := map[string]interface{}{}
+	for _, scheme := range security {
+		schemes[scheme.Name] = g.securitySchemeObject(scheme)
+		requirement[scheme.Name] = []string{}
+	}
+	return requirement
+}
+
+// securitySchemeObject converts a domain security scheme to its OpenAPI form.
+func (g *OpenAPIGenerator) securitySchemeObject(scheme domain.SecurityScheme) map[string]interface{} {
+	object := map[string]interface{}{
+		"type": scheme.Type,
+	}
+	if scheme.Description != "" {
+		object["description"] = scheme.Description
+	}
+	if scheme.Scheme != "" {
+		object["scheme"] = scheme.Scheme
+	}
+	if scheme.BearerFormat != "" {
+		object["bearerFormat"] = scheme.BearerFormat
+	}
+	if scheme.In != "" {
+		object["in"] = scheme.In
+	}
+	if scheme.ParamName != "" {
+		object["name"] = scheme.ParamName
+	}
+	return object
+}
+
+// generatePathItem creates the OpenAPI operation for a route.
 func (g *OpenAPIGenerator) generatePathItem(route *domain.Route) map[string]interface{} {
-	item := map[string]interface{}{
-		"summary": route.Options.Summary,
-		"tags":    route.Options.Tags,
+	item := nonEmpty(map[string]interface{}{
+		"summary":     route.Options.Summary,
+		"description": route.Options.Description,
+		"operationId": route.Options.OperationID,
+	})
+
+	if len(route.Options.Tags) > 0 {
+		item["tags"] = route.Options.Tags
+	}
+	if route.Options.Deprecated {
+		item["deprecated"] = true
+	}
+	if route.Options.ExternalDocs != nil {
+		item["externalDocs"] = g.externalDocsObject(route.Options.ExternalDocs)
 	}
 
-	// Add request body if defined
+	// Guard clause: WebSocket routes document the handshake and their messages
+	if route.Options.WebSocket != nil {
+		return g.webSocketOperation(route, item)
+	}
+
+	// Add request body if defined (JSON and/or form)
+	content := map[string]interface{}{}
 	if route.Options.Body != nil {
+		media := map[string]interface{}{
+			"schema": g.inferSchemaFromStruct(route.Options.Body),
+		}
+		if route.Options.RequestExample != nil {
+			media["example"] = route.Options.RequestExample
+		}
+		mediaTypes := g.mediaTypes
+		if route.Options.BodyStream != "" {
+			mediaTypes = []string{route.Options.BodyStream} // Body describes each streamed item
+		}
+		for _, mediaType := range mediaTypes {
+			content[mediaType] = media
+		}
+	}
+	if route.Options.Form != nil {
+		mediaType, media := g.formBody(route)
+		content[mediaType] = media
+	}
+	if len(content) > 0 {
 		item["requestBody"] = map[string]interface{}{
 			"required": true,
-			"content": map[string]interface{}{
-				"application/json": map[string]interface{}{
-					"schema": g.inferSchemaFromStruct(route.Options.Body),
-				},
-			},
+			"content":  content,
 		}
 	}
 
-	// Add response if defined
-	if route.Options.Response != nil {
-		item["responses"] = map[string]interface{}{
-			"200": map[string]interface{}{
-				"description": "Success",
-				"content": map[string]interface{}{
-					"application/json": map[string]interface{}{
-						"schema": g.inferSchemaFromStruct(route.Options.Response),
-					},
-				},
-			},
+	// Add response, OpenAPI requires at least one
+	status := route.Options.ResponseStatus
+	if status == 0 {
+		status = 200
+	}
+	response := map[string]interface{}{
+		"description": "Success",
+	}
+	if route.Options.Stream != "" {
+		response["content"] = map[string]interface{}{
+			route.Options.Stream: map[string]interface{}{"schema": g.streamSchema(route)},
+		}
+	} else if route.Options.Response != nil {
+		media := map[string]interface{}{
+			"schema": g.inferSchemaFromStruct(route.Options.Response),
+		}
+		if route.Options.ResponseExample != nil {
+			media["example"] = route.Options.ResponseExample
+		}
+		content := map[string]interface{}{}
+		for _, mediaType := range g.mediaTypes {
+			content[mediaType] = media
+		}
+		response["content"] = content
+	}
+	responses := map[string]interface{}{
+		strconv.Itoa(status): response,
+	}
+	if route.Options.Timeout > 0 {
+		responses["504"] = map[string]interface{}{
+			"description": fmt.Sprintf("Not completed within %s", route.Options.Timeout),
 		}
 	}
+	item["responses"] = responses
 
 	return item
 }
 
-// addRouteToSpec adds a route to the OpenAPI specification.
-func (g *OpenAPIGenerator) addRouteToSpec(spec map[string]interface{}, route *domain.Route) {
-	// TODO: Add route to OpenAPI spec
-	// Uses reflection to infer body/response schemas
+// webSocketOperation completes the operation of a WebSocket route: a 101 response,
+// with the Body and Response message schemas under the x-websocket extension
+// since OpenAPI has no way to describe them.
+func (g *OpenAPIGenerator) webSocketOperation(route *domain.Route, item map[string]interface{}) map[string]interface{} {
+	item["responses"] = map[string]interface{}{
+		"101": map[string]interface{}{
+			"description": "Switching Protocols: the connection continues as a WebSocket",
+		},
+	}
+
+	messages := map[string]interface{}{}
+	if route.Options.Body != nil {
+		messages["clientMessage"] = g.inferSchemaFromStruct(route.Options.Body)
+	}
+	if route.Options.Response != nil {
+		messages["serverMessage"] = g.inferSchemaFromStruct(route.Options.Response)
+	}
+	if len(route.Options.WebSocket.Subprotocols) > 0 {
+		messages["subprotocols"] = route.Options.WebSocket.Subprotocols
+	}
+	if len(messages) > 0 {
+		item["x-websocket"] = messages
+	}
+	return item
 }
 
-// inferSchemaFromStruct uses reflection to infer OpenAPI schema from struct tags.
-func (g *OpenAPIGenerator) inferSchemaFromStruct(structType interface{}) map[string]interface{} {
+// streamSchema documents a streamed response. Server-Sent Events are described
+// event by event, with Response as the data; other streams carry Response chunks
+// or raw bytes.
+func (g *OpenAPIGenerator) streamSchema(route *domain.Route) map[string]interface{} {
+	if route.Options.Stream != domain.EventStreamType {
+		if route.Options.Response != nil {
+			return g.inferSchemaFromStruct(route.Options.Response)
+		}
+		return map[string]interface{}{"type": "string", "format": "binary"}
+	}
+
+	data := map[string]interface{}{"type": "string"}
+	if route.Options.Response != nil {
+		data = g.inferSchemaFromStruct(route.Options.Response)
+	}
+	return map[string]interface{}{
+		"type":        "object",
+		"description": "A stream of Server-Sent Events, each with these fields",
+		"properties": map[string]interface{}{
+			"id":    map[string]interface{}{"type": "string"},
+			"event": map[string]interface{}{"type": "string"},
+			"data":  data,
+			"retry": map[string]interface{}{"type": "integer", "description": "Reconnection delay in milliseconds"},
+		},
+		"required": []string{"data"},
+	}
+}
+
+// openAPIPath converts router path parameters (/users/:id) to OpenAPI templates (/users/{id}).
+func (g *OpenAPIGenerator) openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// generateParameters creates the OpenAPI parameters for path, query, header and cookie values.
+func (g *OpenAPIGenerator) generateParameters(route *domain.Route) []interface{} {
+	parameters := []interface{}{}
+
+	// Path parameters are always required
+	for _, segment := range strings.Split(route.Path, "/") {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		name := segment[1:]
+		parameters = append(parameters, map[string]interface{}{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   g.pathParamSchema(route.Options.Params[name]),
+		})
+	}
+
+	parameters = append(parameters, g.structParameters(route.Options.Query, "query")...)
+	parameters = append(parameters, g.structParameters(route.Options.Headers, "header")...)
+	parameters = append(parameters, g.structParameters(route.Options.Cookies, "cookie")...)
+	return parameters
+}
+
+// pathParamSchema builds the schema for a path parameter declared with Params.
+func (g *OpenAPIGenerator) pathParamSchema(spec domain.ParamSpec) map[string]interface{} {
+	schema := map[string]interface{}{
+		"type": "string",
+	}
+	if spec.Type != "" {
+		schema["type"] = spec.Type
+	}
+	if spec.Min != nil {
+		schema["minimum"] = *spec.Min
+	}
+	if spec.Max != nil {
+		schema["maximum"] = *spec.Max
+	}
+	return schema
+}
+
+// structParameters reads `query`, `header` or `cookie` tags and creates OpenAPI parameters.
+// The same tag (location) is used by ParamBinder to fill the struct at runtime.
+func (g *OpenAPIGenerator) structParameters(structType interface{}, location string) []interface{} {
+	// Guard clause: nothing declared
+	if structType == nil {
+		return nil
+	}
+
 	t := reflect.TypeOf(structType)
-	
-	// If it's a pointer, get the element type
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
-
 	if t.Kind() != reflect.Struct {
-		// For non-structs, return basic type
-		return map[string]interface{}{
-			"type": g.goTypeToOpenAPIType(t.Kind().String()),
-		}
+		return nil
 	}
 
-	// For slices/arrays
-	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
-		return map[string]interface{}{
-			"type": "array",
-			"items": g.inferSchemaFromStruct(t.Elem()),
+	parameters := []interface{}{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := ParamName(field, location)
+		if name == "" {
+			continue
+		}
+
+		schema := g.schemas.Build(field.Type)
+		g.schemas.ApplyField(schema, t, field)
+
+		parameter := map[string]interface{}{
+			"name":     name,
+			"in":       location,
+			"required": hasValidateRule(field.Tag.Get("validate"), "required"),
+			"schema":   schema,
 		}
+
+		// Descriptions belong to the parameter, not only its schema
+		if description, ok := schema["description"]; ok {
+			parameter["description"] = description
+		}
+		parameters = append(parameters, parameter)
+	}
+	return parameters
+}
+
+// formBody builds the request body for a Form struct: multipart/form-data when it
+// has *FormFile fields, application/x-www-form-urlencoded otherwise.
+func (g *OpenAPIGenerator) formBody(route *domain.Route) (string, map[string]interface{}) {
+	t := reflect.TypeOf(route.Options.Form)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
 	}
 
 	properties := map[string]interface{}{}
 	required := []string{}
-
-	// Iterate through struct fields
+	encoding := map[string]interface{}{}
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
-		
-		// Get json tag
-		jsonTag := field.Tag.Get("json")
-		if jsonTag == "" || jsonTag == "-" {
+		name := ParamName(field, "form")
+		if name == "" {
 			continue
 		}
 
-		// Remove omitempty
-		jsonTag = strings.Split(jsonTag, ",")[0]
-
-		// Infer property type
-		prop := map[string]interface{}{
-			"type": g.goTypeToOpenAPIType(field.Type.Kind().String()),
+		var schema map[string]interface{}
+		switch field.Type {
+		case formFileType:
+			schema = map[string]interface{}{"type": "string", "format": "binary"}
+		case reflect.SliceOf(formFileType):
+			schema = map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string", "format": "binary"},
+			}
+		default:
+			schema = g.schemas.Build(field.Type)
+			g.schemas.ApplyField(schema, t, field)
 		}
 
-		// Check for validation tags
-		validateTag := field.Tag.Get("validate")
-		if strings.Contains(validateTag, "required") {
-			required = append(required, jsonTag)
+		// Files declare their accepted types in the encoding object
+		upload := route.Options.Upload
+		if isFormFile(field.Type) && upload != nil && len(upload.AllowedTypes) > 0 {
+			encoding[name] = map[string]interface{}{
+				"contentType": strings.Join(upload.AllowedTypes, ", "),
+			}
 		}
 
-		properties[jsonTag] = prop
+		properties[name] = schema
+		if hasValidateRule(field.Tag.Get("validate"), "required") {
+			required = append(required, name)
+		}
 	}
 
 	schema := map[string]interface{}{
 		"type":       "object",
 		"properties": properties,
 	}
-
 	if len(required) > 0 {
 		schema["required"] = required
 	}
 
-	return schema
+	media := map[string]interface{}{"schema": schema}
+	if len(encoding) > 0 {
+		media["encoding"] = encoding
+	}
+	if formHasFiles(t) {
+		return "multipart/form-data", media
+	}
+	return "application/x-www-form-urlencoded", media
 }
 
-// goTypeToOpenAPIType converts GO types to OpenAPI types.
-func (g *OpenAPIGenerator) goTypeToOpenAPIType(goType string) string {
-	switch goType {
-	case "string":
-		return "string"
-	case "int", "int8", "int16", "int32", "int64":
-		return "integer"
-	case "uint", "uint8", "uint16", "uint32", "uint64":
-		return "integer"
-	case "float32", "float64":
-		return "number"
-	case "bool":
-		return "boolean"
-	default:
-		return "string"
+// formHasFiles reports whether a form struct declares file fields.
+func formHasFiles(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if isFormFile(t.Field(i).Type) {
+			return true
+		}
 	}
+	return false
+}
+
+// isFormFile reports whether a field type is *FormFile or []*FormFile.
+func isFormFile(t reflect.Type) bool {
+	return t == formFileType || t == reflect.SliceOf(formFileType)
+}
+
+// addRouteToSpec adds a route to the OpenAPI specification.
+func (g *OpenAPIGenerator) addRouteToSpec(spec map[string]interface{}, route *domain.Route) {
+	// TODO: Add route to OpenAPI spec
+	// Uses reflection to infer body/response schemas
+}
+
+// inferSchemaFromStruct uses reflection to infer OpenAPI schema from struct tags.
+func (g *OpenAPIGenerator) inferSchemaFromStruct(structType interface{}) map[string]interface{} {
+	return g.schemas.Build(reflect.TypeOf(structType))
 }
 
@@ -0,0 +1,191 @@
+package application
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// ParamBinder fills structs from string values using struct tags.
+// SOLID: Single Responsibility - only converts strings to typed fields
+// Reflection-based: Reads `path`, `query`, `header` and `cookie` tags, plus `default`
+//
+// Supported fields: strings, numbers, bools, time.Time (RFC 3339 or 2006-01-02),
+// time.Duration ("1m30s"), encoding.TextUnmarshaler, slices of those (repeated
+// keys) and pointers, which stay nil when the parameter is absent.
+type ParamBinder struct{}
+
+// NewParamBinder creates a new parameter binder.
+func NewParamBinder() *ParamBinder {
+	return &ParamBinder{}
+}
+
+// Bind fills the fields of v tagged with tag using values from lookup.
+// lookup returns every value sent for a name (repeated query keys, headers, etc.).
+func (b *ParamBinder) Bind(v interface{}, tag string, lookup func(name string) []string) error {
+	// Guard clause: v must be a non-nil pointer to a struct
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return domain.NewHTTPException(500, "bind target must be a pointer to a struct")
+	}
+
+	target := rv.Elem()
+	for i, field := range TypeInfoOf(target.Type()).Fields {
+		name := field.Param(tag)
+		if name == "" || !field.IsExported() {
+			continue
+		}
+
+		values := lookup(name)
+		if len(values) == 0 {
+			if !field.HasDefault {
+				continue
+			}
+			values = defaultValues(field.Type, field.Default)
+		}
+
+		if err := b.setField(target.Field(i), values); err != nil {
+			return domain.NewHTTPException(400, fmt.Sprintf("invalid %s parameter %q: %s", tag, name, err.Error()))
+		}
+	}
+
+	return nil
+}
+
+// ParamName returns the parameter name declared by a struct tag.
+// Returns "" when the field has no tag or is explicitly skipped with "-".
+func ParamName(field reflect.StructField, tag string) string {
+	name := strings.Split(field.Tag.Get(tag), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// defaultValues splits a `default` tag: slices take a JSON array or comma-separated values.
+func defaultValues(t reflect.Type, value string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Slice || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return []string{value}
+	}
+
+	var items []interface{}
+	if err := json.Unmarshal([]byte(value), &items); err == nil {
+		values := make([]string, len(items))
+		for i, item := range items {
+			values[i] = fmt.Sprint(item)
+		}
+		return values
+	}
+	return strings.Split(value, ",")
+}
+
+// setField converts values and assigns them to a struct field.
+func (b *ParamBinder) setField(field reflect.Value, values []string) error {
+	// Pointers are allocated only when a value is present
+	if field.Kind() == reflect.Ptr {
+		target := reflect.New(field.Type().Elem())
+		if err := b.setField(target.Elem(), values); err != nil {
+			return err
+		}
+		field.Set(target)
+		return nil
+	}
+
+	// Slices collect every value, other kinds take the first one
+	if field.Kind() == reflect.Slice && !isTextField(field) {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := b.setField(slice.Index(i), []string{value}); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+
+	return b.setScalar(field, values[0])
+}
+
+// setScalar converts a single string to the field's kind.
+func (b *ParamBinder) setScalar(field reflect.Value, value string) error {
+	switch field.Type() {
+	case timeType:
+		t, err := parseTime(value)
+		if err != nil {
+			return fmt.Errorf("expected RFC 3339 date-time or date")
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("expected duration such as 1m30s")
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	// Types that parse themselves (net.IP, uuid.UUID, custom enums, ...)
+	if isTextField(field) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected integer")
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected unsigned integer")
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected number")
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("expected boolean")
+		}
+		field.SetBool(v)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// isTextField reports whether an addressable field implements encoding.TextUnmarshaler.
+func isTextField(field reflect.Value) bool {
+	return field.CanAddr() && reflect.PointerTo(field.Type()).Implements(textUnmarshalerType)
+}
+
+// parseTime accepts RFC 3339 date-times and plain dates.
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
@@ -1,6 +1,8 @@
 package application
 
 import (
+	"strings"
+
 	"github.com/syntropysoft/syntrogo/src/domain"
 )
 
@@ -45,16 +47,94 @@ func (r *RouteRegistry) Register(method, path string, handler domain.HandlerFunc
 // Find returns a route matching the method and path.
 // Returns nil if not found.
 func (r *RouteRegistry) Find(method, path string) *domain.Route {
+	route, _ := r.Match(method, path)
+	return route
+}
+
+// Match returns the route for a method and path along with its path parameters.
+// ":name" segments capture one segment and a final "*name" segment captures the
+// rest of the path. When several routes match, routes without "*name" win, then
+// the one with the most leading literal segments, so /users/me beats /users/:id
+// and /admin/api/users beats /admin/*filepath.
+func (r *RouteRegistry) Match(method, path string) (*domain.Route, map[string]string) {
+	segments := splitPath(path)
+
+	var best *domain.Route
+	var bestParams map[string]string
+	bestScore, bestCatchAll := -1, true
 	for _, route := range r.routes {
-		if route.Method == method {
-			// TODO: Add pattern matching for path parameters like /users/:id
-			// For now, exact match only
-			if route.Path == path {
-				return route
+		if route.Method != method {
+			continue
+		}
+
+		// Fast path: exact match
+		if route.Path == path {
+			return route, map[string]string{}
+		}
+
+		params, score, catchAll, ok := matchSegments(splitPath(route.Path), segments)
+		if !ok {
+			continue
+		}
+		if (bestCatchAll && !catchAll) || (catchAll == bestCatchAll && score > bestScore) {
+			best, bestParams, bestScore, bestCatchAll = route, params, score, catchAll
+		}
+	}
+	return best, bestParams
+}
+
+// matchSegments matches route segments against request segments. The score counts
+// literal segments before the first parameter; catchAll reports a "*name" match.
+func matchSegments(pattern, segments []string) (map[string]string, int, bool, bool) {
+	// A final "*name" segment takes the remaining segments, possibly none
+	last := len(pattern) - 1
+	catchAll := strings.HasPrefix(pattern[last], "*")
+	if catchAll {
+		// Guard clause: too few segments before the catch-all
+		if len(segments) < last {
+			return nil, 0, false, false
+		}
+		rest := ""
+		if len(segments) > last {
+			rest = strings.Join(segments[last:], "/")
+		}
+		segments = append(segments[:last:last], rest)
+	}
+
+	// Guard clause: segment counts differ
+	if len(pattern) != len(segments) {
+		return nil, 0, false, false
+	}
+
+	params := map[string]string{}
+	score, literal := 0, true
+	for i, part := range pattern {
+		if catchAll && i == last {
+			params[part[1:]] = segments[i]
+			continue
+		}
+		if strings.HasPrefix(part, ":") {
+			// Guard clause: parameters never match empty segments
+			if segments[i] == "" {
+				return nil, 0, false, false
 			}
+			params[part[1:]] = segments[i]
+			literal = false
+			continue
+		}
+		if part != segments[i] {
+			return nil, 0, false, false
+		}
+		if literal {
+			score++
 		}
 	}
-	return nil
+	return params, score, catchAll, true
+}
+
+// splitPath splits a path into segments.
+func splitPath(path string) []string {
+	return strings.Split(strings.TrimPrefix(path, "/"), "/")
 }
 
 // GetRoutes returns all registered routes.
@@ -0,0 +1,483 @@
+package application
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SchemaBuilder builds JSON Schemas for Go types.
+// SOLID: Single Responsibility - only maps Go types to schemas
+// Reflection-based: Reads json, validate, doc, example, default, format and openapi tags
+type SchemaBuilder struct {
+	jsonSchema2020 bool                   // OpenAPI 3.1 uses JSON Schema 2020-12 keywords
+	components     map[string]interface{} // Named schemas referenced with $ref (union variants)
+}
+
+// NewSchemaBuilder creates a new schema builder.
+// Schemas follow OpenAPI 3.0 by default.
+func NewSchemaBuilder() *SchemaBuilder {
+	return &SchemaBuilder{
+		components: map[string]interface{}{},
+	}
+}
+
+// Components returns the named schemas referenced by built schemas,
+// to be published under components/schemas.
+func (b *SchemaBuilder) Components() map[string]interface{} {
+	return b.components
+}
+
+// SetOpenAPIVersion selects the schema dialect for an OpenAPI version.
+// 3.1.x emits JSON Schema 2020-12: `type: [.., "null"]` and numeric exclusive bounds.
+func (b *SchemaBuilder) SetOpenAPIVersion(version string) {
+	b.jsonSchema2020 = strings.HasPrefix(version, "3.1")
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Build returns the schema for a Go type.
+func (b *SchemaBuilder) Build(t reflect.Type) map[string]interface{} {
+	return b.build(t, map[reflect.Type]bool{})
+}
+
+// build walks a type, tracking visited structs to stop on recursive types.
+func (b *SchemaBuilder) build(t reflect.Type, visiting map[reflect.Type]bool) map[string]interface{} {
+	// Guard clause: untyped values accept anything
+	if t == nil {
+		return map[string]interface{}{}
+	}
+
+	// If it's a pointer, get the element type
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return map[string]interface{}{
+			"type":   "string",
+			"format": "date-time",
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return b.buildStruct(t, visiting)
+	case reflect.Slice, reflect.Array:
+		// []byte is encoded as base64 by encoding/json
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{
+				"type":   "string",
+				"format": "byte",
+			}
+		}
+		return map[string]interface{}{
+			"type":  "array",
+			"items": b.build(t.Elem(), visiting),
+		}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": b.build(t.Elem(), visiting),
+		}
+	case reflect.Interface:
+		if union := LookupUnion(t); union != nil {
+			return b.buildUnion(union, visiting)
+		}
+		return map[string]interface{}{}
+	}
+
+	schema := map[string]interface{}{
+		"type": b.goTypeToOpenAPIType(t.Kind().String()),
+	}
+	if format := b.numberFormat(t.Kind()); format != "" {
+		schema["format"] = format
+	}
+	return schema
+}
+
+// buildStruct creates an object schema from the exported, json-tagged fields of a struct.
+func (b *SchemaBuilder) buildStruct(t reflect.Type, visiting map[reflect.Type]bool) map[string]interface{} {
+	// Guard clause: recursive type, stop descending
+	if visiting[t] {
+		return map[string]interface{}{
+			"type": "object",
+		}
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	properties := map[string]interface{}{}
+	required := []string{}
+	b.collectFields(t, visiting, properties, &required)
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	// Named types keep their Go name so generators can emit named declarations
+	if t.Name() != "" {
+		schema["title"] = t.Name()
+	}
+	if doc := TypeDoc(t); doc != "" {
+		schema["description"] = doc
+	}
+
+	return schema
+}
+
+// buildUnion creates a oneOf schema with a discriminator for a registered union.
+// Variants are published as components so the discriminator mapping can reference them.
+func (b *SchemaBuilder) buildUnion(union *Union, visiting map[reflect.Type]bool) map[string]interface{} {
+	oneOf := []interface{}{}
+	mapping := map[string]interface{}{}
+	for _, value := range union.VariantNames() {
+		variant := union.Variants[value]
+		for variant.Kind() == reflect.Ptr {
+			variant = variant.Elem()
+		}
+
+		ref := "#/components/schemas/" + variant.Name()
+		if _, ok := b.components[variant.Name()]; !ok {
+			b.components[variant.Name()] = b.variantSchema(variant, union.Discriminator, value, visiting)
+		}
+
+		oneOf = append(oneOf, map[string]interface{}{"$ref": ref})
+		mapping[value] = ref
+	}
+
+	return map[string]interface{}{
+		"oneOf": oneOf,
+		"discriminator": map[string]interface{}{
+			"propertyName": union.Discriminator,
+			"mapping":      mapping,
+		},
+	}
+}
+
+// variantSchema builds a union variant with its discriminator property pinned to one value.
+func (b *SchemaBuilder) variantSchema(t reflect.Type, discriminator, value string, visiting map[reflect.Type]bool) map[string]interface{} {
+	schema := b.build(t, visiting)
+	if schema["type"] != "object" {
+		return schema
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	if properties == nil {
+		properties = map[string]interface{}{}
+		schema["properties"] = properties
+	}
+	properties[discriminator] = map[string]interface{}{
+		"type": "string",
+		"enum": []interface{}{value},
+	}
+
+	required, _ := schema["required"].([]string)
+	if !containsString(required, discriminator) {
+		schema["required"] = append(required, discriminator)
+	}
+	return schema
+}
+
+// containsString reports whether list contains value.
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// collectFields adds struct fields as properties, flattening embedded structs like encoding/json.
+func (b *SchemaBuilder) collectFields(t reflect.Type, visiting map[reflect.Type]bool, properties map[string]interface{}, required *[]string) {
+	for _, field := range TypeInfoOf(t).Fields {
+		if field.JSONSkip {
+			continue
+		}
+		jsonTag := field.Tag.Get("json")
+
+		// Embedded structs without a json name are flattened
+		embedded := field.Type
+		if embedded.Kind() == reflect.Ptr {
+			embedded = embedded.Elem()
+		}
+		if field.Anonymous && jsonTag == "" && embedded.Kind() == reflect.Struct {
+			b.collectFields(embedded, visiting, properties, required)
+			continue
+		}
+
+		if jsonTag == "" || !field.IsExported() {
+			continue
+		}
+
+		prop := b.build(field.Type, visiting)
+		b.ApplyField(prop, t, field.StructField)
+
+		if field.Required {
+			*required = append(*required, field.JSONName)
+		}
+
+		properties[field.JSONName] = prop
+	}
+}
+
+// ApplyField adds field-level metadata to a schema: constraints from validate tags,
+// description, example, default, format, nullable, readOnly and writeOnly.
+// owner is the struct declaring the field, used to look up doc comments.
+func (b *SchemaBuilder) ApplyField(schema map[string]interface{}, owner reflect.Type, field reflect.StructField) {
+	b.applyConstraints(schema, field.Type, field.Tag.Get("validate"))
+
+	// doc tag wins over generated doc comments
+	if doc := field.Tag.Get("doc"); doc != "" {
+		schema["description"] = doc
+	} else if doc := FieldDoc(owner, field.Name); doc != "" {
+		schema["description"] = doc
+	}
+
+	if format := field.Tag.Get("format"); format != "" {
+		schema["format"] = format
+	}
+	if example, ok := field.Tag.Lookup("example"); ok {
+		schema["example"] = b.tagValue(field.Type, example)
+	}
+	if def, ok := field.Tag.Lookup("default"); ok {
+		schema["default"] = b.tagValue(field.Type, def)
+	}
+
+	// Pointers encode nil as null
+	if field.Type.Kind() == reflect.Ptr || hasOpenAPIFlag(field, "nullable") {
+		b.markNullable(schema)
+	}
+	if hasOpenAPIFlag(field, "readOnly") {
+		schema["readOnly"] = true
+	}
+	if hasOpenAPIFlag(field, "writeOnly") {
+		schema["writeOnly"] = true
+	}
+}
+
+// markNullable allows null values in the dialect selected by SetOpenAPIVersion.
+func (b *SchemaBuilder) markNullable(schema map[string]interface{}) {
+	if !b.jsonSchema2020 {
+		schema["nullable"] = true
+		return
+	}
+
+	// JSON Schema 2020-12: null is one more type; schemas without a type already accept null
+	if typ, ok := schema["type"].(string); ok {
+		schema["type"] = []interface{}{typ, "null"}
+	}
+}
+
+// hasOpenAPIFlag reports whether the `openapi:"..."` tag contains a flag (case-insensitive).
+func hasOpenAPIFlag(field reflect.StructField, flag string) bool {
+	for _, f := range strings.Split(field.Tag.Get("openapi"), ",") {
+		if strings.EqualFold(strings.TrimSpace(f), flag) {
+			return true
+		}
+	}
+	return false
+}
+
+// tagValue converts an example or default tag to the field's JSON type.
+// Arrays and objects are written as JSON in the tag.
+func (b *SchemaBuilder) tagValue(t reflect.Type, value string) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(value), &decoded); err == nil {
+			return decoded
+		}
+		return value
+	}
+	return b.typedValue(t, value)
+}
+
+// applyConstraints translates validator rules into JSON Schema keywords.
+// min/max become lengths for strings, item counts for arrays and bounds for numbers.
+// Rules after dive describe the elements of slices and the values of maps.
+func (b *SchemaBuilder) applyConstraints(schema map[string]interface{}, t reflect.Type, validateTag string) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	rules := strings.Split(validateTag, ",")
+	for i, rule := range rules {
+		if rule == "dive" {
+			b.applyElementConstraints(schema, t, rules[i+1:])
+			return
+		}
+
+		key, value := rule, ""
+		if idx := strings.Index(rule, "="); idx >= 0 {
+			key, value = rule[:idx], rule[idx+1:]
+		}
+
+		switch key {
+		case "min", "gte":
+			b.setBound(schema, t, "minLength", "minItems", "minimum", value)
+		case "max", "lte":
+			b.setBound(schema, t, "maxLength", "maxItems", "maximum", value)
+		case "gt":
+			b.setBound(schema, t, "minLength", "minItems", "exclusiveMinimum", value)
+		case "lt":
+			b.setBound(schema, t, "maxLength", "maxItems", "exclusiveMaximum", value)
+		case "len":
+			b.setBound(schema, t, "minLength", "minItems", "minimum", value)
+			b.setBound(schema, t, "maxLength", "maxItems", "maximum", value)
+		case "oneof":
+			enum := []interface{}{}
+			for _, option := range strings.Fields(value) {
+				enum = append(enum, b.typedValue(t, option))
+			}
+			schema["enum"] = enum
+		case "email":
+			schema["format"] = "email"
+		case "url", "uri":
+			schema["format"] = "uri"
+		case "uuid", "uuid4":
+			schema["format"] = "uuid"
+		}
+	}
+}
+
+// applyElementConstraints applies the rules following dive to the items schema of
+// a slice or array, or the additionalProperties schema of a map.
+func (b *SchemaBuilder) applyElementConstraints(schema map[string]interface{}, t reflect.Type, rules []string) {
+	key := "items"
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+	case reflect.Map:
+		key = "additionalProperties"
+		// keys...endkeys validate map keys, which the value schema cannot describe
+		if len(rules) > 0 && rules[0] == "keys" {
+			for i, rule := range rules {
+				if rule == "endkeys" {
+					rules = rules[i+1:]
+					break
+				}
+			}
+		}
+	default:
+		return
+	}
+
+	// Guard clause: referenced schemas are shared, leave them untouched
+	element, ok := schema[key].(map[string]interface{})
+	if !ok || element["$ref"] != nil || element["oneOf"] != nil {
+		return
+	}
+	b.applyConstraints(element, t.Elem(), strings.Join(rules, ","))
+}
+
+// setBound sets the length, item count or numeric bound matching the field's kind.
+func (b *SchemaBuilder) setBound(schema map[string]interface{}, t reflect.Type, lengthKey, itemsKey, numberKey, value string) {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return
+	}
+
+	// Lengths and counts are integers: gt=3 means at least 4, lt=3 at most 2
+	count := int(n)
+	switch numberKey {
+	case "exclusiveMinimum":
+		count++
+	case "exclusiveMaximum":
+		count = max(count-1, 0)
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		schema[lengthKey] = count
+	case reflect.Slice, reflect.Array:
+		schema[itemsKey] = count
+	case reflect.Map:
+		// Objects count properties: minItems -> minProperties
+		schema[strings.TrimSuffix(itemsKey, "Items")+"Properties"] = count
+	case reflect.Struct:
+		// Validator applies min/max to struct fields only through dive, nothing to document
+	default:
+		if (numberKey == "exclusiveMinimum" || numberKey == "exclusiveMaximum") && !b.jsonSchema2020 {
+			// OpenAPI 3.0 expresses exclusive bounds as a flag on minimum/maximum
+			bound := strings.ToLower(strings.TrimPrefix(numberKey, "exclusive"))
+			schema[bound] = n
+			schema[numberKey] = true
+			return
+		}
+		schema[numberKey] = n
+	}
+}
+
+// typedValue converts a tag value to the field's JSON type.
+func (b *SchemaBuilder) typedValue(t reflect.Type, value string) interface{} {
+	switch b.goTypeToOpenAPIType(t.Kind().String()) {
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if v, err := strconv.ParseBool(value); err == nil {
+			return v
+		}
+	}
+	return value
+}
+
+// numberFormat returns the OpenAPI format for sized numeric kinds.
+func (b *SchemaBuilder) numberFormat(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Int32, reflect.Uint32:
+		return "int32"
+	case reflect.Int64, reflect.Uint64:
+		return "int64"
+	case reflect.Float32:
+		return "float"
+	case reflect.Float64:
+		return "double"
+	default:
+		return ""
+	}
+}
+
+// goTypeToOpenAPIType converts GO types to OpenAPI types.
+func (b *SchemaBuilder) goTypeToOpenAPIType(goType string) string {
+	switch goType {
+	case "string":
+		return "string"
+	case "int", "int8", "int16", "int32", "int64":
+		return "integer"
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		return "integer"
+	case "float32", "float64":
+		return "number"
+	case "bool":
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+// hasValidateRule reports whether a validate tag contains the given rule.
+func hasValidateRule(validateTag, rule string) bool {
+	for _, r := range strings.Split(validateTag, ",") {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
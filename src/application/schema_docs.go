@@ -0,0 +1,43 @@
+package application
+
+import (
+	"reflect"
+	"sync"
+)
+
+// typeDocs holds Go doc comments registered by generated code (syntrogo gen docs).
+// Keyed by type; fields are keyed by Go field name.
+var (
+	typeDocsMu sync.RWMutex
+	typeDocs   = map[reflect.Type]typeDoc{}
+)
+
+type typeDoc struct {
+	doc    string
+	fields map[string]string
+}
+
+// RegisterTypeDocs registers the doc comments of a type and its fields.
+// Called from init() in files generated by `syntrogo gen docs`.
+func RegisterTypeDocs(t reflect.Type, doc string, fields map[string]string) {
+	typeDocsMu.Lock()
+	defer typeDocsMu.Unlock()
+
+	typeDocs[t] = typeDoc{doc: doc, fields: fields}
+}
+
+// TypeDoc returns the registered doc comment of a type.
+func TypeDoc(t reflect.Type) string {
+	typeDocsMu.RLock()
+	defer typeDocsMu.RUnlock()
+
+	return typeDocs[t].doc
+}
+
+// FieldDoc returns the registered doc comment of a struct field.
+func FieldDoc(t reflect.Type, field string) string {
+	typeDocsMu.RLock()
+	defer typeDocsMu.RUnlock()
+
+	return typeDocs[t].fields[field]
+}
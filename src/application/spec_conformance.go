@@ -0,0 +1,169 @@
+package application
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// specMethods are the OpenAPI operation keys of a path item, in report order.
+var specMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// CompareSpecs reports where an app's generated spec departs from the source spec
+// it was implemented from (spec-first mode). Both specs are decoded JSON documents.
+// Checked per operation: presence, parameters (name and location), request body
+// and documented response status codes. Schemas are not compared.
+func CompareSpecs(source, actual map[string]interface{}) []string {
+	sourceOps := specOperations(source)
+	actualOps := specOperations(actual)
+	differences := []string{}
+
+	for _, key := range sortedOperationKeys(sourceOps) {
+		want := sourceOps[key]
+		got, ok := actualOps[key]
+		if !ok {
+			differences = append(differences, key+": not registered in the app")
+			continue
+		}
+		differences = append(differences, compareOperation(key, want, got)...)
+	}
+	for _, key := range sortedOperationKeys(actualOps) {
+		if _, ok := sourceOps[key]; !ok {
+			differences = append(differences, key+": not in the source spec")
+		}
+	}
+	return differences
+}
+
+// compareOperation compares one operation present in both specs.
+func compareOperation(key string, want, got map[string]interface{}) []string {
+	differences := []string{}
+
+	wantParams := operationParameters(want)
+	gotParams := operationParameters(got)
+	for _, param := range sortedSet(wantParams) {
+		if !gotParams[param] {
+			differences = append(differences, fmt.Sprintf("%s: missing parameter %s", key, param))
+		}
+	}
+	for _, param := range sortedSet(gotParams) {
+		if !wantParams[param] {
+			differences = append(differences, fmt.Sprintf("%s: unexpected parameter %s", key, param))
+		}
+	}
+
+	_, wantBody := want["requestBody"]
+	_, gotBody := got["requestBody"]
+	switch {
+	case wantBody && !gotBody:
+		differences = append(differences, key+": missing request body")
+	case !wantBody && gotBody:
+		differences = append(differences, key+": unexpected request body")
+	}
+
+	gotResponses, _ := got["responses"].(map[string]interface{})
+	wantResponses, _ := want["responses"].(map[string]interface{})
+	for _, status := range sortedMapKeys(wantResponses) {
+		// Only success responses are declared by RouteOptions; errors come from HTTPException
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		if _, ok := gotResponses[status]; !ok {
+			differences = append(differences, fmt.Sprintf("%s: missing response %s", key, status))
+		}
+	}
+	return differences
+}
+
+// specOperations indexes operations by "METHOD /path".
+// Path-level parameters are copied into each operation and local $refs are resolved.
+func specOperations(spec map[string]interface{}) map[string]map[string]interface{} {
+	operations := map[string]map[string]interface{}{}
+	paths, _ := spec["paths"].(map[string]interface{})
+	for path, item := range paths {
+		pathItem := resolveSpecRef(spec, item)
+		shared, _ := pathItem["parameters"].([]interface{})
+		for _, method := range specMethods {
+			op, ok := pathItem[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			own, _ := op["parameters"].([]interface{})
+			params := []interface{}{}
+			for _, param := range append(append([]interface{}{}, shared...), own...) {
+				params = append(params, resolveSpecRef(spec, param))
+			}
+
+			resolved := map[string]interface{}{}
+			for key, value := range op {
+				resolved[key] = value
+			}
+			resolved["parameters"] = params
+			operations[strings.ToUpper(method)+" "+path] = resolved
+		}
+	}
+	return operations
+}
+
+// resolveSpecRef follows a local "#/..." $ref; other nodes are returned as is.
+func resolveSpecRef(spec map[string]interface{}, node interface{}) map[string]interface{} {
+	object, _ := node.(map[string]interface{})
+	ref, ok := object["$ref"].(string)
+	if !ok || !strings.HasPrefix(ref, "#/") {
+		return object
+	}
+
+	var current interface{} = spec
+	for _, part := range strings.Split(ref[2:], "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		parent, _ := current.(map[string]interface{})
+		current = parent[part]
+	}
+	resolved, _ := current.(map[string]interface{})
+	return resolved
+}
+
+// operationParameters returns the operation's parameters as "location:name" keys.
+func operationParameters(op map[string]interface{}) map[string]bool {
+	params := map[string]bool{}
+	list, _ := op["parameters"].([]interface{})
+	for _, item := range list {
+		param, _ := item.(map[string]interface{})
+		name, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+		if in == "header" {
+			// Header names are case-insensitive
+			name = strings.ToLower(name)
+		}
+		params[in+":"+name] = true
+	}
+	return params
+}
+
+func sortedOperationKeys(m map[string]map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedMapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedSet(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,388 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SpecChange is one difference between two versions of an API spec.
+type SpecChange struct {
+	Breaking bool   // Existing clients may fail against the new version
+	Location string // "POST /users request body: address.city"
+	Message  string // What changed
+}
+
+// String formats the change for reports.
+func (c SpecChange) String() string {
+	level := "info"
+	if c.Breaking {
+		level = "BREAKING"
+	}
+	return fmt.Sprintf("%s %s: %s", level, c.Location, c.Message)
+}
+
+// schemaDirection tells whether a schema is sent by clients or returned to them.
+// The same edit can be safe in one direction and breaking in the other.
+type schemaDirection int
+
+const (
+	requestDirection schemaDirection = iota
+	responseDirection
+)
+
+// specDiff compares two decoded specs.
+type specDiff struct {
+	oldSpec, newSpec map[string]interface{}
+	changes          []SpecChange
+	visiting         map[string]bool // "$ref|$ref" pairs being compared (recursive schemas)
+}
+
+// DiffSpecs compares two OpenAPI documents produced by OpenAPIGenerator, for example
+// the committed spec and the current App. Reported as breaking: removed operations,
+// new required parameters and request fields, narrowed request enums, changed types,
+// removed response fields and removed success responses.
+func DiffSpecs(oldSpec, newSpec map[string]interface{}) []SpecChange {
+	d := &specDiff{oldSpec: oldSpec, newSpec: newSpec, visiting: map[string]bool{}}
+
+	oldOps := specOperations(oldSpec)
+	newOps := specOperations(newSpec)
+	for _, key := range sortedOperationKeys(oldOps) {
+		newOp, ok := newOps[key]
+		if !ok {
+			d.add(true, key, "operation removed")
+			continue
+		}
+		d.compareOperation(key, oldOps[key], newOp)
+	}
+	for _, key := range sortedOperationKeys(newOps) {
+		if _, ok := oldOps[key]; !ok {
+			d.add(false, key, "operation added")
+		}
+	}
+	return d.changes
+}
+
+// HasBreakingChanges reports whether any change is breaking.
+func HasBreakingChanges(changes []SpecChange) bool {
+	for _, change := range changes {
+		if change.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// compareOperation compares parameters, request body and responses of one operation.
+func (d *specDiff) compareOperation(key string, oldOp, newOp map[string]interface{}) {
+	oldParams := indexParameters(oldOp)
+	newParams := indexParameters(newOp)
+	for _, name := range sortedMapKeys(newParams) {
+		param := asObject(newParams[name])
+		required, _ := param["required"].(bool)
+		oldParam, existed := oldParams[name]
+		switch {
+		case !existed && required:
+			d.add(true, key, "new required parameter "+name)
+		case !existed:
+			d.add(false, key, "new optional parameter "+name)
+		default:
+			wasRequired, _ := asObject(oldParam)["required"].(bool)
+			if required && !wasRequired {
+				d.add(true, key, "parameter "+name+" became required")
+			}
+			d.compareSchema(key+" parameter "+name, "", asObject(asObject(oldParam)["schema"]), asObject(param["schema"]), requestDirection)
+		}
+	}
+	for _, name := range sortedMapKeys(oldParams) {
+		if _, ok := newParams[name]; !ok {
+			d.add(false, key, "parameter "+name+" removed")
+		}
+	}
+
+	d.compareRequestBody(key, oldOp, newOp)
+	d.compareResponses(key, oldOp, newOp)
+}
+
+// compareRequestBody compares the JSON request schemas.
+func (d *specDiff) compareRequestBody(key string, oldOp, newOp map[string]interface{}) {
+	oldBody := resolveSpecRef(d.oldSpec, oldOp["requestBody"])
+	newBody := resolveSpecRef(d.newSpec, newOp["requestBody"])
+	switch {
+	case oldBody == nil && newBody == nil:
+		return
+	case oldBody == nil:
+		required, _ := newBody["required"].(bool)
+		d.add(required, key, "request body added")
+		return
+	case newBody == nil:
+		d.add(false, key, "request body removed")
+		return
+	}
+
+	wasRequired, _ := oldBody["required"].(bool)
+	if required, _ := newBody["required"].(bool); required && !wasRequired {
+		d.add(true, key, "request body became required")
+	}
+	d.compareSchema(key+" request body", "", mediaSchema(oldBody), mediaSchema(newBody), requestDirection)
+}
+
+// compareResponses compares success responses and their JSON schemas.
+func (d *specDiff) compareResponses(key string, oldOp, newOp map[string]interface{}) {
+	oldResponses := asObject(oldOp["responses"])
+	newResponses := asObject(newOp["responses"])
+	for _, status := range sortedMapKeys(oldResponses) {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		newResponse, ok := newResponses[status]
+		if !ok {
+			d.add(true, key, "response "+status+" removed")
+			continue
+		}
+		d.compareSchema(key+" response "+status, "",
+			mediaSchema(resolveSpecRef(d.oldSpec, oldResponses[status])),
+			mediaSchema(resolveSpecRef(d.newSpec, newResponse)),
+			responseDirection)
+	}
+}
+
+// compareSchema compares two schemas, recursing into properties and items.
+// base names the operation part ("POST /users request body"), path the field ("address.city").
+func (d *specDiff) compareSchema(base, path string, oldSchema, newSchema map[string]interface{}, direction schemaDirection) {
+	// Guard clause: recursive schemas are compared once per pair of $refs
+	oldRef, _ := oldSchema["$ref"].(string)
+	newRef, _ := newSchema["$ref"].(string)
+	if oldRef != "" || newRef != "" {
+		pair := oldRef + "|" + newRef
+		if d.visiting[pair] {
+			return
+		}
+		d.visiting[pair] = true
+		defer delete(d.visiting, pair)
+	}
+	oldSchema = flattenSchema(d.oldSpec, oldSchema)
+	newSchema = flattenSchema(d.newSpec, newSchema)
+	location := joinLocation(base, path)
+
+	switch {
+	case oldSchema == nil:
+		return
+	case newSchema == nil:
+		d.add(direction == responseDirection, location, "schema removed")
+		return
+	}
+
+	oldType, newType := schemaTypeName(oldSchema), schemaTypeName(newSchema)
+	if oldType != newType && oldType != "" && newType != "" {
+		// Requests accepting numbers still accept the integers clients send
+		widened := direction == requestDirection && oldType == "integer" && newType == "number"
+		d.add(!widened, location, fmt.Sprintf("type changed from %s to %s", oldType, newType))
+		return
+	}
+	oldFormat, _ := oldSchema["format"].(string)
+	newFormat, _ := newSchema["format"].(string)
+	if oldFormat != newFormat && oldFormat != "" && newFormat != "" {
+		d.add(true, location, fmt.Sprintf("format changed from %s to %s", oldFormat, newFormat))
+	}
+
+	d.compareEnum(location, oldSchema, newSchema, direction)
+	d.compareProperties(base, path, oldSchema, newSchema, direction)
+
+	if items := asObject(newSchema["items"]); items != nil {
+		d.compareSchema(base, path+"[]", asObject(oldSchema["items"]), items, direction)
+	}
+	if additional := asObject(newSchema["additionalProperties"]); additional != nil {
+		d.compareSchema(base, path+"{}", asObject(oldSchema["additionalProperties"]), additional, direction)
+	}
+}
+
+// compareEnum reports removed values in requests (clients may still send them)
+// and added values in responses (clients may not handle them).
+func (d *specDiff) compareEnum(location string, oldSchema, newSchema map[string]interface{}, direction schemaDirection) {
+	oldValues, _ := oldSchema["enum"].([]interface{})
+	newValues, _ := newSchema["enum"].([]interface{})
+
+	switch {
+	case len(oldValues) == 0 && len(newValues) == 0:
+		return
+	case len(oldValues) == 0:
+		d.add(direction == requestDirection, location, "enum added")
+		return
+	case len(newValues) == 0:
+		d.add(direction == responseDirection, location, "enum removed")
+		return
+	}
+
+	for _, value := range oldValues {
+		if !containsValue(newValues, value) {
+			d.add(direction == requestDirection, location, fmt.Sprintf("enum value %v removed", value))
+		}
+	}
+	for _, value := range newValues {
+		if !containsValue(oldValues, value) {
+			d.add(direction == responseDirection, location, fmt.Sprintf("enum value %v added", value))
+		}
+	}
+}
+
+// compareProperties compares object properties and required lists.
+func (d *specDiff) compareProperties(base, path string, oldSchema, newSchema map[string]interface{}, direction schemaDirection) {
+	oldProperties := asObject(oldSchema["properties"])
+	newProperties := asObject(newSchema["properties"])
+	oldRequired := requiredSet(oldSchema)
+	newRequired := requiredSet(newSchema)
+	childPath := func(name string) string {
+		if path == "" {
+			return name
+		}
+		return path + "." + name
+	}
+	field := func(name string) string {
+		return joinLocation(base, childPath(name))
+	}
+
+	for _, name := range sortedMapKeys(oldProperties) {
+		if _, ok := newProperties[name]; !ok {
+			d.add(direction == responseDirection, field(name), "field removed")
+			continue
+		}
+		if direction == responseDirection && oldRequired[name] && !newRequired[name] {
+			d.add(true, field(name), "field is no longer always returned")
+		}
+		d.compareSchema(base, childPath(name), asObject(oldProperties[name]), asObject(newProperties[name]), direction)
+	}
+
+	for _, name := range sortedMapKeys(newProperties) {
+		_, existed := oldProperties[name]
+		switch {
+		case direction == requestDirection && !existed && newRequired[name]:
+			d.add(true, field(name), "new required field")
+		case direction == requestDirection && existed && newRequired[name] && !oldRequired[name]:
+			d.add(true, field(name), "field became required")
+		case !existed:
+			d.add(false, field(name), "field added")
+		}
+	}
+}
+
+// joinLocation appends a field path to an operation location.
+func joinLocation(base, path string) string {
+	if path == "" {
+		return base
+	}
+	return base + ": " + path
+}
+
+// flattenSchema resolves $ref and merges allOf members into one object schema.
+func flattenSchema(spec map[string]interface{}, schema map[string]interface{}) map[string]interface{} {
+	schema = resolveSpecRef(spec, schema)
+	members, _ := schema["allOf"].([]interface{})
+	if len(members) == 0 {
+		return schema
+	}
+
+	merged := map[string]interface{}{}
+	for key, value := range schema {
+		if key != "allOf" {
+			merged[key] = value
+		}
+	}
+	properties := map[string]interface{}{}
+	required := []interface{}{}
+	for key, value := range asObject(schema["properties"]) {
+		properties[key] = value
+	}
+	if list, ok := schema["required"].([]interface{}); ok {
+		required = append(required, list...)
+	}
+	for _, member := range members {
+		part := flattenSchema(spec, asObject(member))
+		for key, value := range asObject(part["properties"]) {
+			properties[key] = value
+		}
+		list, _ := part["required"].([]interface{})
+		required = append(required, list...)
+		if merged["type"] == nil && part["type"] != nil {
+			merged["type"] = part["type"]
+		}
+	}
+	merged["properties"] = properties
+	merged["required"] = required
+	return merged
+}
+
+// add records a change.
+func (d *specDiff) add(breaking bool, location, message string) {
+	d.changes = append(d.changes, SpecChange{Breaking: breaking, Location: location, Message: message})
+}
+
+// indexParameters keys an operation's parameters by "location:name".
+func indexParameters(op map[string]interface{}) map[string]interface{} {
+	params := map[string]interface{}{}
+	list, _ := op["parameters"].([]interface{})
+	for _, item := range list {
+		param := asObject(item)
+		name, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+		if in == "header" {
+			name = strings.ToLower(name)
+		}
+		params[in+":"+name] = param
+	}
+	return params
+}
+
+// mediaSchema returns the JSON schema of a request body or response.
+func mediaSchema(object map[string]interface{}) map[string]interface{} {
+	content := asObject(object["content"])
+	if media := asObject(content["application/json"]); media != nil {
+		return asObject(media["schema"])
+	}
+	for _, mediaType := range sortedMapKeys(content) {
+		return asObject(asObject(content[mediaType])["schema"])
+	}
+	return nil
+}
+
+// schemaTypeName returns the non-null type of a 3.0 or 3.1 schema.
+func schemaTypeName(schema map[string]interface{}) string {
+	switch typ := schema["type"].(type) {
+	case string:
+		return typ
+	case []interface{}:
+		for _, t := range typ {
+			if s, ok := t.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// requiredSet returns a schema's required properties.
+func requiredSet(schema map[string]interface{}) map[string]bool {
+	set := map[string]bool{}
+	list, _ := schema["required"].([]interface{})
+	for _, name := range list {
+		if s, ok := name.(string); ok {
+			set[s] = true
+		}
+	}
+	return set
+}
+
+// containsValue reports whether a JSON array contains a value.
+func containsValue(list []interface{}, value interface{}) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// asObject returns v as a JSON object, or nil.
+func asObject(v interface{}) map[string]interface{} {
+	object, _ := v.(map[string]interface{})
+	return object
+}
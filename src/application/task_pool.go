@@ -0,0 +1,131 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// ErrTaskPoolClosed is returned when tasks are submitted after Shutdown started.
+var ErrTaskPoolClosed = errors.New("task pool closed")
+
+// TaskPool runs background tasks on a bounded set of workers.
+// A panicking task is recovered and reported; it never takes the process down.
+// Workers start with the first submitted task.
+type TaskPool struct {
+	config domain.TaskConfig
+	queue  chan domain.Task
+
+	ctx    context.Context // Passed to tasks; canceled when Shutdown gives up
+	cancel context.CancelFunc
+
+	start   sync.Once
+	workers sync.WaitGroup
+
+	mu     sync.RWMutex // Guards closed against concurrent Submit
+	closed bool
+}
+
+// NewTaskPool creates a task pool; zero config values take the defaults.
+func NewTaskPool(config domain.TaskConfig) *TaskPool {
+	if config.Workers <= 0 {
+		config.Workers = runtime.GOMAXPROCS(0)
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1024
+	}
+	if config.OnError == nil {
+		config.OnError = func(err error) {
+			log.Printf("syntrogo: background task: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &TaskPool{
+		config: config,
+		queue:  make(chan domain.Task, config.QueueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Submit queues tasks, blocking while the queue is full.
+// Rejected tasks are also reported to TaskConfig.OnError.
+func (p *TaskPool) Submit(tasks ...domain.Task) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	// Guard clause: shutting down
+	if p.closed {
+		p.config.OnError(ErrTaskPoolClosed)
+		return ErrTaskPoolClosed
+	}
+
+	p.start.Do(p.startWorkers)
+	for _, task := range tasks {
+		select {
+		case p.queue <- task:
+		case <-p.ctx.Done():
+			p.config.OnError(ErrTaskPoolClosed)
+			return ErrTaskPoolClosed
+		}
+	}
+	return nil
+}
+
+// Shutdown stops accepting tasks and waits for queued ones to finish.
+// When ctx expires first, running tasks see their context canceled and ctx.Err() is returned.
+func (p *TaskPool) Shutdown(ctx context.Context) error {
+	drained := make(chan struct{})
+	go func() {
+		p.mu.Lock()
+		if !p.closed {
+			p.closed = true
+			close(p.queue)
+		}
+		p.mu.Unlock()
+
+		p.workers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return ctx.Err()
+	}
+}
+
+// startWorkers launches the workers.
+func (p *TaskPool) startWorkers() {
+	p.workers.Add(p.config.Workers)
+	for i := 0; i < p.config.Workers; i++ {
+		go func() {
+			defer p.workers.Done()
+			for task := range p.queue {
+				p.run(task)
+			}
+		}()
+	}
+}
+
+// run executes one task, reporting its error or panic.
+func (p *TaskPool) run(task domain.Task) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			p.config.OnError(&domain.TaskPanicError{Value: recovered, Stack: debug.Stack()})
+		}
+	}()
+
+	if err := task(p.ctx); err != nil {
+		p.config.OnError(err)
+	}
+}
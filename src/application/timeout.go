@@ -0,0 +1,78 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// Timeout bounds a handler with a deadline on its request context.
+// Transport-agnostic: the HTTP adapter applies it for RouteOptions.Timeout and
+// any other transport can do the same.
+//
+// The handler runs on a detached copy of the Context (see Context.Detach), so
+// middlewares never share fields with it. When it returns in time, its status,
+// body and headers are copied back. When the deadline passes first, the request
+// fails with 504 right away; the handler keeps running until it notices
+// ctx.Context().Done(), and whatever it writes afterwards is discarded: values
+// and tasks it adds are dropped, files it sends are closed and streams fail.
+// Handler errors caused by the deadline or by the client going away become 504
+// and 503.
+func Timeout(d time.Duration) domain.Middleware {
+	return func(next domain.HandlerFunc) domain.HandlerFunc {
+		// Guard clause: no deadline configured
+		if d <= 0 {
+			return next
+		}
+
+		return func(c *domain.Context) error {
+			ctx, cancel := context.WithTimeout(c.Context(), d)
+			defer cancel()
+			handlerCtx := c.Detach().WithContext(ctx)
+
+			done := make(chan error, 1)
+			panicked := make(chan interface{}, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicked <- p
+					}
+				}()
+				done <- next(handlerCtx)
+			}()
+
+			select {
+			case err := <-done:
+				c.StatusCode, c.Body, c.ContentType = handlerCtx.StatusCode, handlerCtx.Body, handlerCtx.ContentType
+				c.Headers = handlerCtx.Headers
+				return ContextError(err)
+			case p := <-panicked:
+				// Re-panic on the request goroutine, as if there were no timeout
+				panic(p)
+			case <-ctx.Done():
+				handlerCtx.Abandon()
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return domain.GatewayTimeout
+				}
+				return domain.ServiceUnavailable
+			}
+		}
+	}
+}
+
+// ContextError maps context errors to HTTP exceptions: an exceeded deadline
+// becomes 504 and a canceled request 503. Other errors are returned unchanged.
+func ContextError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.DeadlineExceeded):
+		return domain.GatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return domain.ServiceUnavailable
+	default:
+		return err
+	}
+}
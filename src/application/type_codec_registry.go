@@ -0,0 +1,86 @@
+package application
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// TypeCodec is reflection-free code for one struct type T, written by
+// `syntrogo gen binders`. The HTTP adapter uses it when registered and falls back
+// to encoding/json and the validator otherwise.
+type TypeCodec struct {
+	Decode   func(r *JSONReader, v interface{}) error // v is *T
+	Encode   func(w *JSONWriter, v interface{})       // v is T or *T
+	Validate func(v interface{}) error                // nil when the validate tags need the validator
+}
+
+// FieldErrors collects failed validate rules, reported in the validator's format.
+type FieldErrors struct {
+	messages []string
+}
+
+// typeCodecs holds the generated code by struct type.
+var (
+	typeCodecsMu sync.RWMutex
+	typeCodecs   = map[reflect.Type]TypeCodec{}
+)
+
+// RegisterTypeCodec registers generated code for a struct type.
+// Called from the init function of generated files.
+func RegisterTypeCodec(t reflect.Type, codec TypeCodec) {
+	typeCodecsMu.Lock()
+	defer typeCodecsMu.Unlock()
+
+	typeCodecs[t] = codec
+}
+
+// LookupTypeCodec returns the generated code for a struct type.
+func LookupTypeCodec(t reflect.Type) (TypeCodec, bool) {
+	typeCodecsMu.RLock()
+	defer typeCodecsMu.RUnlock()
+
+	codec, ok := typeCodecs[t]
+	return codec, ok
+}
+
+// Unmarshal decodes a whole JSON document into v (*T). Like encoding/json, fields
+// absent from data keep their values. Unlike it, v is left unchanged on any error:
+// the document is decoded into a copy of *v, stored only once fully read.
+func (c TypeCodec) Unmarshal(data []byte, v interface{}) error {
+	target := reflect.ValueOf(v).Elem()
+	decoded := reflect.New(target.Type())
+	decoded.Elem().Set(target)
+
+	r := NewJSONReader(data)
+	if err := c.Decode(r, decoded.Interface()); err != nil {
+		return err
+	}
+	if err := r.End(); err != nil {
+		return err
+	}
+	target.Set(decoded.Elem())
+	return nil
+}
+
+// Marshal encodes v (T or *T) as JSON.
+func (c TypeCodec) Marshal(v interface{}) ([]byte, error) {
+	w := &JSONWriter{}
+	c.Encode(w, v)
+	return w.Result()
+}
+
+// Add records a failed rule. namespace is the dotted path from the root type
+// ("User.Address.City"), field the Go field name and tag the rule ("min").
+func (e *FieldErrors) Add(namespace, field, tag string) {
+	e.messages = append(e.messages, "Key: '"+namespace+"' Error:Field validation for '"+field+"' failed on the '"+tag+"' tag")
+}
+
+// Err returns the collected failures as one error, nil when there are none.
+func (e *FieldErrors) Err() error {
+	if len(e.messages) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(e.messages, "\n"))
+}
@@ -0,0 +1,129 @@
+package application
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// paramTags are the struct tags read by the parameter and form binders.
+var paramTags = []string{"path", "query", "header", "cookie", "form"}
+
+// typeInfos caches TypeInfo by reflect.Type.
+var typeInfos sync.Map
+
+// TypeInfo is the parsed struct metadata shared by the binders, the schema builder
+// and the JSON decoder. It is computed once per type: at route registration for
+// declared types (PrepareTypes), otherwise on first use.
+type TypeInfo struct {
+	Type      reflect.Type
+	Fields    []FieldInfo // Every field, in declaration order
+	HasJSON   bool        // Some field has a json name
+	hasParams map[string]bool
+}
+
+// FieldInfo is one struct field with its tags parsed.
+type FieldInfo struct {
+	reflect.StructField
+	Params     map[string]string // Parameter name per binder tag ("query", "form", ...)
+	JSONName   string            // "" when the field has no json name
+	JSONSkip   bool              // json:"-"
+	Required   bool              // validate:"required"
+	Default    string            // default tag
+	HasDefault bool
+}
+
+// TypeInfoOf returns the cached metadata of a struct type (pointers are dereferenced).
+// Non-struct types have no fields.
+func TypeInfoOf(t reflect.Type) *TypeInfo {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if cached, ok := typeInfos.Load(t); ok {
+		return cached.(*TypeInfo)
+	}
+
+	info := &TypeInfo{Type: t, hasParams: map[string]bool{}}
+	if t != nil && t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			info.Fields = append(info.Fields, newFieldInfo(t.Field(i)))
+		}
+	}
+	for _, field := range info.Fields {
+		if field.JSONName != "" {
+			info.HasJSON = true
+		}
+		for tag := range field.Params {
+			info.hasParams[tag] = true
+		}
+	}
+
+	cached, _ := typeInfos.LoadOrStore(t, info)
+	return cached.(*TypeInfo)
+}
+
+// LookupTypeInfo returns the metadata of a type if it has been computed (by
+// TypeInfoOf or PrepareTypes), nil otherwise.
+func LookupTypeInfo(t reflect.Type) *TypeInfo {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if cached, ok := typeInfos.Load(t); ok {
+		return cached.(*TypeInfo)
+	}
+	return nil
+}
+
+// HasParams reports whether some field is tagged for a binder ("query", "form", ...).
+func (i *TypeInfo) HasParams(tag string) bool {
+	return i.hasParams[tag]
+}
+
+// Param returns the parameter name of the field for a binder tag; "" when untagged.
+func (f FieldInfo) Param(tag string) string {
+	if name, ok := f.Params[tag]; ok {
+		return name
+	}
+	for _, known := range paramTags {
+		if known == tag {
+			return ""
+		}
+	}
+	return ParamName(f.StructField, tag)
+}
+
+// PrepareTypes computes the metadata of the given values' types ahead of the first
+// request. The app calls it for the Body, Query, Headers, Cookies and Form of each route.
+func PrepareTypes(values ...interface{}) {
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		t := reflect.TypeOf(value)
+		TypeInfoOf(t)
+		containsUnionCached(t)
+	}
+}
+
+// newFieldInfo parses the tags of one field.
+func newFieldInfo(field reflect.StructField) FieldInfo {
+	info := FieldInfo{
+		StructField: field,
+		Params:      map[string]string{},
+		Required:    hasValidateRule(field.Tag.Get("validate"), "required"),
+	}
+
+	for _, tag := range paramTags {
+		if name := ParamName(field, tag); name != "" {
+			info.Params[tag] = name
+		}
+	}
+
+	jsonTag := field.Tag.Get("json")
+	info.JSONSkip = jsonTag == "-"
+	if !info.JSONSkip {
+		info.JSONName = strings.Split(jsonTag, ",")[0]
+	}
+	info.Default, info.HasDefault = field.Tag.Lookup("default")
+	return info
+}
@@ -0,0 +1,283 @@
+package application
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// Union describes an interface type whose JSON payloads are tagged by a discriminator field.
+// Example: PaymentMethod is either Card ("card") or BankTransfer ("bank_transfer") by "type".
+type Union struct {
+	Interface     reflect.Type
+	Discriminator string                  // JSON property holding the variant name
+	Variants      map[string]reflect.Type // Discriminator value -> concrete type
+}
+
+// unions holds the registered polymorphic types.
+var (
+	unionsMu sync.RWMutex
+	unions   = map[reflect.Type]*Union{}
+)
+
+// RegisterUnion registers the concrete implementations of an interface type.
+// Guard Clause: Fails fast on types that cannot take part in a union.
+func RegisterUnion(iface reflect.Type, discriminator string, variants map[string]reflect.Type) error {
+	if iface == nil || iface.Kind() != reflect.Interface {
+		return fmt.Errorf("union type must be an interface, got %v", iface)
+	}
+	if discriminator == "" {
+		return fmt.Errorf("union %s requires a discriminator property", iface)
+	}
+	if len(variants) == 0 {
+		return fmt.Errorf("union %s requires at least one variant", iface)
+	}
+	for value, variant := range variants {
+		if variant == nil || !variant.Implements(iface) {
+			return fmt.Errorf("union %s: variant %q (%v) does not implement it", iface, value, variant)
+		}
+	}
+
+	unionsMu.Lock()
+	defer unionsMu.Unlock()
+
+	unions[iface] = &Union{Interface: iface, Discriminator: discriminator, Variants: variants}
+	resetUnionCache()
+	return nil
+}
+
+// LookupUnion returns the union registered for an interface type, or nil.
+func LookupUnion(t reflect.Type) *Union {
+	unionsMu.RLock()
+	defer unionsMu.RUnlock()
+
+	return unions[t]
+}
+
+// VariantNames returns the discriminator values sorted, for deterministic output.
+func (u *Union) VariantNames() []string {
+	names := make([]string, 0, len(u.Variants))
+	for name := range u.Variants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DecodeJSON unmarshals data into v, choosing the concrete type of registered
+// unions by their discriminator value. Types without unions use encoding/json directly.
+func DecodeJSON(data []byte, v interface{}) error {
+	return DecodeJSONWith(nil, data, v)
+}
+
+// DecodeJSONWith is DecodeJSON with a pluggable engine for types without unions;
+// nil means encoding/json. Union payloads are always split with encoding/json.
+func DecodeJSONWith(engine domain.JSONEngine, data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return json.Unmarshal(data, v)
+	}
+	if engine != nil && !containsUnionCached(rv.Elem().Type()) {
+		return engine.Unmarshal(data, v)
+	}
+	return decodeValue(data, rv.Elem())
+}
+
+// decodeValue decodes into an addressable value, descending only where unions are involved.
+func decodeValue(data []byte, rv reflect.Value) error {
+	t := rv.Type()
+
+	// Happy path: nothing polymorphic below this type
+	if !containsUnionCached(t) {
+		return json.Unmarshal(data, rv.Addr().Interface())
+	}
+
+	// null leaves pointers, interfaces, slices and maps at their zero value
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		rv.Set(reflect.Zero(t))
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return decodeUnion(data, rv, LookupUnion(t))
+	case reflect.Ptr:
+		target := reflect.New(t.Elem())
+		if err := decodeValue(data, target.Elem()); err != nil {
+			return err
+		}
+		rv.Set(target)
+		return nil
+	case reflect.Slice:
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(t, len(items), len(items))
+		for i, item := range items {
+			if err := decodeValue(item, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(slice)
+		return nil
+	case reflect.Map:
+		var items map[string]json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		m := reflect.MakeMapWithSize(t, len(items))
+		for key, item := range items {
+			value := reflect.New(t.Elem()).Elem()
+			if err := decodeValue(item, value); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), value)
+		}
+		rv.Set(m)
+		return nil
+	case reflect.Struct:
+		return decodeStruct(data, rv)
+	default:
+		return json.Unmarshal(data, rv.Addr().Interface())
+	}
+}
+
+// decodeUnion reads the discriminator and decodes into the matching concrete type.
+func decodeUnion(data []byte, rv reflect.Value, union *Union) error {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return err
+	}
+
+	// Guard clause: the discriminator must be present and known
+	var name string
+	if raw, ok := probe[union.Discriminator]; !ok || json.Unmarshal(raw, &name) != nil {
+		return domain.NewHTTPException(400, fmt.Sprintf("missing %q discriminator", union.Discriminator))
+	}
+	variant, ok := union.Variants[name]
+	if !ok {
+		return domain.NewHTTPException(400, fmt.Sprintf("unknown %s %q, expected one of: %s",
+			union.Discriminator, name, strings.Join(union.VariantNames(), ", ")))
+	}
+
+	// Variants registered as pointers (&Card{}) are stored as pointers
+	if variant.Kind() == reflect.Ptr {
+		target := reflect.New(variant.Elem())
+		if err := decodeValue(data, target.Elem()); err != nil {
+			return err
+		}
+		rv.Set(target)
+		return nil
+	}
+
+	target := reflect.New(variant).Elem()
+	if err := decodeValue(data, target); err != nil {
+		return err
+	}
+	rv.Set(target)
+	return nil
+}
+
+// decodeStruct decodes a struct field by field so union fields get their concrete types.
+// Field names follow encoding/json: json tag, else Go name, matched case-insensitively.
+func decodeStruct(data []byte, rv reflect.Value) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+
+		// Embedded structs without a json name share the parent's object
+		if field.Anonymous && jsonTag == "" {
+			if field.IsExported() || field.Type.Kind() == reflect.Struct {
+				if err := decodeValue(data, rv.Field(i)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name := strings.Split(jsonTag, ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+
+		raw, ok := fields[name]
+		if !ok {
+			for key, value := range fields {
+				if strings.EqualFold(key, name) {
+					raw, ok = value, true
+					break
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		if err := decodeValue(raw, rv.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unionCache memoizes containsUnion by type; registering a union clears it.
+var unionCache sync.Map
+
+// containsUnionCached is containsUnion computed once per type.
+func containsUnionCached(t reflect.Type) bool {
+	if cached, ok := unionCache.Load(t); ok {
+		return cached.(bool)
+	}
+	found := containsUnion(t, map[reflect.Type]bool{})
+	unionCache.Store(t, found)
+	return found
+}
+
+// resetUnionCache forgets the memoized answers after a new union is registered.
+func resetUnionCache() {
+	unionCache.Range(func(key, _ interface{}) bool {
+		unionCache.Delete(key)
+		return true
+	})
+}
+
+// containsUnion reports whether a registered union appears anywhere inside t.
+func containsUnion(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return LookupUnion(t) != nil
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return containsUnion(t.Elem(), visiting)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if containsUnion(t.Field(i).Type, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package codecs
+
+import (
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// cborCodec reads and writes CBOR (RFC 8949).
+type cborCodec struct{}
+
+// CBOR returns the application/cbor codec. Field names follow `cbor` tags, then `json` tags.
+func CBOR() domain.Codec {
+	return cborCodec{}
+}
+
+// MediaType implements domain.Codec.
+func (cborCodec) MediaType() string { return "application/cbor" }
+
+// Encode implements domain.Codec.
+func (cborCodec) Encode(w io.Writer, v interface{}) error {
+	return cbor.NewEncoder(w).Encode(v)
+}
+
+// Decode implements domain.Codec.
+func (cborCodec) Decode(r io.Reader, v interface{}) error {
+	return cbor.NewDecoder(r).Decode(v)
+}
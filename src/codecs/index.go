@@ -0,0 +1,13 @@
+// Package codecs provides optional body codecs for app.Codec.
+// JSON and XML are built in; these add binary formats and their dependencies only when imported.
+package codecs
+
+// Codecs:
+// - MsgPack: application/msgpack, reuses `json` struct tags
+// - CBOR: application/cbor, reuses `json` struct tags
+// - Protobuf: application/x-protobuf, for proto.Message values
+//
+// Usage:
+//   import "github.com/syntropysoft/syntrogo/src/codecs"
+//
+//   app.Codec(codecs.MsgPack()).Codec(codecs.CBOR()).Codec(codecs.Protobuf())
@@ -0,0 +1,35 @@
+package codecs
+
+import (
+	"io"
+
+	"github.com/syntropysoft/syntrogo/src/domain"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// msgpackCodec reads and writes MessagePack.
+type msgpackCodec struct{}
+
+// MsgPack returns the application/msgpack codec. Field names follow `json` tags,
+// so the same structs serve JSON and MessagePack clients.
+func MsgPack() domain.Codec {
+	return msgpackCodec{}
+}
+
+// MediaType implements domain.Codec.
+func (msgpackCodec) MediaType() string { return "application/msgpack" }
+
+// Encode implements domain.Codec.
+func (msgpackCodec) Encode(w io.Writer, v interface{}) error {
+	encoder := msgpack.NewEncoder(w)
+	encoder.SetCustomStructTag("json")
+	encoder.SetOmitEmpty(false)
+	return encoder.Encode(v)
+}
+
+// Decode implements domain.Codec.
+func (msgpackCodec) Decode(r io.Reader, v interface{}) error {
+	decoder := msgpack.NewDecoder(r)
+	decoder.SetCustomStructTag("json")
+	return decoder.Decode(v)
+}
@@ -0,0 +1,48 @@
+package codecs
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/syntropysoft/syntrogo/src/domain"
+	"google.golang.org/protobuf/proto"
+)
+
+// protobufCodec reads and writes Protocol Buffers messages.
+type protobufCodec struct{}
+
+// Protobuf returns the application/x-protobuf codec. Bodies must be generated
+// proto.Message types; other values fail to encode and decode.
+func Protobuf() domain.Codec {
+	return protobufCodec{}
+}
+
+// MediaType implements domain.Codec.
+func (protobufCodec) MediaType() string { return "application/x-protobuf" }
+
+// Encode implements domain.Codec.
+func (protobufCodec) Encode(w io.Writer, v interface{}) error {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("protobuf: %T is not a proto.Message", v)
+	}
+	data, err := proto.Marshal(message)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Decode implements domain.Codec.
+func (protobufCodec) Decode(r io.Reader, v interface{}) error {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return domain.NewHTTPException(415, fmt.Sprintf("protobuf bodies need a proto.Message target, not %T", v))
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, message)
+}
@@ -0,0 +1,924 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BindersFile is the default name of the file written by `syntrogo gen binders`.
+const BindersFile = "syntrogo_binders.go"
+
+// binderMethods make encoding/json bypass the struct fields, so types declaring
+// them keep the reflection path.
+var binderMethods = []string{"MarshalJSON", "UnmarshalJSON", "MarshalText", "UnmarshalText"}
+
+// Builtin types the generated code reads and writes directly.
+var (
+	intTypes   = map[string]int{"int": 0, "int8": 8, "int16": 16, "int32": 32, "rune": 32, "int64": 64}
+	uintTypes  = map[string]int{"uint": 0, "uint8": 8, "byte": 8, "uint16": 16, "uint32": 32, "uint64": 64}
+	floatTypes = map[string]int{"float32": 32, "float64": 64}
+)
+
+// binderType is a field type the generator understands.
+type binderType struct {
+	kind string // string, bool, int, uint, float, time, bytes, ptr, slice, map, struct
+	bits int
+	name string // Declared type name ("Status", "Address"); "" for builtins
+	base string // Builtin Go type ("int64")
+	elem *binderType
+}
+
+// binderField is one exported struct field.
+type binderField struct {
+	goName    string
+	jsonName  string // "" when encoding/json skips the field
+	omitEmpty bool
+	validate  string
+	typ       *binderType
+}
+
+// binderStruct is a struct type with what could be generated for it.
+type binderStruct struct {
+	name           string
+	fields         []binderField
+	skip           string // Why encode/decode was not generated
+	skipValidation string // Why validation was not generated
+}
+
+// binderScanner holds the parsed package.
+type binderScanner struct {
+	pkgName string
+	decls   map[string]*ast.TypeSpec
+	files   map[string]*ast.File // Declaring file by type name
+	methods map[string]map[string]bool
+	roots   map[string]bool
+	structs map[string]*binderStruct
+}
+
+// GenerateBinders reads the Go package in dir and writes reflection-free JSON
+// decoders, encoders and validators for the types passed to Body(...) and
+// Response(...), plus the structs they contain. The HTTP adapter uses them
+// automatically; types the generator cannot handle keep the reflection path.
+// Usage: //go:generate go run github.com/syntropysoft/syntrogo/cmd/syntrogo gen binders
+func GenerateBinders(dir string, w io.Writer) error {
+	scanner, err := scanBinders(dir)
+	if err != nil {
+		return err
+	}
+	for name := range scanner.roots {
+		scanner.analyze(name)
+	}
+	scanner.propagate()
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by syntrogo gen binders. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", scanner.pkgName)
+
+	names := make([]string, 0, len(scanner.structs))
+	for name := range scanner.structs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Types left to reflection are listed with the reason
+	generated := []*binderStruct{}
+	for _, name := range names {
+		s := scanner.structs[name]
+		if s.skip != "" {
+			fmt.Fprintf(&buf, "// %s uses reflection: %s.\n", name, s.skip)
+			continue
+		}
+		if s.skipValidation != "" {
+			fmt.Fprintf(&buf, "// %s is validated with the validator: %s.\n", name, s.skipValidation)
+		}
+		generated = append(generated, s)
+	}
+	buf.WriteString("\n")
+
+	// Guard clause: nothing to generate, emit a file that still compiles
+	if len(generated) == 0 {
+		source, err := format.Source(buf.Bytes())
+		if err != nil {
+			return fmt.Errorf("format generated binders: %w", err)
+		}
+		_, err = w.Write(source)
+		return err
+	}
+
+	var body bytes.Buffer
+	gen := &binderGenerator{}
+	gen.writeInit(&body, generated)
+	for _, s := range generated {
+		gen.writeDecode(&body, s)
+		gen.writeEncode(&body, s)
+		if s.skipValidation == "" {
+			gen.writeValidate(&body, s)
+		}
+	}
+
+	imports := []string{`"reflect"`}
+	if gen.usesTime {
+		imports = append(imports, `"time"`)
+	}
+	if gen.usesUTF8 {
+		imports = append(imports, `"unicode/utf8"`)
+	}
+	sort.Strings(imports)
+	fmt.Fprintf(&buf, "import (\n\t%s\n\n\t\"github.com/syntropysoft/syntrogo/src/application\"\n)\n\n", strings.Join(imports, "\n\t"))
+	buf.Write(body.Bytes())
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("format generated binders: %w", err)
+	}
+	_, err = w.Write(source)
+	return err
+}
+
+// scanBinders parses the non-test, non-generated files of a package, collecting
+// type declarations, their methods and the types used as bodies and responses.
+func scanBinders(dir string) (*binderScanner, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := &binderScanner{
+		decls:   map[string]*ast.TypeSpec{},
+		files:   map[string]*ast.File{},
+		methods: map[string]map[string]bool{},
+		roots:   map[string]bool{},
+		structs: map[string]*binderStruct{},
+	}
+	fset := token.NewFileSet()
+	parsed := []*ast.File{}
+	for _, path := range files {
+		base := filepath.Base(path)
+		if strings.HasSuffix(base, "_test.go") || base == DocsFile || base == BindersFile {
+			continue
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		file, err := parser.ParseFile(fset, path, src, 0)
+		if err != nil {
+			return nil, err
+		}
+		scanner.pkgName = file.Name.Name
+		scanner.collectDecls(file)
+		parsed = append(parsed, file)
+	}
+
+	if scanner.pkgName == "" {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+
+	// Roots need every declaration, so they are found in a second pass
+	for _, file := range parsed {
+		scanner.collectRoots(file)
+	}
+	return scanner, nil
+}
+
+// collectDecls records the type declarations and method names of a file.
+func (s *binderScanner) collectDecls(file *ast.File) {
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			if decl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				ts := spec.(*ast.TypeSpec)
+				// Generic types cannot be referenced without instantiation
+				if ts.TypeParams == nil {
+					s.decls[ts.Name.Name] = ts
+					s.files[ts.Name.Name] = file
+				}
+			}
+		case *ast.FuncDecl:
+			if decl.Recv == nil || len(decl.Recv.List) == 0 {
+				continue
+			}
+			recv := decl.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if ident, ok := recv.(*ast.Ident); ok {
+				if s.methods[ident.Name] == nil {
+					s.methods[ident.Name] = map[string]bool{}
+				}
+				s.methods[ident.Name][decl.Name.Name] = true
+			}
+		}
+	}
+}
+
+// collectRoots finds Body(T{}) and Response(status, T{}) calls and Body/Response
+// keys of RouteOptions literals.
+func (s *binderScanner) collectRoots(file *ast.File) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			name := ""
+			switch fun := n.Fun.(type) {
+			case *ast.Ident:
+				name = fun.Name
+			case *ast.SelectorExpr:
+				name = fun.Sel.Name
+			}
+			if name == "Body" || name == "Response" {
+				for _, arg := range n.Args {
+					s.addRoot(arg)
+				}
+			}
+		case *ast.KeyValueExpr:
+			if key, ok := n.Key.(*ast.Ident); ok && (key.Name == "Body" || key.Name == "Response") {
+				s.addRoot(n.Value)
+			}
+		}
+		return true
+	})
+}
+
+// addRoot records the struct type of a value expression: T{}, &T{}, []T{} or new(T).
+func (s *binderScanner) addRoot(expr ast.Expr) {
+	var typ ast.Expr
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		typ = e.Type
+	case *ast.UnaryExpr:
+		s.addRoot(e.X)
+		return
+	case *ast.CallExpr:
+		if ident, ok := e.Fun.(*ast.Ident); ok && ident.Name == "new" && len(e.Args) == 1 {
+			typ = e.Args[0]
+		}
+	}
+
+	for typ != nil {
+		switch t := typ.(type) {
+		case *ast.Ident:
+			if ts, ok := s.decls[t.Name]; ok {
+				if _, isStruct := ts.Type.(*ast.StructType); isStruct {
+					s.roots[t.Name] = true
+				}
+			}
+			return
+		case *ast.StarExpr:
+			typ = t.X
+		case *ast.ArrayType:
+			typ = t.Elt
+		case *ast.MapType:
+			typ = t.Value
+		default:
+			return
+		}
+	}
+}
+
+// analyze resolves the fields of a struct type and the structs it contains.
+func (s *binderScanner) analyze(name string) {
+	// Guard clause: already analyzed (also stops recursive types)
+	if _, done := s.structs[name]; done {
+		return
+	}
+	info := &binderStruct{name: name}
+	s.structs[name] = info
+
+	if s.hasBinderMethods(name) {
+		info.skip = "it implements its own JSON or text encoding"
+		return
+	}
+	st := s.decls[name].Type.(*ast.StructType)
+	file := s.files[name]
+
+	seen := map[string]bool{}
+	for _, field := range st.Fields.List {
+		// Guard clause: promoted fields follow encoding/json's embedding rules
+		if len(field.Names) == 0 {
+			info.skip = "embedded fields are not supported"
+			return
+		}
+
+		tag := reflect.StructTag("")
+		if field.Tag != nil {
+			unquoted, _ := strconv.Unquote(field.Tag.Value)
+			tag = reflect.StructTag(unquoted)
+		}
+
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+
+			f := binderField{goName: ident.Name, validate: tag.Get("validate")}
+			jsonTag := tag.Get("json")
+			options := strings.Split(jsonTag, ",")
+			switch {
+			case jsonTag == "-":
+			case options[0] == "":
+				f.jsonName = ident.Name
+			default:
+				f.jsonName = options[0]
+			}
+			for _, option := range options[1:] {
+				switch option {
+				case "omitempty":
+					f.omitEmpty = true
+				case "string":
+					info.skip = fmt.Sprintf("field %s uses the ,string option", ident.Name)
+					return
+				}
+			}
+			if f.jsonName != "" {
+				if seen[f.jsonName] {
+					info.skip = fmt.Sprintf("json name %q is declared twice", f.jsonName)
+					return
+				}
+				seen[f.jsonName] = true
+			}
+
+			typ, reason := s.resolve(field.Type, file)
+			if reason != "" {
+				// Fields invisible to JSON and the validator do not matter
+				if f.jsonName == "" && f.validate == "" {
+					continue
+				}
+				info.skip = fmt.Sprintf("field %s: %s", ident.Name, reason)
+				return
+			}
+			f.typ = typ
+			info.fields = append(info.fields, f)
+		}
+	}
+
+	for _, f := range info.fields {
+		s.analyzeNested(f.typ)
+		if info.skipValidation == "" {
+			if _, reason := validationCases(f, "v."+f.goName); reason != "" {
+				info.skipValidation = fmt.Sprintf("field %s: %s", f.goName, reason)
+			}
+		}
+	}
+}
+
+// analyzeNested analyzes the structs referenced by a field type.
+func (s *binderScanner) analyzeNested(t *binderType) {
+	for ; t != nil; t = t.elem {
+		if t.kind == "struct" {
+			s.analyze(t.name)
+		}
+	}
+}
+
+// propagate marks structs whose nested structs were not generated, until stable.
+func (s *binderScanner) propagate() {
+	for changed := true; changed; {
+		changed = false
+		for _, info := range s.structs {
+			for _, f := range info.fields {
+				for t := f.typ; t != nil; t = t.elem {
+					if t.kind != "struct" {
+						continue
+					}
+					nested := s.structs[t.name]
+					if info.skip == "" && nested.skip != "" {
+						info.skip = fmt.Sprintf("field %s: %s uses reflection", f.goName, t.name)
+						changed = true
+					}
+					// Only directly contained structs are validated (no dive)
+					direct := t == f.typ || (t == f.typ.elem && f.typ.kind == "ptr")
+					if direct && info.skipValidation == "" && (nested.skip != "" || nested.skipValidation != "") {
+						info.skipValidation = fmt.Sprintf("field %s: %s is validated with the validator", f.goName, t.name)
+						changed = true
+					}
+				}
+			}
+		}
+	}
+}
+
+// hasBinderMethods reports whether a type customizes its encoding.
+func (s *binderScanner) hasBinderMethods(name string) bool {
+	for _, method := range binderMethods {
+		if s.methods[name][method] {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve maps a field type expression to a binderType, or explains why it cannot.
+func (s *binderScanner) resolve(expr ast.Expr, file *ast.File) (*binderType, string) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if t := builtinType(e.Name); t != nil {
+			return t, ""
+		}
+		ts, ok := s.decls[e.Name]
+		if !ok {
+			return nil, fmt.Sprintf("type %s is not supported", e.Name)
+		}
+		if s.hasBinderMethods(e.Name) {
+			return nil, fmt.Sprintf("%s implements its own JSON or text encoding", e.Name)
+		}
+		if _, isStruct := ts.Type.(*ast.StructType); isStruct {
+			return &binderType{kind: "struct", name: e.Name}, ""
+		}
+		// Named scalars (type Status string) convert to and from their builtin
+		underlying, reason := s.resolve(ts.Type, s.files[e.Name])
+		if reason != "" {
+			return nil, reason
+		}
+		if underlying.base == "" {
+			return nil, fmt.Sprintf("type %s is not a struct or a scalar", e.Name)
+		}
+		named := *underlying
+		named.name = e.Name
+		return &named, ""
+	case *ast.SelectorExpr:
+		if pkg, ok := e.X.(*ast.Ident); ok && e.Sel.Name == "Time" && importPath(file, pkg.Name) == "time" {
+			return &binderType{kind: "time"}, ""
+		}
+		return nil, "types from other packages are not supported"
+	case *ast.StarExpr:
+		elem, reason := s.resolve(e.X, file)
+		if reason != "" {
+			return nil, reason
+		}
+		return &binderType{kind: "ptr", elem: elem}, ""
+	case *ast.ArrayType:
+		if e.Len != nil {
+			return nil, "arrays are not supported"
+		}
+		if ident, ok := e.Elt.(*ast.Ident); ok && (ident.Name == "byte" || ident.Name == "uint8") {
+			return &binderType{kind: "bytes"}, ""
+		}
+		elem, reason := s.resolve(e.Elt, file)
+		if reason != "" {
+			return nil, reason
+		}
+		return &binderType{kind: "slice", elem: elem}, ""
+	case *ast.MapType:
+		if ident, ok := e.Key.(*ast.Ident); !ok || ident.Name != "string" {
+			return nil, "only map[string] keys are supported"
+		}
+		elem, reason := s.resolve(e.Value, file)
+		if reason != "" {
+			return nil, reason
+		}
+		return &binderType{kind: "map", elem: elem}, ""
+	case *ast.InterfaceType:
+		return nil, "interface types are not supported"
+	}
+	return nil, "type is not supported"
+}
+
+// builtinType returns the scalar for a predeclared type name.
+func builtinType(name string) *binderType {
+	if bits, ok := intTypes[name]; ok {
+		return &binderType{kind: "int", bits: bits, base: name}
+	}
+	if bits, ok := uintTypes[name]; ok {
+		return &binderType{kind: "uint", bits: bits, base: name}
+	}
+	if bits, ok := floatTypes[name]; ok {
+		return &binderType{kind: "float", bits: bits, base: name}
+	}
+	switch name {
+	case "string", "bool":
+		return &binderType{kind: name, base: name}
+	}
+	return nil
+}
+
+// importPath returns the path imported under name in a file.
+func importPath(file *ast.File, name string) string {
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		local := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			local = spec.Name.Name
+		}
+		if local == name {
+			return path
+		}
+	}
+	return ""
+}
+
+// binderGenerator writes the functions of generated types.
+type binderGenerator struct {
+	usesTime bool
+	usesUTF8 bool
+}
+
+// writeInit registers the generated code with the adapter.
+func (g *binderGenerator) writeInit(buf *bytes.Buffer, structs []*binderStruct) {
+	fmt.Fprintf(buf, "func init() {\n")
+	for _, s := range structs {
+		fmt.Fprintf(buf, "\tapplication.RegisterTypeCodec(reflect.TypeOf((*%s)(nil)).Elem(), application.TypeCodec{\n", s.name)
+		fmt.Fprintf(buf, "\t\tDecode: func(r *application.JSONReader, v interface{}) error { return syntrogoDecode%s(r, v.(*%s)) },\n", s.name, s.name)
+		fmt.Fprintf(buf, "\t\tEncode: func(w *application.JSONWriter, v interface{}) {\n")
+		fmt.Fprintf(buf, "\t\t\tif p := syntrogoPtr%s(v); p != nil {\n\t\t\t\tsyntrogoEncode%s(w, p)\n\t\t\t} else {\n\t\t\t\tw.Null()\n\t\t\t}\n\t\t},\n", s.name, s.name)
+		if s.skipValidation == "" {
+			fmt.Fprintf(buf, "\t\tValidate: func(v interface{}) error {\n")
+			fmt.Fprintf(buf, "\t\t\terrs := &application.FieldErrors{}\n")
+			fmt.Fprintf(buf, "\t\t\tif p := syntrogoPtr%s(v); p != nil {\n\t\t\t\tsyntrogoValidate%s(p, %q, errs)\n\t\t\t}\n", s.name, s.name, s.name)
+			fmt.Fprintf(buf, "\t\t\treturn errs.Err()\n\t\t},\n")
+		}
+		fmt.Fprintf(buf, "\t})\n")
+	}
+	fmt.Fprintf(buf, "}\n\n")
+
+	for _, s := range structs {
+		fmt.Fprintf(buf, "// syntrogoPtr%s accepts %s or *%s.\n", s.name, s.name, s.name)
+		fmt.Fprintf(buf, "func syntrogoPtr%s(v interface{}) *%s {\n\tif p, ok := v.(*%s); ok {\n\t\treturn p\n\t}\n\tvalue := v.(%s)\n\treturn &value\n}\n\n", s.name, s.name, s.name, s.name)
+	}
+}
+
+// writeDecode writes the decoder of a struct.
+func (g *binderGenerator) writeDecode(buf *bytes.Buffer, s *binderStruct) {
+	keys := []string{}
+	for _, f := range s.fields {
+		if f.jsonName != "" {
+			keys = append(keys, strconv.Quote(f.jsonName))
+		}
+	}
+
+	fmt.Fprintf(buf, "func syntrogoDecode%s(r *application.JSONReader, v *%s) error {\n", s.name, s.name)
+	if len(keys) == 0 {
+		fmt.Fprintf(buf, "\treturn r.Object(func(string) error { return r.Skip() })\n}\n\n")
+		return
+	}
+
+	fmt.Fprintf(buf, "\treturn r.Object(func(key string) error {\n")
+	fmt.Fprintf(buf, "\t\tswitch application.MatchKey(key, syntrogo%sKeys) {\n", s.name)
+	for _, f := range s.fields {
+		if f.jsonName == "" {
+			continue
+		}
+		fmt.Fprintf(buf, "\t\tcase %s:\n", strconv.Quote(f.jsonName))
+		buf.WriteString(g.decodeInto(f.typ, "v."+f.goName, 0))
+	}
+	fmt.Fprintf(buf, "\t\tdefault:\n\t\t\treturn r.Skip()\n\t\t}\n\t\treturn nil\n\t})\n}\n\n")
+	fmt.Fprintf(buf, "var syntrogo%sKeys = []string{%s}\n\n", s.name, strings.Join(keys, ", "))
+}
+
+// decodeInto returns statements reading the next value into dst.
+// They run inside a func() error and return on failure. Pointers and maps are
+// filled through copies, so a failed decode never writes to values the caller shares.
+func (g *binderGenerator) decodeInto(t *binderType, dst string, depth int) string {
+	x, e := fmt.Sprintf("x%d", depth), fmt.Sprintf("e%d", depth)
+	switch t.kind {
+	case "string", "bool", "int", "uint", "float":
+		read := map[string]string{
+			"string": "r.String()",
+			"bool":   "r.Bool()",
+			"int":    fmt.Sprintf("r.Int(%d)", t.bits),
+			"uint":   fmt.Sprintf("r.Uint(%d)", t.bits),
+			"float":  fmt.Sprintf("r.Float(%d)", t.bits),
+		}[t.kind]
+		same := t.name == "" && (t.kind == "string" || t.kind == "bool" || t.base == "int64" || t.base == "uint64" || t.base == "float64")
+		return fmt.Sprintf("if !r.Null() {\n%s, err := %s\nif err != nil {\nreturn err\n}\n%s = %s\n}\n", x, read, dst, convert(g.typeExpr(t), x, same))
+	case "time":
+		return fmt.Sprintf("%s, err := r.Raw()\nif err != nil {\nreturn err\n}\nif err := %s.UnmarshalJSON(%s); err != nil {\nreturn err\n}\n", x, paren(dst), x)
+	case "bytes":
+		return fmt.Sprintf("if r.Null() {\n%s = nil\n} else {\n%s, err := r.Bytes()\nif err != nil {\nreturn err\n}\n%s = %s\n}\n", dst, x, dst, x)
+	case "ptr":
+		// Decode into a copy: the value pointed to is shared with the caller's target
+		p := fmt.Sprintf("p%d", depth)
+		return fmt.Sprintf("if r.Null() {\n%s = nil\n} else {\n%s := new(%s)\nif %s != nil {\n%s = %s\n}\n%s%s = %s\n}\n",
+			dst, p, g.typeExpr(t.elem), dst, "*"+p, "*"+paren(dst), g.decodeInto(t.elem, "*"+p, depth+1), dst, p)
+	case "slice":
+		return fmt.Sprintf("if r.Null() {\n%s = nil\n} else {\n%s := %s{}\nif err := r.Array(func() error {\nvar %s %s\n%s%s = append(%s, %s)\nreturn nil\n}); err != nil {\nreturn err\n}\n%s = %s\n}\n",
+			dst, x, g.typeExpr(t), e, g.typeExpr(t.elem), g.decodeInto(t.elem, e, depth+1), x, x, e, dst, x)
+	case "map":
+		k := fmt.Sprintf("k%d", depth)
+		return fmt.Sprintf("if r.Null() {\n%s = nil\n} else {\n%s := make(%s, len(%s))\nfor %s, %s := range %s {\n%s[%s] = %s\n}\nif err := r.Object(func(%s string) error {\nvar %s %s\n%s%s[%s] = %s\nreturn nil\n}); err != nil {\nreturn err\n}\n%s = %s\n}\n",
+			dst, x, g.typeExpr(t), dst, k, e, dst, x, k, e, k, e, g.typeExpr(t.elem), g.decodeInto(t.elem, e, depth+1), x, k, e, dst, x)
+	case "struct":
+		return fmt.Sprintf("if err := syntrogoDecode%s(r, %s); err != nil {\nreturn err\n}\n", t.name, addr(dst))
+	}
+	return ""
+}
+
+// writeEncode writes the encoder of a struct.
+func (g *binderGenerator) writeEncode(buf *bytes.Buffer, s *binderStruct) {
+	fmt.Fprintf(buf, "func syntrogoEncode%s(w *application.JSONWriter, v *%s) {\n", s.name, s.name)
+
+	fields := []binderField{}
+	for _, f := range s.fields {
+		if f.jsonName != "" {
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) == 0 {
+		fmt.Fprintf(buf, "\tw.Raw(\"{}\")\n}\n\n")
+		return
+	}
+
+	fmt.Fprintf(buf, "\tmore := false\n\tw.Raw(\"{\")\n")
+	for _, f := range fields {
+		src := "v." + f.goName
+		key, _ := json.Marshal(f.jsonName)
+		member := fmt.Sprintf("w.Field(&more, %s)\n%s", strconv.Quote(string(key)+":"), g.encodeValue(f.typ, src, 0))
+		if cond := nonEmpty(f.typ, src); f.omitEmpty && cond != "" {
+			fmt.Fprintf(buf, "if %s {\n%s}\n", cond, member)
+			continue
+		}
+		buf.WriteString(member)
+	}
+	fmt.Fprintf(buf, "\tw.Raw(\"}\")\n}\n\n")
+}
+
+// encodeValue returns statements writing src.
+func (g *binderGenerator) encodeValue(t *binderType, src string, depth int) string {
+	i, e := fmt.Sprintf("i%d", depth), fmt.Sprintf("e%d", depth)
+	switch t.kind {
+	case "string":
+		return fmt.Sprintf("w.String(%s)\n", convert("string", src, t.name == ""))
+	case "bool":
+		return fmt.Sprintf("w.Bool(%s)\n", convert("bool", src, t.name == ""))
+	case "int":
+		return fmt.Sprintf("w.Int(int64(%s))\n", src)
+	case "uint":
+		return fmt.Sprintf("w.Uint(uint64(%s))\n", src)
+	case "float":
+		return fmt.Sprintf("w.Float(float64(%s), %d)\n", src, t.bits)
+	case "time":
+		return fmt.Sprintf("w.JSON(%s.MarshalJSON())\n", paren(src))
+	case "bytes":
+		return fmt.Sprintf("w.Bytes(%s)\n", src)
+	case "ptr":
+		return fmt.Sprintf("if %s == nil {\nw.Null()\n} else {\n%s}\n", src, g.encodeValue(t.elem, "*"+src, depth+1))
+	case "slice":
+		return fmt.Sprintf("if %s == nil {\nw.Null()\n} else {\nw.Raw(\"[\")\nfor %s, %s := range %s {\nif %s > 0 {\nw.Raw(\",\")\n}\n%s}\nw.Raw(\"]\")\n}\n",
+			src, i, e, src, i, g.encodeValue(t.elem, e, depth+1))
+	case "map":
+		k := fmt.Sprintf("k%d", depth)
+		return fmt.Sprintf("if %s == nil {\nw.Null()\n} else {\nw.Raw(\"{\")\nfor %s, %s := range application.SortedKeys(%s) {\nif %s > 0 {\nw.Raw(\",\")\n}\nw.String(%s)\nw.Raw(\":\")\n%s := %s[%s]\n%s}\nw.Raw(\"}\")\n}\n",
+			src, i, k, src, i, k, e, paren(src), k, g.encodeValue(t.elem, e, depth+1))
+	case "struct":
+		return fmt.Sprintf("syntrogoEncode%s(w, %s)\n", t.name, addr(src))
+	}
+	return ""
+}
+
+// writeValidate writes the validator of a struct: each field's rules in a switch,
+// stopping at the first failure like go-playground/validator, then nested structs.
+func (g *binderGenerator) writeValidate(buf *bytes.Buffer, s *binderStruct) {
+	fmt.Fprintf(buf, "func syntrogoValidate%s(v *%s, ns string, errs *application.FieldErrors) {\n", s.name, s.name)
+	for _, f := range s.fields {
+		src := "v." + f.goName
+		cases, _ := validationCases(f, src)
+		if len(cases) > 0 {
+			fmt.Fprintf(buf, "switch {\n")
+			for _, c := range cases {
+				if strings.Contains(c.cond, "utf8.") {
+					g.usesUTF8 = true
+				}
+				fmt.Fprintf(buf, "case %s:\n", c.cond)
+				if c.tag != "" {
+					fmt.Fprintf(buf, "errs.Add(ns+%q, %q, %q)\n", "."+f.goName, f.goName, c.tag)
+				}
+			}
+			fmt.Fprintf(buf, "}\n")
+		}
+
+		// Nested structs are validated without a tag, nil pointers skipped
+		switch {
+		case f.validate == "" && f.typ.kind == "struct":
+			fmt.Fprintf(buf, "syntrogoValidate%s(&%s, ns+%q, errs)\n", f.typ.name, src, "."+f.goName)
+		case f.validate == "" && f.typ.kind == "ptr" && f.typ.elem.kind == "struct":
+			fmt.Fprintf(buf, "if %s != nil {\nsyntrogoValidate%s(%s, ns+%q, errs)\n}\n", src, f.typ.elem.name, src, "."+f.goName)
+		}
+	}
+	fmt.Fprintf(buf, "}\n\n")
+}
+
+// typeExpr renders a type in the generated file.
+func (g *binderGenerator) typeExpr(t *binderType) string {
+	switch t.kind {
+	case "time":
+		g.usesTime = true
+		return "time.Time"
+	case "bytes":
+		return "[]byte"
+	case "ptr":
+		return "*" + g.typeExpr(t.elem)
+	case "slice":
+		return "[]" + g.typeExpr(t.elem)
+	case "map":
+		return "map[string]" + g.typeExpr(t.elem)
+	}
+	if t.name != "" {
+		return t.name
+	}
+	return t.base
+}
+
+// validationCase is one rule: a failing condition and the tag reported ("" to stop silently).
+type validationCase struct {
+	cond string
+	tag  string
+}
+
+// validationCases compiles a validate tag into switch cases, or explains which
+// part needs go-playground/validator.
+func validationCases(f binderField, src string) ([]validationCase, string) {
+	// Guard clause: no rules
+	if f.validate == "" || f.validate == "-" {
+		return nil, ""
+	}
+	if strings.ContainsAny(f.validate, "|'") {
+		return nil, "alternative rules (|) are not supported"
+	}
+
+	t := f.typ
+	rules := strings.Split(f.validate, ",")
+	cases := []validationCase{}
+	if t.kind == "ptr" {
+		switch rules[0] {
+		case "required":
+			cases = append(cases, validationCase{cond: src + " == nil", tag: "required"})
+		case "omitempty":
+			cases = append(cases, validationCase{cond: src + " == nil"})
+		default:
+			return nil, "pointer rules must start with required or omitempty"
+		}
+		rules, t, src = rules[1:], t.elem, "*"+src
+	}
+	if t.kind == "struct" || t.kind == "time" || t.kind == "ptr" {
+		return nil, "rules on struct, time and nested pointer fields are not supported"
+	}
+
+	for i, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		switch {
+		case name == "omitempty" && i == 0:
+			cases = append(cases, validationCase{cond: isZero(t, src)})
+		case name == "required":
+			cases = append(cases, validationCase{cond: isZero(t, src), tag: "required"})
+		default:
+			cond, reason := ruleCondition(t, src, name, param)
+			if reason != "" {
+				return nil, reason
+			}
+			cases = append(cases, validationCase{cond: cond, tag: name})
+		}
+	}
+	return cases, ""
+}
+
+// ruleCondition returns the failing condition of one parameterized rule.
+func ruleCondition(t *binderType, src, name, param string) (string, string) {
+	unsupported := fmt.Sprintf("rule %q is not supported on %s", name, t.kind)
+	comparisons := map[string]string{"min": "<", "max": ">", "len": "!=", "gt": "<=", "gte": "<", "lt": ">=", "lte": ">", "eq": "!=", "ne": "=="}
+
+	// Guard clause: bools only take required and omitempty
+	if t.kind == "bool" {
+		return "", unsupported
+	}
+
+	if name == "oneof" {
+		values := strings.Fields(param)
+		if len(values) == 0 {
+			return "", unsupported
+		}
+		conds := []string{}
+		for _, value := range values {
+			switch t.kind {
+			case "string":
+				conds = append(conds, fmt.Sprintf("%s != %s", convert("string", src, t.name == ""), strconv.Quote(value)))
+			case "int", "uint":
+				literal, ok := numberLiteral(t.kind, value)
+				if !ok {
+					return "", fmt.Sprintf("oneof value %q is not a number", value)
+				}
+				conds = append(conds, fmt.Sprintf("%s != %s", numberExpr(t, src), literal))
+			default:
+				return "", unsupported
+			}
+		}
+		return strings.Join(conds, " && "), ""
+	}
+
+	op, ok := comparisons[name]
+	if !ok || param == "" {
+		return "", unsupported
+	}
+
+	// String equality compares the value, every other string rule its length
+	if t.kind == "string" && (name == "eq" || name == "ne") {
+		return fmt.Sprintf("%s %s %s", convert("string", src, t.name == ""), op, strconv.Quote(param)), ""
+	}
+
+	switch t.kind {
+	case "string", "slice", "map", "bytes":
+		literal, ok := numberLiteral("int", param)
+		if !ok {
+			return "", fmt.Sprintf("rule %s=%s needs an integer", name, param)
+		}
+		size := fmt.Sprintf("len(%s)", src)
+		if t.kind == "string" {
+			size = fmt.Sprintf("utf8.RuneCountInString(%s)", convert("string", src, t.name == ""))
+		}
+		return fmt.Sprintf("%s %s %s", size, op, literal), ""
+	case "int", "uint", "float":
+		literal, ok := numberLiteral(t.kind, param)
+		if !ok {
+			return "", fmt.Sprintf("rule %s=%s needs a number", name, param)
+		}
+		return fmt.Sprintf("%s %s %s", numberExpr(t, src), op, literal), ""
+	}
+	return "", unsupported
+}
+
+// numberLiteral parses a rule parameter the way the validator does.
+func numberLiteral(kind, param string) (string, bool) {
+	switch kind {
+	case "int":
+		n, err := strconv.ParseInt(param, 0, 64)
+		return strconv.FormatInt(n, 10), err == nil
+	case "uint":
+		n, err := strconv.ParseUint(param, 0, 64)
+		return strconv.FormatUint(n, 10), err == nil
+	case "float":
+		f, err := strconv.ParseFloat(param, 64)
+		return strconv.FormatFloat(f, 'g', -1, 64), err == nil
+	}
+	return "", false
+}
+
+// numberExpr widens a numeric field for comparison with a rule parameter.
+func numberExpr(t *binderType, src string) string {
+	return map[string]string{"int": "int64", "uint": "uint64", "float": "float64"}[t.kind] + "(" + src + ")"
+}
+
+// isZero is the condition for an empty value (required, omitempty).
+func isZero(t *binderType, src string) string {
+	switch t.kind {
+	case "string":
+		return src + ` == ""`
+	case "bool":
+		return "!" + src
+	case "slice", "map", "bytes", "ptr":
+		return src + " == nil"
+	}
+	return src + " == 0"
+}
+
+// nonEmpty is the omitempty condition of encoding/json; "" when always written.
+func nonEmpty(t *binderType, src string) string {
+	switch t.kind {
+	case "string", "slice", "map", "bytes":
+		return "len(" + src + ") != 0"
+	case "bool":
+		return src
+	case "int", "uint", "float":
+		return src + " != 0"
+	case "ptr":
+		return src + " != nil"
+	}
+	return ""
+}
+
+// convert wraps src in a conversion unless it already has the type.
+func convert(typ, src string, same bool) string {
+	if same {
+		return src
+	}
+	return typ + "(" + src + ")"
+}
+
+// paren protects a dereference before a selector or index: (*v.X).Method().
+func paren(expr string) string {
+	if strings.HasPrefix(expr, "*") {
+		return "(" + expr + ")"
+	}
+	return expr
+}
+
+// addr takes the address of an expression; &*p is p.
+func addr(expr string) string {
+	if strings.HasPrefix(expr, "*") {
+		return expr[1:]
+	}
+	return "&" + expr
+}
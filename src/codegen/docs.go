@@ -0,0 +1,163 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DocsFile is the default name of the file written by `syntrogo gen docs`.
+const DocsFile = "syntrogo_docs.go"
+
+// typeComments holds the doc comments found for one type declaration.
+type typeComments struct {
+	name   string
+	doc    string
+	fields map[string]string
+}
+
+// GenerateDocs reads the Go package in dir and writes a file that registers
+// its type and field doc comments with the OpenAPI generator.
+// Usage: //go:generate go run github.com/syntropysoft/syntrogo/cmd/syntrogo gen docs
+func GenerateDocs(dir string, w io.Writer) error {
+	pkgName, types, err := collectDocs(dir)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by syntrogo gen docs. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkgName)
+
+	// Guard clause: nothing documented, emit an empty file that still compiles
+	if len(types) == 0 {
+		_, err := w.Write(buf.Bytes())
+		return err
+	}
+
+	fmt.Fprintf(&buf, "import (\n\t\"reflect\"\n\n\t\"github.com/syntropysoft/syntrogo/src/application\"\n)\n\n")
+	fmt.Fprintf(&buf, "func init() {\n")
+	for _, t := range types {
+		fmt.Fprintf(&buf, "\tapplication.RegisterTypeDocs(reflect.TypeOf((*%s)(nil)).Elem(), %s, map[string]string{\n", t.name, strconv.Quote(t.doc))
+
+		names := make([]string, 0, len(t.fields))
+		for name := range t.fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(&buf, "\t\t%s: %s,\n", strconv.Quote(name), strconv.Quote(t.fields[name]))
+		}
+		fmt.Fprintf(&buf, "\t})\n")
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("format generated docs: %w", err)
+	}
+	_, err = w.Write(source)
+	return err
+}
+
+// collectDocs parses the non-test, non-generated files of a package.
+// Types are returned sorted by name so the output is stable.
+func collectDocs(dir string) (string, []typeComments, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", nil, err
+	}
+
+	fset := token.NewFileSet()
+	pkgName := ""
+	types := []typeComments{}
+	for _, path := range files {
+		base := filepath.Base(path)
+		if strings.HasSuffix(base, "_test.go") || base == DocsFile {
+			continue
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return "", nil, err
+		}
+		file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			return "", nil, err
+		}
+		pkgName = file.Name.Name
+
+		types = append(types, fileDocs(file)...)
+	}
+
+	if pkgName == "" {
+		return "", nil, fmt.Errorf("no Go files in %s", dir)
+	}
+
+	sort.Slice(types, func(i, j int) bool { return types[i].name < types[j].name })
+	return pkgName, types, nil
+}
+
+// fileDocs extracts documented type declarations from a parsed file.
+func fileDocs(file *ast.File) []typeComments {
+	types := []typeComments{}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+
+			// Generic types cannot be referenced without instantiation
+			if ts.TypeParams != nil {
+				continue
+			}
+
+			doc := commentText(ts.Doc)
+			if doc == "" && len(gen.Specs) == 1 {
+				doc = commentText(gen.Doc)
+			}
+
+			fields := map[string]string{}
+			if st, ok := ts.Type.(*ast.StructType); ok {
+				for _, field := range st.Fields.List {
+					text := commentText(field.Doc)
+					if text == "" {
+						text = commentText(field.Comment)
+					}
+					if text == "" {
+						continue
+					}
+					for _, name := range field.Names {
+						fields[name.Name] = text
+					}
+				}
+			}
+
+			if doc == "" && len(fields) == 0 {
+				continue
+			}
+			types = append(types, typeComments{name: ts.Name.Name, doc: doc, fields: fields})
+		}
+	}
+	return types
+}
+
+// commentText returns a comment group as trimmed text.
+func commentText(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+	return strings.TrimSpace(group.Text())
+}
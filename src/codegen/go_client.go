@@ -0,0 +1,390 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// clientImports are always needed by the generated client runtime.
+var clientImports = []string{
+	"bytes",
+	"context",
+	"encoding/json",
+	"fmt",
+	"io",
+	"net/http",
+	"net/url",
+	"reflect",
+	"strings",
+}
+
+// clientLocals are the identifiers generated methods already use: the receiver,
+// the fixed arguments and the runtime's request type. Path parameters with these
+// names, or the names of imported packages, get a suffix.
+var clientLocals = map[string]bool{
+	"c": true, "ctx": true, "query": true, "headers": true, "cookies": true,
+	"body": true, "out": true, "err": true, "request": true,
+}
+
+// clientRuntime is the static part of every generated client.
+const clientRuntime = `// Doer sends HTTP requests. *http.Client implements it; plug in your own
+// for retries, tracing or tests.
+type Doer interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Client calls the API. Safe for concurrent use.
+type Client struct {
+	baseURL string
+	http    Doer
+	headers http.Header
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used to send requests (default http.DefaultClient).
+func WithHTTPClient(doer Doer) Option {
+	return func(c *Client) {
+		c.http = doer
+	}
+}
+
+// WithHeader adds a header to every request, e.g. Authorization.
+func WithHeader(name, value string) Option {
+	return func(c *Client) {
+		c.headers.Add(name, value)
+	}
+}
+
+// NewClient creates a client for the API served at baseURL.
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    http.DefaultClient,
+		headers: http.Header{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError is returned for non-2xx responses.
+// Message is read from the server's {"error": "..."} body.
+type APIError struct {
+	StatusCode int
+	Message    string ` + "`json:\"error\"`" + `
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%d: %s", e.StatusCode, e.Message)
+}
+
+// request describes one API call.
+type request struct {
+	method  string
+	path    string
+	query   interface{}
+	headers interface{}
+	cookies interface{}
+	body    interface{}
+	out     interface{}
+}
+
+// do sends a request and decodes the response into r.out.
+func (c *Client) do(ctx context.Context, r request) error {
+	var body io.Reader
+	if r.body != nil {
+		data, err := json.Marshal(r.body)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	target := c.baseURL + r.path
+	if r.query != nil {
+		if query := url.Values(encodeParams(r.query, "query")).Encode(); query != "" {
+			target += "?" + query
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, target, body)
+	if err != nil {
+		return err
+	}
+	for name, values := range c.headers {
+		req.Header[name] = append([]string(nil), values...)
+	}
+	if r.body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if r.headers != nil {
+		for name, values := range encodeParams(r.headers, "header") {
+			for _, value := range values {
+				req.Header.Add(name, value)
+			}
+		}
+	}
+	if r.cookies != nil {
+		for name, values := range encodeParams(r.cookies, "cookie") {
+			for _, value := range values {
+				req.AddCookie(&http.Cookie{Name: name, Value: value})
+			}
+		}
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Guard clause: errors use the framework's {"error": "..."} format
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		data, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(data, apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(data))
+		}
+		return apiErr
+	}
+
+	if r.out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(r.out)
+}
+
+// encodeParams reads ` + "`query`, `header` or `cookie`" + ` tags into string values.
+// Zero values are skipped; slices become repeated values.
+func encodeParams(v interface{}, tag string) map[string][]string {
+	values := map[string][]string{}
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return values
+	}
+
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get(tag), ",")[0]
+		field := rv.Field(i)
+		if name == "" || name == "-" || field.IsZero() {
+			continue
+		}
+
+		field = reflect.Indirect(field)
+		if field.Kind() == reflect.Slice {
+			for j := 0; j < field.Len(); j++ {
+				values[name] = append(values[name], fmt.Sprint(field.Index(j).Interface()))
+			}
+			continue
+		}
+		values[name] = append(values[name], fmt.Sprint(field.Interface()))
+	}
+	return values
+}
+`
+
+// GenerateGoClient writes a typed Go client package for the given routes.
+// Body, response and parameter types are mirrored into the package, and each
+// route becomes one method taking a context.Context.
+func GenerateGoClient(pkg string, routes []*domain.Route, w io.Writer) error {
+	// Guard clause: package name is required
+	if pkg == "" {
+		return fmt.Errorf("package name is required")
+	}
+
+	// WebSocket routes cannot be called over plain HTTP; hidden routes are not API
+	httpRoutes := make([]*domain.Route, 0, len(routes))
+	for _, route := range routes {
+		if route.Options.WebSocket == nil && !route.Options.Hidden {
+			httpRoutes = append(httpRoutes, route)
+		}
+	}
+	routes = httpRoutes
+
+	mirror := newGoTypeMirror()
+	var methods bytes.Buffer
+	names := uniqueNames(routes)
+	for i, route := range routes {
+		writeClientMethod(&methods, mirror, names[i], route)
+	}
+	types := mirror.declarations()
+
+	imports := map[string]bool{}
+	for _, imp := range clientImports {
+		imports[imp] = true
+	}
+	for _, imp := range mirror.importList() {
+		imports[imp] = true
+	}
+	importList := make([]string, 0, len(imports))
+	for imp := range imports {
+		importList = append(importList, strconv.Quote(imp))
+	}
+	sort.Strings(importList)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by syntrogo. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "// Package %s is a typed client for the API.\n", pkg)
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import (\n\t%s\n)\n\n", strings.Join(importList, "\n\t"))
+	buf.WriteString(clientRuntime)
+	buf.WriteString("\n")
+	buf.Write(methods.Bytes())
+	buf.WriteString(types)
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("format generated client: %w", err)
+	}
+	_, err = w.Write(source)
+	return err
+}
+
+// writeClientMethod writes the client method for one route.
+func writeClientMethod(buf *bytes.Buffer, mirror *goTypeMirror, name string, route *domain.Route) {
+	opts := route.Options
+	args := []string{"ctx context.Context"}
+	params := paramIdents(pathParams(route.Path), clientReserved(mirror), goIdent)
+	fields := []string{
+		fmt.Sprintf("method: %q", route.Method),
+		"path: " + clientPathExpr(route.Path, params),
+	}
+
+	for _, param := range params {
+		args = append(args, param+" string")
+	}
+	if opts.Query != nil {
+		args = append(args, "query "+mirror.expr(pointerTo(opts.Query)))
+		fields = append(fields, "query: query")
+	}
+	if opts.Headers != nil {
+		args = append(args, "headers "+mirror.expr(pointerTo(opts.Headers)))
+		fields = append(fields, "headers: headers")
+	}
+	if opts.Cookies != nil {
+		args = append(args, "cookies "+mirror.expr(pointerTo(opts.Cookies)))
+		fields = append(fields, "cookies: cookies")
+	}
+	if opts.Body != nil {
+		args = append(args, "body "+mirror.expr(pointerTo(opts.Body)))
+		fields = append(fields, "body: body")
+	}
+
+	// Doc comment: summary, description, deprecation
+	doc := opts.Summary
+	if doc == "" {
+		doc = "calls " + route.Method + " " + route.Path + "."
+	}
+	fmt.Fprintf(buf, "// %s %s\n", name, doc)
+	if opts.Description != "" {
+		for _, line := range strings.Split(opts.Description, "\n") {
+			fmt.Fprintf(buf, "// %s\n", line)
+		}
+	}
+	if opts.Deprecated {
+		fmt.Fprintf(buf, "//\n// Deprecated: the API marks this operation as deprecated.\n")
+	}
+
+	// Guard clause: no response type, only an error is returned
+	if opts.Response == nil {
+		fmt.Fprintf(buf, "func (c *Client) %s(%s) error {\n", name, strings.Join(args, ", "))
+		fmt.Fprintf(buf, "\treturn c.do(ctx, request{%s})\n}\n\n", strings.Join(fields, ", "))
+		return
+	}
+
+	t := reflect.TypeOf(opts.Response)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	out := mirror.expr(t)
+	fields = append(fields, "out: &out")
+
+	// Slices and maps are returned by value, structs by pointer
+	result, ret := "*"+out, "&out"
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+		result, ret = out, "out"
+	}
+	fmt.Fprintf(buf, "func (c *Client) %s(%s) (%s, error) {\n", name, strings.Join(args, ", "), result)
+	fmt.Fprintf(buf, "\tvar out %s\n", out)
+	fmt.Fprintf(buf, "\tif err := c.do(ctx, request{%s}); err != nil {\n\t\treturn nil, err\n\t}\n", strings.Join(fields, ", "))
+	fmt.Fprintf(buf, "\treturn %s, nil\n}\n\n", ret)
+}
+
+// clientReserved returns the identifiers path parameters must not shadow.
+func clientReserved(mirror *goTypeMirror) map[string]bool {
+	reserved := make(map[string]bool, len(clientLocals)+len(clientImports))
+	for name := range clientLocals {
+		reserved[name] = true
+	}
+	for _, imp := range clientImports {
+		reserved[path.Base(imp)] = true
+	}
+	for _, imp := range mirror.importList() {
+		reserved[path.Base(imp)] = true
+	}
+	return reserved
+}
+
+// clientPathExpr builds the Go expression for a path with escaped parameters,
+// named by params in order. "/users/:id" becomes "/users/" + url.PathEscape(id).
+func clientPathExpr(path string, params []string) string {
+	parts := []string{}
+	literal := ""
+	for _, segment := range strings.Split(path, "/")[1:] {
+		literal += "/"
+		if strings.HasPrefix(segment, ":") {
+			parts = append(parts, strconv.Quote(literal), "url.PathEscape("+params[0]+")")
+			params = params[1:]
+			literal = ""
+			continue
+		}
+		literal += segment
+	}
+	if literal != "" || len(parts) == 0 {
+		parts = append(parts, strconv.Quote(literal))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// goIdent turns a path parameter name into an unexported Go identifier.
+func goIdent(name string) string {
+	ident := exportedName(name)
+	if ident == "" {
+		return "param"
+	}
+	// Lower-case the leading word: "UserID" -> "userID", "ID" -> "id"
+	words := splitWords(ident)
+	words[0] = strings.ToLower(words[0])
+	ident = strings.Join(words, "")
+
+	// Parameters named after keywords (:type, :func) get a suffix
+	if token.IsKeyword(ident) {
+		ident += "Param"
+	}
+	return ident
+}
+
+// pointerTo returns the pointer type of a value's type, keeping existing pointers.
+func pointerTo(v interface{}) reflect.Type {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr {
+		return t
+	}
+	return reflect.PointerTo(t)
+}
@@ -0,0 +1,162 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/build"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// keptTags are the struct tags copied into generated types.
+// Validation and docs stay on the server; the client only needs wire names.
+var keptTags = []string{"json", "query", "header", "cookie", "form"}
+
+// goTypeMirror re-declares the Go types reachable from route bodies and responses
+// so a generated package compiles without importing the server's packages.
+type goTypeMirror struct {
+	imports map[string]bool         // Standard library packages referenced (time, encoding/json)
+	named   map[string]reflect.Type // Type name -> type to declare
+	order   []string                // Declaration order (discovery order)
+}
+
+// newGoTypeMirror creates an empty mirror.
+func newGoTypeMirror() *goTypeMirror {
+	return &goTypeMirror{
+		imports: map[string]bool{},
+		named:   map[string]reflect.Type{},
+	}
+}
+
+// expr returns the Go expression for t, registering named types to declare.
+func (m *goTypeMirror) expr(t reflect.Type) string {
+	// Guard clause: untyped values
+	if t == nil {
+		return "interface{}"
+	}
+
+	// Predeclared types (int, string, bool...)
+	if t.Name() != "" && t.PkgPath() == "" {
+		return t.Name()
+	}
+
+	if t.Name() != "" {
+		// Standard library types are referenced, not copied
+		if isStdlib(t.PkgPath()) {
+			m.imports[t.PkgPath()] = true
+			return path.Base(t.PkgPath()) + "." + t.Name()
+		}
+
+		// Interfaces (including unions) arrive as raw JSON for the caller to decode
+		if t.Kind() == reflect.Interface {
+			m.imports["encoding/json"] = true
+			return "json.RawMessage"
+		}
+
+		if _, ok := m.named[t.Name()]; !ok {
+			m.named[t.Name()] = t
+			m.order = append(m.order, t.Name())
+		}
+		return t.Name()
+	}
+
+	return m.underlying(t)
+}
+
+// underlying returns the type literal of t, ignoring its name.
+func (m *goTypeMirror) underlying(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + m.expr(t.Elem())
+	case reflect.Slice:
+		return "[]" + m.expr(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), m.expr(t.Elem()))
+	case reflect.Map:
+		return "map[" + m.expr(t.Key()) + "]" + m.expr(t.Elem())
+	case reflect.Struct:
+		return m.structLiteral(t)
+	case reflect.Interface:
+		return "interface{}"
+	default:
+		return t.Kind().String()
+	}
+}
+
+// structLiteral writes a struct type literal with exported fields and wire tags.
+func (m *goTypeMirror) structLiteral(t reflect.Type) string {
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Anonymous {
+			b.WriteString("\t" + m.expr(field.Type))
+		} else {
+			b.WriteString("\t" + field.Name + " " + m.expr(field.Type))
+		}
+		if tag := wireTags(field.Tag); tag != "" {
+			b.WriteString(" `" + tag + "`")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// declarations writes `type X ...` for every registered named type.
+// Declaring a type may discover more, so the loop runs until none are left.
+func (m *goTypeMirror) declarations() string {
+	var buf bytes.Buffer
+	written := map[string]bool{}
+	for i := 0; i < len(m.order); i++ {
+		name := m.order[i]
+		if written[name] {
+			continue
+		}
+		written[name] = true
+
+		fmt.Fprintf(&buf, "// %s mirrors the server type of the same name.\n", name)
+		fmt.Fprintf(&buf, "type %s %s\n\n", name, m.underlying(m.named[name]))
+	}
+	return buf.String()
+}
+
+// importList returns the standard library imports needed by the mirrored types.
+func (m *goTypeMirror) importList() []string {
+	list := make([]string, 0, len(m.imports))
+	for pkg := range m.imports {
+		list = append(list, pkg)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// wireTags keeps the tags relevant on the wire, in a stable order.
+func wireTags(tag reflect.StructTag) string {
+	parts := []string{}
+	for _, key := range keptTags {
+		if value, ok := tag.Lookup(key); ok {
+			parts = append(parts, fmt.Sprintf("%s:%q", key, value))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// isStdlib reports whether an import path belongs to the standard library.
+// Asks the Go toolchain first and falls back to "no dot in the first element".
+func isStdlib(pkgPath string) bool {
+	if pkgPath == "main" {
+		return false
+	}
+	if pkg, err := build.Import(pkgPath, "", build.FindOnly); err == nil {
+		return pkg.Goroot
+	}
+	first := strings.Split(pkgPath, "/")[0]
+	return !strings.Contains(first, ".")
+}
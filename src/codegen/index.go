@@ -0,0 +1,14 @@
+// Package codegen generates code and documents from SyntroGo apps.
+//
+// Generators:
+// - GenerateDocs: Registers Go doc comments as OpenAPI descriptions
+// - GenerateBinders: Reflection-free JSON decode/encode and validation for body types
+// - GenerateGoClient: Typed Go client package from registered routes
+// - GenerateTypeScript: TypeScript interfaces and fetch client from a spec
+// - GenerateServer: DTOs, handler interface and route registration from a spec (spec-first)
+//
+// Principles:
+// - Deterministic output: Generated files are stable and diff-friendly
+// - Standard library only: go/ast, go/parser and go/format
+// - Used by the syntrogo CLI and callable as a library
+package codegen
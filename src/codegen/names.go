@@ -0,0 +1,137 @@
+package codegen
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// initialisms are kept upper-case in generated identifiers (golint style).
+var initialisms = map[string]string{
+	"id":   "ID",
+	"ids":  "IDs",
+	"url":  "URL",
+	"uri":  "URI",
+	"api":  "API",
+	"http": "HTTP",
+	"json": "JSON",
+	"uuid": "UUID",
+	"sku":  "SKU",
+}
+
+// exportedName converts "create-user", "create_user" or "createUser" to "CreateUser".
+func exportedName(s string) string {
+	words := splitWords(s)
+	var b strings.Builder
+	for _, word := range words {
+		if upper, ok := initialisms[strings.ToLower(word)]; ok {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
+
+// splitWords splits identifiers on separators and lower-to-upper case changes.
+func splitWords(s string) []string {
+	words := []string{}
+	current := []rune{}
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+	return words
+}
+
+// operationName derives a method name for a route.
+// OperationID wins; otherwise "GET /users/:id/posts" becomes "GetUsersPostsByID".
+func operationName(route *domain.Route) string {
+	if route.Options.OperationID != "" {
+		return exportedName(route.Options.OperationID)
+	}
+
+	name := exportedName(strings.ToLower(route.Method))
+	by := ""
+	for _, segment := range strings.Split(route.Path, "/") {
+		switch {
+		case segment == "":
+			continue
+		case strings.HasPrefix(segment, ":"):
+			by += exportedName(segment[1:])
+		default:
+			name += exportedName(segment)
+		}
+	}
+	if by != "" {
+		name += "By" + by
+	}
+	return name
+}
+
+// uniqueNames assigns each route a distinct operation name, suffixing duplicates.
+func uniqueNames(routes []*domain.Route) []string {
+	seen := map[string]int{}
+	names := make([]string, len(routes))
+	for i, route := range routes {
+		name := operationName(route)
+		seen[name]++
+		if seen[name] > 1 {
+			name += strconv.Itoa(seen[name])
+		}
+		names[i] = name
+	}
+	return names
+}
+
+// pathParams returns the names of the :param segments of a path, in order.
+func pathParams(path string) []string {
+	params := []string{}
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, ":") {
+			params = append(params, segment[1:])
+		}
+	}
+	return params
+}
+
+// paramIdents names path parameters as arguments of a generated function, adding
+// a "Param" suffix to names taken by reserved identifiers or earlier parameters.
+// ident maps a parameter name to its identifier in the target language.
+func paramIdents(params []string, reserved map[string]bool, ident func(string) string) []string {
+	taken := make(map[string]bool, len(reserved)+len(params))
+	for name := range reserved {
+		taken[name] = true
+	}
+
+	idents := make([]string, len(params))
+	for i, param := range params {
+		name := ident(param)
+		for taken[name] {
+			name += "Param"
+		}
+		taken[name] = true
+		idents[i] = name
+	}
+	return idents
+}
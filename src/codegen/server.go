@@ -0,0 +1,777 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// serverMethods maps OpenAPI operation keys to the App method registering them.
+var serverMethods = map[string]string{
+	"get":     "GET",
+	"post":    "POST",
+	"put":     "PUT",
+	"patch":   "PATCH",
+	"delete":  "DELETE",
+	"head":    "HEAD",
+	"options": "OPTIONS",
+}
+
+// handlerArgs are the Handlers arguments besides path parameters; path
+// parameters with these names get a suffix.
+var handlerArgs = map[string]bool{"c": true, "body": true, "query": true, "headers": true, "cookies": true}
+
+// serverGenerator turns an OpenAPI document into Go types, a Handlers interface
+// and a Register function (spec-first mode).
+type serverGenerator struct {
+	spec       map[string]interface{}
+	components map[string]interface{}
+	decls      map[string]string // Type name -> declaration source
+	order      []string
+	structs    map[string]bool // Declared names whose underlying type is a struct
+	imports    map[string]bool
+}
+
+// serverOperation is one OpenAPI operation resolved to Go names and types.
+type serverOperation struct {
+	name       string
+	method     string // App method: GET, POST...
+	path       string // Framework path: /users/:id
+	op         map[string]interface{}
+	pathParams []string
+	paramTypes map[string]string    // Path parameter -> OpenAPI type, when not a string
+	pathStruct string               // Struct bound by BindPath when a path parameter is typed
+	pathFields map[string]pathField // Typed path parameters by name
+	body       string               // Request body Go type
+	query      string
+	headers    string
+	cookies    string
+	status     int
+	response   string // Response Go type, empty for no body
+}
+
+// pathField is a path parameter bound through the operation's path struct.
+type pathField struct {
+	field string // Field name in the path struct
+	typ   string // Go type passed to the handler
+}
+
+// GenerateServer writes a Go package from an OpenAPI 3 document: DTO structs with
+// json and validate tags, a Handlers interface with one method per operation,
+// Register to mount them on an App, and VerifySpec to compare the running app
+// with the source document.
+func GenerateServer(pkg string, spec map[string]interface{}, w io.Writer) error {
+	// Guard clauses: package name and paths are required
+	if pkg == "" {
+		return fmt.Errorf("package name is required")
+	}
+	paths, ok := spec["paths"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("spec has no paths")
+	}
+
+	g := &serverGenerator{
+		spec:    spec,
+		decls:   map[string]string{},
+		structs: map[string]bool{},
+		imports: map[string]bool{},
+	}
+	if components, ok := spec["components"].(map[string]interface{}); ok {
+		g.components, _ = components["schemas"].(map[string]interface{})
+	}
+	for _, name := range sortedKeys(g.components) {
+		g.declare(exportedName(name), asMap(g.components[name]), "the \""+name+"\" schema")
+	}
+
+	operations := []*serverOperation{}
+	seen := map[string]int{}
+	for _, path := range sortedKeys(paths) {
+		item := g.resolve(paths[path])
+		for _, method := range httpMethods {
+			op, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			appMethod, ok := serverMethods[method]
+			if !ok {
+				return fmt.Errorf("%s %s: App has no %s method", strings.ToUpper(method), path, strings.ToUpper(method))
+			}
+
+			name := exportedName(tsOperationName(method, path, op))
+			seen[name]++
+			if seen[name] > 1 {
+				name += strconv.Itoa(seen[name])
+			}
+			operations = append(operations, g.operation(name, appMethod, path, item, op))
+		}
+	}
+
+	source, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by syntrogo. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "// Package %s implements the API contract in SourceSpec.\n", pkg)
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	buf.WriteString(g.importBlock())
+
+	g.writeHandlers(&buf, operations)
+	g.writeRegister(&buf, operations)
+
+	buf.WriteString("// VerifySpec compares the routes registered on app with SourceSpec.\n")
+	buf.WriteString("// Call it after Register (and any hand-written routes), before Listen.\n")
+	buf.WriteString("func VerifySpec(app *core.App) error {\n\treturn app.VerifySpec([]byte(SourceSpec))\n}\n\n")
+	buf.WriteString("// SourceSpec is the OpenAPI document this package was generated from.\n")
+	fmt.Fprintf(&buf, "const SourceSpec = %s\n\n", goStringLiteral(string(source)))
+
+	for _, name := range g.order {
+		buf.WriteString(g.decls[name])
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("format generated server: %w", err)
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// operation resolves names, parameters, body and response of one operation.
+func (g *serverGenerator) operation(name, method, path string, item, op map[string]interface{}) *serverOperation {
+	o := &serverOperation{
+		name:       name,
+		method:     method,
+		path:       path,
+		op:         op,
+		paramTypes: map[string]string{},
+		status:     200,
+	}
+
+	// Parameters: path-level first, overridden by operation-level
+	params := map[string]map[string]interface{}{}
+	keys := []string{}
+	for _, list := range [][]interface{}{asList(item["parameters"]), asList(op["parameters"])} {
+		for _, raw := range list {
+			param := g.resolve(raw)
+			key := fmt.Sprint(param["in"]) + ":" + fmt.Sprint(param["name"])
+			if _, ok := params[key]; !ok {
+				keys = append(keys, key)
+			}
+			params[key] = param
+		}
+	}
+
+	locations := map[string][]map[string]interface{}{}
+	for _, key := range keys {
+		param := params[key]
+		in, _ := param["in"].(string)
+		if in == "path" {
+			paramName, _ := param["name"].(string)
+			o.path = strings.ReplaceAll(o.path, "{"+paramName+"}", ":"+paramName)
+			o.pathParams = append(o.pathParams, paramName)
+			if typ := schemaType(asMap(param["schema"])); typ != "" && typ != "string" {
+				o.paramTypes[paramName] = typ
+			}
+		}
+		locations[in] = append(locations[in], param)
+	}
+
+	// Typed path parameters are bound (400 when malformed) and passed as their Go types
+	if len(o.paramTypes) > 0 {
+		o.pathStruct = g.paramStruct(name, "Path", "path", locations["path"])
+		schema := asMap(g.paramSchema(locations["path"]))
+		fields := structFields(schema)
+		o.pathFields = map[string]pathField{}
+		for _, param := range o.pathParams {
+			fieldSchema := asMap(asMap(schema["properties"])[param])
+			o.pathFields[param] = pathField{field: fields[param], typ: g.fieldType(fieldSchema, o.pathStruct+fields[param], true)}
+		}
+	}
+	o.query = g.paramStruct(name, "Query", "query", locations["query"])
+	o.headers = g.paramStruct(name, "Headers", "header", locations["header"])
+	o.cookies = g.paramStruct(name, "Cookies", "cookie", locations["cookie"])
+
+	if body := g.resolve(op["requestBody"]); len(body) > 0 {
+		o.body = g.goType(jsonSchemaOf(body), name+"Request")
+	}
+
+	// Success response: the lowest 2xx status
+	responses := g.resolve(op["responses"])
+	for _, status := range sortedKeys(responses) {
+		code, err := strconv.Atoi(status)
+		if err != nil || code < 200 || code > 299 {
+			continue
+		}
+		o.status = code
+		if schema := jsonSchemaOf(g.resolve(responses[status])); schema != nil {
+			o.response = g.goType(schema, name+"Response")
+		}
+		break
+	}
+	return o
+}
+
+// paramStruct declares the struct bound by BindPath/BindQuery/BindHeader/BindCookie.
+func (g *serverGenerator) paramStruct(operation, suffix, tag string, params []map[string]interface{}) string {
+	// Guard clause: no parameters in this location
+	if len(params) == 0 {
+		return ""
+	}
+
+	name := operation + suffix
+	g.declareStruct(name, g.paramSchema(params), tag, "the "+tag+" parameters of "+operation)
+	return name
+}
+
+// paramSchema combines parameters into one object schema.
+func (g *serverGenerator) paramSchema(params []map[string]interface{}) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []interface{}{}
+	for _, param := range params {
+		paramName, _ := param["name"].(string)
+		schema := map[string]interface{}{}
+		for key, value := range asMap(param["schema"]) {
+			schema[key] = value
+		}
+		if description, ok := param["description"].(string); ok {
+			schema["description"] = description
+		}
+		properties[paramName] = schema
+		if isRequired, _ := param["required"].(bool); isRequired {
+			required = append(required, paramName)
+		}
+	}
+	return map[string]interface{}{"properties": properties, "required": required}
+}
+
+// declare registers a named type for a schema.
+func (g *serverGenerator) declare(name string, schema map[string]interface{}, origin string) {
+	if _, ok := g.decls[name]; ok {
+		return
+	}
+
+	schema = g.mergeAllOf(schema)
+	if isStructSchema(schema) {
+		g.declareStruct(name, schema, "json", origin)
+		return
+	}
+
+	// Reserve the name first: the schema may refer to itself
+	g.decls[name] = ""
+	g.order = append(g.order, name)
+
+	var b strings.Builder
+	writeTypeDoc(&b, name, origin, schema)
+	typ := g.goType(schema, name+"Item")
+	g.structs[name] = g.structs[typ]
+	if typ == "json.RawMessage" {
+		// Alias keeps RawMessage's JSON methods
+		fmt.Fprintf(&b, "type %s = %s\n\n", name, typ)
+	} else {
+		fmt.Fprintf(&b, "type %s %s\n\n", name, typ)
+	}
+	g.decls[name] = b.String()
+}
+
+// declareStruct writes a struct type whose fields carry `tag` names.
+func (g *serverGenerator) declareStruct(name string, schema map[string]interface{}, tag, origin string) {
+	if _, ok := g.decls[name]; ok {
+		return
+	}
+	g.decls[name] = ""
+	g.order = append(g.order, name)
+	g.structs[name] = true
+
+	required := map[string]bool{}
+	for _, field := range asList(schema["required"]) {
+		if s, ok := field.(string); ok {
+			required[s] = true
+		}
+	}
+
+	var b strings.Builder
+	writeTypeDoc(&b, name, origin, schema)
+	fmt.Fprintf(&b, "type %s struct {\n", name)
+	fields := structFields(schema)
+	for _, property := range sortedKeys(asMap(schema["properties"])) {
+		fieldSchema := asMap(asMap(schema["properties"])[property])
+		field := fields[property]
+
+		typ := g.fieldType(fieldSchema, name+field, required[property])
+		fmt.Fprintf(&b, "\t%s %s %s\n", field, typ, structTag(tag, property, typ, fieldSchema, required[property]))
+	}
+	b.WriteString("}\n\n")
+	g.decls[name] = b.String()
+}
+
+// structFields returns the Go field name of each property of an object schema,
+// numbering names that collide ("id" and "ID").
+func structFields(schema map[string]interface{}) map[string]string {
+	fields := map[string]string{}
+	seen := map[string]int{}
+	for _, property := range sortedKeys(asMap(schema["properties"])) {
+		field := fieldName(property)
+		seen[field]++
+		if seen[field] > 1 {
+			field += strconv.Itoa(seen[field])
+		}
+		fields[property] = field
+	}
+	return fields
+}
+
+// fieldType returns a field's Go type. Nullable values and optional objects are pointers.
+func (g *serverGenerator) fieldType(schema map[string]interface{}, hint string, required bool) string {
+	typ := g.goType(schema, hint)
+	if strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") || typ == "interface{}" || typ == "json.RawMessage" {
+		return typ
+	}
+	if isNullable(schema) || (!required && g.structs[typ]) {
+		return "*" + typ
+	}
+	return typ
+}
+
+// goType returns the Go type for a schema, declaring named types as needed.
+func (g *serverGenerator) goType(schema map[string]interface{}, hint string) string {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		g.declare(exportedName(name), asMap(g.components[name]), "the \""+name+"\" schema")
+		return exportedName(name)
+	}
+	if variants := asList(schema["oneOf"]); len(variants) > 0 || len(asList(schema["anyOf"])) > 0 {
+		// Unions arrive as raw JSON; register Go variants with api.OneOf to bind them
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	}
+	if all := asList(schema["allOf"]); len(all) == 1 {
+		return g.goType(asMap(all[0]), hint)
+	}
+	schema = g.mergeAllOf(schema)
+
+	switch schemaType(schema) {
+	case "string":
+		switch schema["format"] {
+		case "date-time":
+			g.imports["time"] = true
+			return "time.Time"
+		case "byte", "binary":
+			return "[]byte"
+		}
+		return "string"
+	case "integer":
+		switch schema["format"] {
+		case "int32":
+			return "int32"
+		case "int64":
+			return "int64"
+		}
+		return "int"
+	case "number":
+		if schema["format"] == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + g.goType(asMap(schema["items"]), hint+"Item")
+	}
+
+	if isStructSchema(schema) {
+		g.declareStruct(hint, schema, "json", "an inline schema")
+		return hint
+	}
+	if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+		return "map[string]" + g.goType(additional, hint+"Value")
+	}
+	if schemaType(schema) == "object" {
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+// mergeAllOf combines the properties of an allOf composition into one object schema.
+// Single-member compositions are left alone so they keep the member's name.
+func (g *serverGenerator) mergeAllOf(schema map[string]interface{}) map[string]interface{} {
+	members := asList(schema["allOf"])
+	if len(members) < 2 {
+		return schema
+	}
+
+	properties := map[string]interface{}{}
+	required := []interface{}{}
+	for _, member := range members {
+		part := asMap(member)
+		if ref, ok := part["$ref"].(string); ok {
+			part = asMap(g.components[strings.TrimPrefix(ref, "#/components/schemas/")])
+		}
+		part = g.mergeAllOf(part)
+		for name, property := range asMap(part["properties"]) {
+			properties[name] = property
+		}
+		required = append(required, asList(part["required"])...)
+	}
+
+	merged := map[string]interface{}{"type": "object", "properties": properties, "required": required}
+	if description, ok := schema["description"]; ok {
+		merged["description"] = description
+	}
+	return merged
+}
+
+// resolve follows a local "#/..." $ref (parameters, request bodies, responses).
+func (g *serverGenerator) resolve(v interface{}) map[string]interface{} {
+	node := asMap(v)
+	ref, ok := node["$ref"].(string)
+	if !ok || !strings.HasPrefix(ref, "#/") {
+		return node
+	}
+
+	var current interface{} = g.spec
+	for _, part := range strings.Split(ref[2:], "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		current = asMap(current)[part]
+	}
+	return asMap(current)
+}
+
+// writeHandlers writes the Handlers interface.
+func (g *serverGenerator) writeHandlers(buf *bytes.Buffer, operations []*serverOperation) {
+	buf.WriteString("// Handlers implements the operations of the API.\n")
+	buf.WriteString("// Returned errors are written like any handler error (domain.NewHTTPException for status codes).\n")
+	buf.WriteString("type Handlers interface {\n")
+	for i, o := range operations {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(buf, "\t// %s handles %s %s.\n", o.name, o.method, o.path)
+		if summary, ok := o.op["summary"].(string); ok && summary != "" {
+			fmt.Fprintf(buf, "\t// %s\n", strings.ReplaceAll(summary, "\n", " "))
+		}
+		if deprecated, _ := o.op["deprecated"].(bool); deprecated {
+			buf.WriteString("\t//\n\t// Deprecated: the API marks this operation as deprecated.\n")
+		}
+
+		args := []string{"c *api.Context"}
+		idents := paramIdents(o.pathParams, handlerArgs, goIdent)
+		for i, param := range o.pathParams {
+			typ := "string"
+			if field, ok := o.pathFields[param]; ok {
+				typ = field.typ
+			}
+			args = append(args, idents[i]+" "+typ)
+		}
+		for _, arg := range [][2]string{{"body", o.body}, {"query", o.query}, {"headers", o.headers}, {"cookies", o.cookies}} {
+			if arg[1] != "" {
+				args = append(args, arg[0]+" "+arg[1])
+			}
+		}
+
+		if o.response == "" {
+			fmt.Fprintf(buf, "\t%s(%s) error\n", o.name, strings.Join(args, ", "))
+			continue
+		}
+		fmt.Fprintf(buf, "\t%s(%s) (%s, error)\n", o.name, strings.Join(args, ", "), g.resultType(o.response))
+	}
+	buf.WriteString("}\n\n")
+}
+
+// writeRegister writes Register, which binds inputs and calls the handlers.
+func (g *serverGenerator) writeRegister(buf *bytes.Buffer, operations []*serverOperation) {
+	buf.WriteString("// Register mounts the handlers on app with the request, response and parameter\n")
+	buf.WriteString("// types of the source spec, so the generated OpenAPI document matches it.\n")
+	buf.WriteString("func Register(app *core.App, h Handlers) {\n")
+	for _, o := range operations {
+		fmt.Fprintf(buf, "\tapp.%s(%q, func(c *api.Context) error {\n", o.method, o.path)
+
+		callArgs := []string{"c"}
+		if o.pathStruct != "" {
+			fmt.Fprintf(buf, "\t\tvar pathParams %s\n", o.pathStruct)
+			buf.WriteString("\t\tif err := c.BindPath(&pathParams); err != nil {\n\t\t\treturn err\n\t\t}\n")
+		}
+		for _, param := range o.pathParams {
+			if field, ok := o.pathFields[param]; ok {
+				callArgs = append(callArgs, "pathParams."+field.field)
+				continue
+			}
+			callArgs = append(callArgs, fmt.Sprintf("c.Param(%q)", param))
+		}
+		binds := [][3]string{
+			{"body", o.body, "BindJSON"},
+			{"query", o.query, "BindQuery"},
+			{"headers", o.headers, "BindHeader"},
+			{"cookies", o.cookies, "BindCookie"},
+		}
+		for _, bind := range binds {
+			if bind[1] == "" {
+				continue
+			}
+			fmt.Fprintf(buf, "\t\tvar %s %s\n", bind[0], bind[1])
+			fmt.Fprintf(buf, "\t\tif err := c.%s(&%s); err != nil {\n\t\t\treturn err\n\t\t}\n", bind[2], bind[0])
+			callArgs = append(callArgs, bind[0])
+		}
+
+		call := fmt.Sprintf("h.%s(%s)", o.name, strings.Join(callArgs, ", "))
+		if o.response == "" {
+			fmt.Fprintf(buf, "\t\tif err := %s; err != nil {\n\t\t\treturn err\n\t\t}\n", call)
+			fmt.Fprintf(buf, "\t\treturn c.JSON(%d, nil)\n", o.status)
+		} else {
+			fmt.Fprintf(buf, "\t\tout, err := %s\n", call)
+			buf.WriteString("\t\tif err != nil {\n\t\t\treturn err\n\t\t}\n")
+			fmt.Fprintf(buf, "\t\treturn c.JSON(%d, out)\n", o.status)
+		}
+		buf.WriteString("\t},\n")
+
+		for _, option := range g.routeOptions(o) {
+			fmt.Fprintf(buf, "\t\t%s,\n", option)
+		}
+		buf.WriteString("\t)\n")
+	}
+	buf.WriteString("}\n\n")
+}
+
+// routeOptions returns the api.* option expressions describing an operation.
+func (g *serverGenerator) routeOptions(o *serverOperation) []string {
+	options := []string{}
+	if id, ok := o.op["operationId"].(string); ok && id != "" {
+		options = append(options, fmt.Sprintf("api.OperationID(%q)", id))
+	}
+	if summary, ok := o.op["summary"].(string); ok && summary != "" {
+		options = append(options, fmt.Sprintf("api.Summary(%q)", summary))
+	}
+	if description, ok := o.op["description"].(string); ok && description != "" {
+		options = append(options, fmt.Sprintf("api.Description(%q)", description))
+	}
+	if tags := asList(o.op["tags"]); len(tags) > 0 {
+		quoted := make([]string, len(tags))
+		for i, tag := range tags {
+			quoted[i] = strconv.Quote(fmt.Sprint(tag))
+		}
+		options = append(options, "api.Tags("+strings.Join(quoted, ", ")+")")
+	}
+	if deprecated, _ := o.op["deprecated"].(bool); deprecated {
+		options = append(options, "api.Deprecated()")
+	}
+	if len(o.paramTypes) > 0 {
+		entries := []string{}
+		for _, param := range o.pathParams {
+			if typ, ok := o.paramTypes[param]; ok {
+				entries = append(entries, fmt.Sprintf("%q: %q", param, typ))
+			}
+		}
+		options = append(options, "api.Params(map[string]string{"+strings.Join(entries, ", ")+"})")
+	}
+	if o.body != "" {
+		options = append(options, "api.Body("+g.zeroValue(o.body)+")")
+	}
+	if o.query != "" {
+		options = append(options, "api.Query("+o.query+"{})")
+	}
+	if o.headers != "" {
+		options = append(options, "api.Headers("+o.headers+"{})")
+	}
+	if o.cookies != "" {
+		options = append(options, "api.Cookies("+o.cookies+"{})")
+	}
+	switch {
+	case o.response != "":
+		options = append(options, fmt.Sprintf("api.Response(%d, %s)", o.status, g.zeroValue(o.response)))
+	case o.status != 200:
+		options = append(options, fmt.Sprintf("api.Response(%d, nil)", o.status))
+	}
+	return options
+}
+
+// resultType returns the handler result: structs by pointer, everything else by value.
+func (g *serverGenerator) resultType(typ string) string {
+	if g.isDeclaredStruct(typ) || typ == "time.Time" {
+		return "*" + typ
+	}
+	return typ
+}
+
+// zeroValue returns an expression for the zero value of a generated type.
+func (g *serverGenerator) zeroValue(typ string) string {
+	if g.isDeclaredStruct(typ) || typ == "time.Time" || strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") {
+		return typ + "{}"
+	}
+	return "*new(" + typ + ")"
+}
+
+// isDeclaredStruct reports whether typ names a generated struct type (or a type defined from one).
+func (g *serverGenerator) isDeclaredStruct(typ string) bool {
+	return g.structs[typ]
+}
+
+// importBlock lists the imports of the generated file.
+func (g *serverGenerator) importBlock() string {
+	std := []string{}
+	for imp := range g.imports {
+		std = append(std, strconv.Quote(imp))
+	}
+	sort.Strings(std)
+
+	lines := append(std, "")
+	lines = append(lines,
+		`api "github.com/syntropysoft/syntrogo"`,
+		`"github.com/syntropysoft/syntrogo/src/core"`,
+	)
+	if len(std) == 0 {
+		lines = lines[1:]
+	}
+	return "import (\n\t" + strings.Join(lines, "\n\t") + "\n)\n\n"
+}
+
+// structTag builds the field tag: wire name, validation rules, doc and example.
+func structTag(tag, property, typ string, schema map[string]interface{}, required bool) string {
+	parts := []string{}
+	name := property
+	if tag == "json" && !required {
+		name += ",omitempty"
+	}
+	parts = append(parts, fmt.Sprintf("%s:%q", tag, name))
+
+	if rules := validateRules(typ, schema, required); rules != "" {
+		parts = append(parts, fmt.Sprintf("validate:%q", rules))
+	}
+	if description, ok := schema["description"].(string); ok && description != "" {
+		parts = append(parts, fmt.Sprintf("doc:%q", strings.TrimSpace(description)))
+	}
+	if example, ok := schema["example"]; ok {
+		switch example.(type) {
+		case string, float64, bool:
+			parts = append(parts, fmt.Sprintf("example:%q", fmt.Sprint(example)))
+		}
+	}
+	return goStringLiteral(strings.Join(parts, " "))
+}
+
+// validateRules maps JSON Schema constraints to go-playground/validator rules.
+// Required numbers and booleans are not marked "required": the validator would
+// reject their zero values (0, false), which are legitimate inputs.
+func validateRules(typ string, schema map[string]interface{}, required bool) string {
+	rules := []string{}
+	base := strings.TrimPrefix(typ, "*")
+	isNumber := strings.HasPrefix(base, "int") || strings.HasPrefix(base, "float")
+	switch {
+	case required && (base == "bool" || isNumber) && !strings.HasPrefix(typ, "*"):
+	case required:
+		rules = append(rules, "required")
+	default:
+		rules = append(rules, "omitempty")
+	}
+
+	for _, bound := range [][2]string{{"minLength", "min"}, {"maxLength", "max"}, {"minItems", "min"}, {"maxItems", "max"}, {"minProperties", "min"}, {"maxProperties", "max"}} {
+		if value, ok := schema[bound[0]].(float64); ok {
+			rules = append(rules, bound[1]+"="+formatNumber(value))
+		}
+	}
+
+	// Bounds: 3.0 uses boolean exclusive flags, 3.1 uses numbers
+	if value, ok := schema["minimum"].(float64); ok {
+		if exclusive, _ := schema["exclusiveMinimum"].(bool); exclusive {
+			rules = append(rules, "gt="+formatNumber(value))
+		} else {
+			rules = append(rules, "gte="+formatNumber(value))
+		}
+	}
+	if value, ok := schema["exclusiveMinimum"].(float64); ok {
+		rules = append(rules, "gt="+formatNumber(value))
+	}
+	if value, ok := schema["maximum"].(float64); ok {
+		if exclusive, _ := schema["exclusiveMaximum"].(bool); exclusive {
+			rules = append(rules, "lt="+formatNumber(value))
+		} else {
+			rules = append(rules, "lte="+formatNumber(value))
+		}
+	}
+	if value, ok := schema["exclusiveMaximum"].(float64); ok {
+		rules = append(rules, "lt="+formatNumber(value))
+	}
+
+	if enum := enumRule(asList(schema["enum"])); enum != "" {
+		rules = append(rules, enum)
+	}
+	switch schema["format"] {
+	case "email":
+		rules = append(rules, "email")
+	case "uri", "url":
+		rules = append(rules, "url")
+	case "uuid":
+		rules = append(rules, "uuid")
+	}
+
+	// Guard clause: "omitempty" alone adds nothing
+	if len(rules) == 1 && rules[0] == "omitempty" {
+		return ""
+	}
+	return strings.Join(rules, ",")
+}
+
+// enumRule builds "oneof=a b c"; values with spaces or commas cannot be expressed.
+func enumRule(values []interface{}) string {
+	if len(values) == 0 {
+		return ""
+	}
+	words := []string{}
+	for _, value := range values {
+		switch v := value.(type) {
+		case string:
+			if v == "" || strings.ContainsAny(v, " ,|") {
+				return ""
+			}
+			words = append(words, v)
+		case float64:
+			words = append(words, formatNumber(v))
+		default:
+			return ""
+		}
+	}
+	return "oneof=" + strings.Join(words, " ")
+}
+
+// writeTypeDoc writes the doc comment of a generated type.
+func writeTypeDoc(b *strings.Builder, name, origin string, schema map[string]interface{}) {
+	fmt.Fprintf(b, "// %s is generated from %s.\n", name, origin)
+	if description, ok := schema["description"].(string); ok && description != "" {
+		for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+			fmt.Fprintf(b, "// %s\n", line)
+		}
+	}
+}
+
+// isStructSchema reports whether a schema has properties and becomes a Go struct.
+func isStructSchema(schema map[string]interface{}) bool {
+	_, ok := schema["properties"].(map[string]interface{})
+	return ok && (schemaType(schema) == "" || schemaType(schema) == "object")
+}
+
+// fieldName converts a property name to an exported Go field name.
+func fieldName(property string) string {
+	name := exportedName(property)
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "Field" + name
+	}
+	return name
+}
+
+// formatNumber writes a JSON number without a trailing ".0".
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// goStringLiteral prefers a raw string literal and falls back to a quoted one.
+func goStringLiteral(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
@@ -0,0 +1,547 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// httpMethods are the OpenAPI operation keys, in output order.
+var httpMethods = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+
+// tsIdentifier matches property names that need no quotes in TypeScript.
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsInvalidChars matches characters not allowed in TypeScript type names.
+var tsInvalidChars = regexp.MustCompile(`[^A-Za-z0-9_$]+`)
+
+// tsReserved are identifiers path parameters must not take in generated methods:
+// the fixed arguments, the globals the path expression calls and the words
+// JavaScript reserves in strict mode.
+var tsReserved = map[string]bool{
+	"body": true, "query": true, "headers": true, "init": true, "encodeURIComponent": true, "String": true,
+	"arguments": true, "await": true, "break": true, "case": true, "catch": true, "class": true, "const": true,
+	"continue": true, "debugger": true, "default": true, "delete": true, "do": true, "else": true, "enum": true,
+	"eval": true, "export": true, "extends": true, "false": true, "finally": true, "for": true, "function": true,
+	"if": true, "implements": true, "import": true, "in": true, "instanceof": true, "interface": true, "let": true,
+	"new": true, "null": true, "package": true, "private": true, "protected": true, "public": true, "return": true,
+	"static": true, "super": true, "switch": true, "this": true, "throw": true, "true": true, "try": true,
+	"typeof": true, "var": true, "void": true, "while": true, "with": true, "yield": true,
+}
+
+// tsRuntime is the static part of every generated TypeScript client.
+const tsRuntime = `/** Error thrown for non-2xx responses; message comes from the {"error": "..."} body. */
+export class ApiError extends Error {
+  readonly status: number;
+
+  constructor(status: number, message: string) {
+    super(message);
+    this.name = "ApiError";
+    this.status = status;
+  }
+}
+
+export interface ClientOptions {
+  /** Base URL of the API, e.g. "https://api.example.com". */
+  baseUrl?: string;
+  /** fetch implementation (defaults to the global fetch). */
+  fetch?: typeof fetch;
+  /** Headers sent with every request, e.g. Authorization. */
+  headers?: Record<string, string>;
+}
+
+type Params = Record<string, unknown> | undefined;
+
+function toSearch(params: Params): string {
+  if (!params) return "";
+  const search = new URLSearchParams();
+  for (const [key, value] of Object.entries(params)) {
+    if (value === undefined || value === null) continue;
+    for (const item of Array.isArray(value) ? value : [value]) search.append(key, String(item));
+  }
+  const text = search.toString();
+  return text ? "?" + text : "";
+}
+
+function toHeaders(params: Params): Record<string, string> {
+  const headers: Record<string, string> = {};
+  for (const [key, value] of Object.entries(params ?? {})) {
+    if (value !== undefined && value !== null) headers[key] = String(value);
+  }
+  return headers;
+}
+
+export class ApiClient {
+  private readonly baseUrl: string;
+  private readonly fetchImpl: typeof fetch;
+  private readonly headers: Record<string, string>;
+
+  constructor(options: ClientOptions = {}) {
+    this.baseUrl = (options.baseUrl ?? "").replace(/\/+$/, "");
+    this.fetchImpl = options.fetch ?? fetch.bind(globalThis);
+    this.headers = options.headers ?? {};
+  }
+
+  private async request<T>(method: string, path: string, body: unknown, query: Params, headers: Params, init?: RequestInit): Promise<T> {
+    const response = await this.fetchImpl(this.baseUrl + path + toSearch(query), {
+      ...init,
+      method,
+      headers: {
+        Accept: "application/json",
+        ...(body !== undefined ? { "Content-Type": "application/json" } : {}),
+        ...this.headers,
+        ...toHeaders(headers),
+        ...(init?.headers as Record<string, string> | undefined),
+      },
+      body: body !== undefined ? JSON.stringify(body) : undefined,
+    });
+
+    if (!response.ok) {
+      const text = await response.text();
+      let message = text;
+      try {
+        message = JSON.parse(text).error ?? text;
+      } catch {
+        // Not JSON, keep the raw text
+      }
+      throw new ApiError(response.status, message);
+    }
+
+    if (response.status === 204) return undefined as T;
+    const text = await response.text();
+    return (text ? JSON.parse(text) : undefined) as T;
+  }
+`
+
+// tsGenerator converts OpenAPI schemas to TypeScript declarations.
+type tsGenerator struct {
+	components map[string]interface{}
+	decls      map[string]string // Declaration name -> source
+	order      []string
+}
+
+// GenerateTypeScript writes TypeScript interfaces for every schema in an OpenAPI
+// document plus a fetch-based ApiClient with one method per operation.
+// The spec is the output of OpenAPIGenerator (or a file written by App.WriteSpec).
+func GenerateTypeScript(spec map[string]interface{}, w io.Writer) error {
+	paths, ok := spec["paths"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("spec has no paths")
+	}
+
+	g := &tsGenerator{decls: map[string]string{}}
+	if components, ok := spec["components"].(map[string]interface{}); ok {
+		g.components, _ = components["schemas"].(map[string]interface{})
+	}
+
+	// Component schemas first, so $ref targets keep their names
+	for _, name := range sortedKeys(g.components) {
+		g.declare(name, asMap(g.components[name]))
+	}
+
+	var methods bytes.Buffer
+	seen := map[string]int{}
+	for _, path := range sortedKeys(paths) {
+		item := asMap(paths[path])
+		for _, method := range httpMethods {
+			op, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			// WebSocket handshakes cannot be made with fetch
+			if _, upgrade := asMap(op["responses"])["101"]; upgrade {
+				continue
+			}
+
+			name := tsOperationName(method, path, op)
+			seen[name]++
+			if seen[name] > 1 {
+				name += strconv.Itoa(seen[name])
+			}
+			g.writeMethod(&methods, name, method, path, op)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by syntrogo. DO NOT EDIT.\n\n")
+	for _, name := range g.order {
+		buf.WriteString(g.decls[name])
+		buf.WriteString("\n")
+	}
+	buf.WriteString(tsRuntime)
+	buf.Write(methods.Bytes())
+	buf.WriteString("}\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeMethod writes one ApiClient method.
+// Signature: path params..., body, query, headers, init.
+func (g *tsGenerator) writeMethod(buf *bytes.Buffer, name, method, path string, op map[string]interface{}) {
+	args := []string{}
+	pathExpr := "`" + path + "`"
+	query := map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	headers := map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+
+	pathNames := []string{}
+	for _, raw := range asList(op["parameters"]) {
+		if param := asMap(raw); param["in"] == "path" {
+			name, _ := param["name"].(string)
+			pathNames = append(pathNames, name)
+		}
+	}
+	idents := paramIdents(pathNames, tsReserved, goIdent)
+
+	for _, raw := range asList(op["parameters"]) {
+		param := asMap(raw)
+		paramName, _ := param["name"].(string)
+		switch param["in"] {
+		case "path":
+			ident := idents[0]
+			idents = idents[1:]
+			args = append(args, ident+": string | number")
+			pathExpr = strings.Replace(pathExpr, "{"+paramName+"}", "${encodeURIComponent(String("+ident+"))}", 1)
+		case "query":
+			addTSProperty(query, paramName, param)
+		case "header":
+			addTSProperty(headers, paramName, param)
+		}
+	}
+
+	body := "undefined"
+	if schema := jsonSchemaOf(asMap(op["requestBody"])); schema != nil {
+		args = append(args, "body: "+g.typeOf(schema))
+		body = "body"
+	}
+
+	queryArg, headersArg := "undefined", "undefined"
+	if len(asMap(query["properties"])) > 0 {
+		args = append(args, "query"+g.optionalMark(query)+": "+g.objectType(query, "  "))
+		queryArg = "query"
+	}
+	if len(asMap(headers["properties"])) > 0 {
+		args = append(args, "headers"+g.optionalMark(headers)+": "+g.objectType(headers, "  "))
+		headersArg = "headers"
+	}
+	args = append(args, "init?: RequestInit")
+
+	result := "void"
+	responses := asMap(op["responses"])
+	for _, status := range sortedKeys(responses) {
+		if strings.HasPrefix(status, "2") {
+			if schema := jsonSchemaOf(asMap(responses[status])); schema != nil {
+				result = g.typeOf(schema)
+			}
+			break
+		}
+	}
+
+	buf.WriteString("\n")
+	g.writeDoc(buf, "  ", op)
+	fmt.Fprintf(buf, "  %s(%s): Promise<%s> {\n", name, strings.Join(args, ", "), result)
+	fmt.Fprintf(buf, "    return this.request<%s>(%q, %s, %s, %s, %s, init);\n  }\n",
+		result, strings.ToUpper(method), pathExpr, body, queryArg, headersArg)
+}
+
+// writeDoc writes a JSDoc block from summary, description and deprecated.
+func (g *tsGenerator) writeDoc(buf *bytes.Buffer, indent string, op map[string]interface{}) {
+	lines := []string{}
+	for _, key := range []string{"summary", "description"} {
+		if text, ok := op[key].(string); ok && text != "" {
+			lines = append(lines, strings.Split(text, "\n")...)
+		}
+	}
+	if deprecated, _ := op["deprecated"].(bool); deprecated {
+		lines = append(lines, "@deprecated")
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	buf.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		buf.WriteString(indent + " * " + strings.ReplaceAll(line, "*/", "* /") + "\n")
+	}
+	buf.WriteString(indent + " */\n")
+}
+
+// optionalMark returns "?" when no property of an object schema is required.
+func (g *tsGenerator) optionalMark(schema map[string]interface{}) string {
+	if len(asList(schema["required"])) == 0 {
+		return "?"
+	}
+	return ""
+}
+
+// declare registers a named interface or type alias for a schema.
+func (g *tsGenerator) declare(name string, schema map[string]interface{}) string {
+	name = tsTypeName(name)
+	if _, ok := g.decls[name]; ok {
+		return name
+	}
+
+	// Reserve the name first: recursive types reference themselves
+	g.decls[name] = ""
+	g.order = append(g.order, name)
+
+	var buf bytes.Buffer
+	if description, ok := schema["description"].(string); ok && description != "" {
+		g.writeDoc(&buf, "", map[string]interface{}{"description": description})
+	}
+	if isObjectSchema(schema) && schema["additionalProperties"] == nil {
+		fmt.Fprintf(&buf, "export interface %s %s\n", name, g.objectType(schema, ""))
+	} else {
+		fmt.Fprintf(&buf, "export type %s = %s;\n", name, g.inlineType(schema))
+	}
+	g.decls[name] = buf.String()
+	return name
+}
+
+// typeOf returns the TypeScript type for a schema, hoisting titled objects into declarations.
+func (g *tsGenerator) typeOf(schema map[string]interface{}) string {
+	if title, ok := schema["title"].(string); ok && title != "" && isObjectSchema(schema) {
+		return g.nullable(schema, g.declare(title, schema))
+	}
+	return g.inlineType(schema)
+}
+
+// inlineType converts a schema without hoisting it.
+func (g *tsGenerator) inlineType(schema map[string]interface{}) string {
+	if ref, ok := schema["$ref"].(string); ok {
+		return tsTypeName(ref[strings.LastIndex(ref, "/")+1:])
+	}
+
+	if variants := asList(schema["oneOf"]); len(variants) > 0 {
+		return g.union(variants)
+	}
+	if variants := asList(schema["anyOf"]); len(variants) > 0 {
+		return g.union(variants)
+	}
+
+	if enum := asList(schema["enum"]); len(enum) > 0 {
+		literals := []string{}
+		for _, value := range enum {
+			literals = append(literals, jsonLiteral(value))
+		}
+		return g.nullable(schema, strings.Join(literals, " | "))
+	}
+
+	typ := schemaType(schema)
+	var ts string
+	switch typ {
+	case "string":
+		ts = "string"
+	case "integer", "number":
+		ts = "number"
+	case "boolean":
+		ts = "boolean"
+	case "array":
+		ts = wrapUnion(g.typeOf(asMap(schema["items"]))) + "[]"
+	case "object":
+		if extra, ok := schema["additionalProperties"].(map[string]interface{}); ok && len(asMap(schema["properties"])) == 0 {
+			ts = "Record<string, " + g.typeOf(extra) + ">"
+		} else {
+			ts = g.objectType(schema, "")
+		}
+	default:
+		ts = "unknown"
+	}
+	return g.nullable(schema, ts)
+}
+
+// objectType writes an object literal type with optional markers for non-required properties.
+func (g *tsGenerator) objectType(schema map[string]interface{}, indent string) string {
+	properties := asMap(schema["properties"])
+	if len(properties) == 0 {
+		return "{}"
+	}
+
+	required := map[string]bool{}
+	for _, name := range asList(schema["required"]) {
+		if s, ok := name.(string); ok {
+			required[s] = true
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	for _, name := range sortedKeys(properties) {
+		prop := asMap(properties[name])
+		if description, ok := prop["description"].(string); ok && description != "" {
+			g.writeDoc(&buf, indent+"  ", map[string]interface{}{"description": description})
+		}
+
+		key := name
+		if !tsIdentifier.MatchString(name) {
+			key = strconv.Quote(name)
+		}
+		optional := "?"
+		if required[name] {
+			optional = ""
+		}
+		readonly := ""
+		if ro, _ := prop["readOnly"].(bool); ro {
+			readonly = "readonly "
+		}
+		ts := strings.ReplaceAll(g.typeOf(prop), "\n", "\n"+indent+"  ")
+		fmt.Fprintf(&buf, "%s  %s%s%s: %s;\n", indent, readonly, key, optional, ts)
+	}
+	buf.WriteString(indent + "}")
+	return buf.String()
+}
+
+// union joins variant types with "|".
+func (g *tsGenerator) union(variants []interface{}) string {
+	types := []string{}
+	for _, variant := range variants {
+		types = append(types, g.typeOf(asMap(variant)))
+	}
+	return strings.Join(types, " | ")
+}
+
+// nullable appends "| null" for OpenAPI 3.0 nullable and 3.1 type arrays.
+func (g *tsGenerator) nullable(schema map[string]interface{}, ts string) string {
+	if isNullable(schema) {
+		return wrapUnion(ts) + " | null"
+	}
+	return ts
+}
+
+// addTSProperty adds a parameter to an object schema used for query/header arguments.
+func addTSProperty(object map[string]interface{}, name string, param map[string]interface{}) {
+	asMap(object["properties"])[name] = asMap(param["schema"])
+	if required, _ := param["required"].(bool); required {
+		object["required"] = append(asList(object["required"]), name)
+	}
+}
+
+// jsonSchemaOf returns the application/json schema of a request body or response.
+func jsonSchemaOf(object map[string]interface{}) map[string]interface{} {
+	media, ok := asMap(object["content"])["application/json"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	schema, _ := media["schema"].(map[string]interface{})
+	return schema
+}
+
+// tsOperationName returns the camelCase method name for an operation.
+func tsOperationName(method, path string, op map[string]interface{}) string {
+	if id, ok := op["operationId"].(string); ok && id != "" {
+		return goIdent(id)
+	}
+
+	name := method
+	by := ""
+	for _, segment := range strings.Split(path, "/") {
+		switch {
+		case segment == "":
+			continue
+		case strings.HasPrefix(segment, "{"):
+			by += exportedName(strings.Trim(segment, "{}"))
+		default:
+			name += exportedName(segment)
+		}
+	}
+	if by != "" {
+		name += "By" + by
+	}
+	return name
+}
+
+// tsTypeName sanitizes a Go or component name for TypeScript ("Page[main.User]" -> "Page_main_User").
+func tsTypeName(name string) string {
+	cleaned := tsInvalidChars.ReplaceAllString(name, "_")
+	return strings.Trim(cleaned, "_")
+}
+
+// wrapUnion parenthesizes union types before "[]" or "| null".
+func wrapUnion(ts string) string {
+	if strings.Contains(ts, " | ") && !strings.HasPrefix(ts, "{") {
+		return "(" + ts + ")"
+	}
+	return ts
+}
+
+// schemaType returns the non-null type of a schema, for both 3.0 and 3.1 documents.
+func schemaType(schema map[string]interface{}) string {
+	switch typ := schema["type"].(type) {
+	case string:
+		return typ
+	case []interface{}:
+		for _, t := range typ {
+			if s, ok := t.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// isNullable reports whether a schema accepts null.
+func isNullable(schema map[string]interface{}) bool {
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		return true
+	}
+	for _, t := range asList(schema["type"]) {
+		if t == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// isObjectSchema reports whether a schema describes an object with properties.
+func isObjectSchema(schema map[string]interface{}) bool {
+	return schemaType(schema) == "object"
+}
+
+// jsonLiteral formats an enum value as a TypeScript literal.
+func jsonLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// asMap returns v as a JSON object, or an empty one.
+func asMap(v interface{}) map[string]interface{} {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m
+	}
+	return map[string]interface{}{}
+}
+
+// asList returns v as a JSON array; []string from generated specs is converted.
+func asList(v interface{}) []interface{} {
+	switch list := v.(type) {
+	case []interface{}:
+		return list
+	case []string:
+		converted := make([]interface{}, len(list))
+		for i, s := range list {
+			converted[i] = s
+		}
+		return converted
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of a JSON object in sorted order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
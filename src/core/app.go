@@ -1,7 +1,20 @@
 package core
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
 	"github.com/syntropysoft/syntrogo/src/application"
+	"github.com/syntropysoft/syntrogo/src/codegen"
 	"github.com/syntropysoft/syntrogo/src/domain"
 	"github.com/syntropysoft/syntrogo/src/infrastructure"
 )
@@ -10,6 +23,16 @@ import (
 type RouteRegistry = application.RouteRegistry
 type MiddlewareRegistry = application.MiddlewareRegistry
 
+// SpecFormat is the serialization format of the OpenAPI document.
+type SpecFormat string
+
+const (
+	// SpecJSON writes the spec as indented JSON.
+	SpecJSON SpecFormat = "json"
+	// SpecYAML writes the spec as YAML.
+	SpecYAML SpecFormat = "yaml"
+)
+
 // Protocol defines the communication protocol.
 type Protocol int
 
@@ -33,7 +56,13 @@ type App struct {
 	protocol           Protocol           // Protocol selector (REST by default, gRPC for v2.0+)
 	prefix             string            // Route prefix for groups
 	groupMiddlewares   []domain.Middleware // Middlewares for this group
+	groupSecurity      []domain.SecurityScheme // Schemes enforced by the group's secured middlewares
 	parent             *App              // Parent app for groups
+	dependencies       *application.DependencyContainer // Providers shared with groups
+	codecs             *application.CodecRegistry       // Body codecs shared with groups
+
+	mu      sync.Mutex                   // Guards adapter
+	adapter *infrastructure.HTTPAdapter // Running server, set by Listen
 }
 
 // New creates a new application instance.
@@ -47,6 +76,8 @@ func New() *App {
 		},
 		routeRegistry:     application.NewRouteRegistry(),
 		middlewareRegistry: application.NewMiddlewareRegistry(),
+		dependencies:      application.NewDependencyContainer(),
+		codecs:            application.NewCodecRegistry(infrastructure.DefaultCodecs()...),
 		swaggerEnabled:    false,
 		protocol:          ProtocolREST, // Default to REST
 	}
@@ -92,37 +123,83 @@ func (a *App) DELETE(path string, handler domain.HandlerFunc, opts ...domain.Rou
 	return a
 }
 
+// PATCH registers a PATCH route.
+func (a *App) PATCH(path string, handler domain.HandlerFunc, opts ...domain.RouteOptions) *App {
+	a.registerRoute("PATCH", path, handler, opts...)
+	return a
+}
+
+// HEAD registers a HEAD route.
+func (a *App) HEAD(path string, handler domain.HandlerFunc, opts ...domain.RouteOptions) *App {
+	a.registerRoute("HEAD", path, handler, opts...)
+	return a
+}
+
+// OPTIONS registers an OPTIONS route.
+func (a *App) OPTIONS(path string, handler domain.HandlerFunc, opts ...domain.RouteOptions) *App {
+	a.registerRoute("OPTIONS", path, handler, opts...)
+	return a
+}
+
+// WS registers a WebSocket route. The upgrade request goes through the same
+// router, group and route middlewares as a GET, so security middlewares reject
+// it before the connection switches protocols.
+// Usage: app.WS("/chat", func(ctx *api.Context, conn api.WSConn) error { ... }, api.WebSocket(api.WSOptions{...}))
+func (a *App) WS(path string, handler domain.WSHandler, opts ...domain.RouteOptions) *App {
+	options := domain.WSOptions{}
+	if merged := domain.MergeRouteOptions(opts...); merged.WebSocket != nil {
+		options = *merged.WebSocket
+	}
+
+	upgrade := func(c *domain.Context) error {
+		conn, err := c.Upgrade(options)
+		if err != nil {
+			return err
+		}
+		return handler(c, conn)
+	}
+	a.registerRoute("GET", path, upgrade, append(opts[:len(opts):len(opts)], domain.RouteOptions{WebSocket: &options})...)
+	return a
+}
+
+// Static serves files under prefix, typically an embed.FS holding a built front end.
+// Files get ETag and Last-Modified validators, Range support and their .br or .gz
+// variants when the client accepts them. Routes registered on the app win over
+// the files, so an API can live under the same prefix.
+// Usage: app.Static("/admin", adminFiles, api.StaticOptions{SPA: true})
+func (a *App) Static(prefix string, files fs.FS, options ...domain.StaticOptions) *App {
+	selected := domain.StaticOptions{}
+	if len(options) > 0 {
+		selected = options[0]
+	}
+
+	handler := infrastructure.StaticHandler(files, selected)
+	pattern := strings.TrimSuffix(prefix, "/") + "/*" + infrastructure.StaticParam
+	for _, method := range []string{"GET", "HEAD"} {
+		a.registerRoute(method, pattern, handler, domain.RouteOptions{Hidden: true})
+	}
+	return a
+}
+
 // registerRoute is the internal implementation that merges options.
 func (a *App) registerRoute(method, path string, handler domain.HandlerFunc, opts ...domain.RouteOptions) {
 	// Merge all options into one
-	var merged domain.RouteOptions
-	for _, opt := range opts {
-		if opt.Body != nil {
-			merged.Body = opt.Body
-		}
-		if opt.Response != nil {
-			merged.Response = opt.Response
-		}
-		if opt.Summary != "" {
-			merged.Summary = opt.Summary
-		}
-		if len(opt.Tags) > 0 {
-			merged.Tags = opt.Tags
-		}
-		if opt.Params != nil {
-			merged.Params = opt.Params
-		}
-		if len(opt.Middlewares) > 0 {
-			merged.Middlewares = opt.Middlewares
-		}
-	}
-	
+	merged := domain.MergeRouteOptions(opts...)
+
 	// Combine group middlewares with route middlewares
-	merged.Middlewares = append(a.groupMiddlewares, merged.Middlewares...)
+	routeMiddlewares := make([]domain.Middleware, 0, len(a.groupMiddlewares)+len(merged.Middlewares))
+	routeMiddlewares = append(routeMiddlewares, a.groupMiddlewares...)
+	merged.Middlewares = append(routeMiddlewares, merged.Middlewares...)
+	routeSecurity := make([]domain.SecurityScheme, 0, len(a.groupSecurity)+len(merged.Security))
+	routeSecurity = append(routeSecurity, a.groupSecurity...)
+	merged.Security = append(routeSecurity, merged.Security...)
 	
 	// Register in registry with full path (prefix + path)
 	fullPath := a.prefix + path
 	_ = a.routeRegistry.Register(method, fullPath, handler, merged)
+
+	// Parse the declared types now instead of on the first request
+	application.PrepareTypes(merged.Body, merged.Query, merged.Headers, merged.Cookies, merged.Form, merged.Response)
 }
 
 // Use adds a global middleware to the chain.
@@ -131,19 +208,130 @@ func (a *App) Use(middleware domain.Middleware) *App {
 	return a
 }
 
+// UseSecured adds a global security middleware; its schemes become the spec's
+// top-level security.
+// Usage: app.UseSecured(security.BearerToken("token"))
+func (a *App) UseSecured(middleware domain.SecuredMiddleware) *App {
+	a.middlewareRegistry.UseSecured(middleware)
+	return a
+}
+
 // Group creates a route group with a prefix and optional middlewares.
-// Usage: api := app.Group("/api", security.BearerToken("token"))
+// Nested groups inherit the parent's prefix, middlewares and security.
+// Usage: api := app.Group("/api", logger)
 func (a *App) Group(prefix string, middlewares ...domain.Middleware) *App {
+	// Copy to avoid sharing the parent's backing array between sibling groups
+	inherited := make([]domain.Middleware, 0, len(a.groupMiddlewares)+len(middlewares))
+	inherited = append(inherited, a.groupMiddlewares...)
+	inherited = append(inherited, middlewares...)
+	security := make([]domain.SecurityScheme, len(a.groupSecurity))
+	copy(security, a.groupSecurity)
+
 	return &App{
 		config:           a.config,
 		routeRegistry:    a.routeRegistry,
 		middlewareRegistry: a.middlewareRegistry,
 		swaggerEnabled:   a.swaggerEnabled,
 		protocol:         a.protocol,
-		prefix:           prefix,
-		groupMiddlewares: middlewares,
+		prefix:           a.prefix + prefix,
+		groupMiddlewares: inherited,
+		groupSecurity:    security,
 		parent:           a,
+		dependencies:     a.dependencies,
+		codecs:           a.codecs,
+	}
+}
+
+// Secure returns a group with the same prefix whose routes run a security
+// middleware and document its schemes.
+// Usage: api := app.Group("/api").Secure(security.BearerToken("token"))
+func (a *App) Secure(middleware domain.SecuredMiddleware) *App {
+	group := a.Group("", middleware.Middleware)
+	group.groupSecurity = append(group.groupSecurity, middleware.Schemes...)
+	return group
+}
+
+// Provide registers a dependency provider (request scope by default).
+// The provider's parameters are resolved the same way, recursively.
+// Usage: app.Provide(func(ctx *api.Context, db *sql.DB) (*sql.Tx, func(), error) { ... })
+func (a *App) Provide(provider interface{}, scope ...domain.Scope) *App {
+	selected := domain.ScopeRequest
+	if len(scope) > 0 {
+		selected = scope[0]
 	}
+	a.dependencies.Provide(provider, selected)
+	return a
+}
+
+// Inject builds a handler whose arguments are resolved from the providers.
+// Usage: app.GET("/users", app.Inject(func(ctx *api.Context, tx *sql.Tx) error { ... }))
+func (a *App) Inject(handler interface{}) domain.HandlerFunc {
+	return a.dependencies.Inject(handler)
+}
+
+// CheckDependencies reports missing providers and dependency cycles. Listen calls it.
+func (a *App) CheckDependencies() error {
+	return a.dependencies.Validate()
+}
+
+// Close runs the cleanups of singleton dependencies.
+func (a *App) Close() error {
+	a.dependencies.Close()
+	return nil
+}
+
+// Codec registers a body codec; the request Content-Type and Accept headers pick
+// among JSON (the default), XML and the registered codecs.
+// Usage: app.Codec(codecs.MsgPack())
+func (a *App) Codec(codec domain.Codec) *App {
+	a.codecs.Register(codec)
+	return a
+}
+
+// JSONEngine replaces encoding/json in the JSON codec with a faster library.
+// Usage: app.JSONEngine(sonic.ConfigStd)
+func (a *App) JSONEngine(engine domain.JSONEngine) *App {
+	a.config.JSONEngine = engine
+	a.codecs.Register(infrastructure.JSONCodec{Engine: engine})
+	return a
+}
+
+// BackgroundTasks configures the pool that runs Context.AddTask work.
+// Usage: app.BackgroundTasks(api.TaskConfig{Workers: 8, OnError: report})
+func (a *App) BackgroundTasks(config domain.TaskConfig) *App {
+	a.config.Tasks = config
+	return a
+}
+
+// GracefulShutdown makes Listen stop on SIGINT/SIGTERM, giving in-flight requests
+// and background tasks up to timeout to finish.
+func (a *App) GracefulShutdown(timeout time.Duration) *App {
+	a.config.ShutdownTimeout = timeout
+	return a
+}
+
+// Shutdown stops the server, waits for in-flight requests, drains background tasks
+// and runs singleton cleanups. Work still running when ctx expires is canceled.
+func (a *App) Shutdown(ctx context.Context) error {
+	root := a.root()
+	root.mu.Lock()
+	adapter := root.adapter
+	root.mu.Unlock()
+
+	var err error
+	if adapter != nil {
+		err = adapter.Shutdown(ctx)
+	}
+	a.dependencies.Close()
+	return err
+}
+
+// root returns the top-level app; groups share its server.
+func (a *App) root() *App {
+	for a.parent != nil {
+		a = a.parent
+	}
+	return a
 }
 
 // Title sets the application title.
@@ -158,6 +346,65 @@ func (a *App) Version(version string) *App {
 	return a
 }
 
+// Description sets the API description shown in the OpenAPI info object.
+func (a *App) Description(description string) *App {
+	a.config.Description = description
+	return a
+}
+
+// Contact sets the API contact information.
+func (a *App) Contact(name, url, email string) *App {
+	a.config.Contact = &domain.Contact{Name: name, URL: url, Email: email}
+	return a
+}
+
+// License sets the API license.
+func (a *App) License(name, url string) *App {
+	a.config.License = &domain.License{Name: name, URL: url}
+	return a
+}
+
+// Server adds a base URL where the API is served.
+// Can be called multiple times: app.Server("https://api.example.com", "Production")
+func (a *App) Server(url, description string) *App {
+	a.config.Servers = append(a.config.Servers, domain.Server{URL: url, Description: description})
+	return a
+}
+
+// Tag documents a tag used by routes.
+// Usage: app.Tag("users", "User management")
+func (a *App) Tag(name, description string) *App {
+	a.config.Tags = append(a.config.Tags, domain.TagInfo{Name: name, Description: description})
+	return a
+}
+
+// TagDocs documents a tag with a link to external documentation.
+func (a *App) TagDocs(name, description string, docs domain.ExternalDocs) *App {
+	a.config.Tags = append(a.config.Tags, domain.TagInfo{Name: name, Description: description, ExternalDocs: &docs})
+	return a
+}
+
+// OpenAPIVersion selects the generated OpenAPI version.
+// Use domain.OpenAPI30 (default, 3.0.3) or domain.OpenAPI31 (3.1.0).
+func (a *App) OpenAPIVersion(version string) *App {
+	a.config.OpenAPIVersion = version
+	return a
+}
+
+// MockMode serves synthesized responses instead of calling handlers, so clients
+// can be developed before the handlers exist. Responses fit each route's Response
+// schema; ResponseExample and `example` tags override synthesized values.
+// Route middlewares are skipped, global middlewares still run.
+// Usage: app.MockMode(api.MockConfig{Seed: 42, Latency: 200 * time.Millisecond})
+func (a *App) MockMode(config ...domain.MockConfig) *App {
+	mock := domain.MockConfig{}
+	if len(config) > 0 {
+		mock = config[0]
+	}
+	a.config.Mock = &mock
+	return a
+}
+
 // Swagger enables Swagger documentation.
 func (a *App) Swagger(enabled bool) *App {
 	a.swaggerEnabled = enabled
@@ -168,25 +415,179 @@ func (a *App) Swagger(enabled bool) *App {
 // The app is now ready to accept requests
 func (a *App) Listen(port string) error {
 	a.config.Port = port
+
+	// Guard clause: fail at startup, not on the first request
+	if err := a.dependencies.Validate(); err != nil {
+		return err
+	}
 	
+	// Mock mode serves the same operations from the generated spec
+	routes := a.routeRegistry
+	if a.config.Mock != nil {
+		spec, err := a.decodedSpec()
+		if err != nil {
+			return err
+		}
+		routes = application.NewRouteRegistry()
+		if err := application.NewMockServer(spec, *a.config.Mock).Register(routes); err != nil {
+			return err
+		}
+
+		// Routes outside the spec, such as static files, keep their handlers
+		for _, route := range a.routeRegistry.GetRoutes() {
+			if route.Options.Hidden {
+				_ = routes.Register(route.Method, route.Path, route.Handler, route.Options)
+			}
+		}
+	}
+
 	// Create HTTP adapter
 	adapter := infrastructure.NewHTTPAdapter(
-		a.routeRegistry,
+		routes,
 		a.middlewareRegistry,
 	)
 	
+	adapter.SetTaskPool(application.NewTaskPool(a.config.Tasks))
+	adapter.SetCodecs(a.codecs)
+	adapter.WarmUp()
+	
 	// Generate and set Swagger if enabled
 	if a.swaggerEnabled {
-		generator := application.NewOpenAPIGenerator(a.routeRegistry.GetRoutes())
-		spec, err := generator.Generate(a.config.Title, a.config.Version)
+		spec, err := a.generateSpec()
 		if err == nil {
 			adapter.SetSwaggerEnabled(true)
 			adapter.SetSwaggerSpec(spec)
 		}
 	}
-	
-	// Start server
-	return adapter.StartServer(port)
+
+	root := a.root()
+	root.mu.Lock()
+	root.adapter = adapter
+	root.mu.Unlock()
+
+	// Guard clause: no graceful shutdown, the process stops with the server
+	if a.config.ShutdownTimeout <= 0 {
+		return adapter.StartServer(port)
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	served := make(chan error, 1)
+	go func() { served <- adapter.StartServer(port) }()
+
+	select {
+	case err := <-served:
+		return err
+	case <-stop:
+		ctx, cancel := context.WithTimeout(context.Background(), a.config.ShutdownTimeout)
+		defer cancel()
+		return a.Shutdown(ctx)
+	}
+}
+
+// generateSpec builds the OpenAPI specification from the registered routes.
+func (a *App) generateSpec() (map[string]interface{}, error) {
+	generator := application.NewOpenAPIGenerator(a.routeRegistry.GetRoutes())
+	generator.SetGlobalSecurity(a.middlewareRegistry.GetSecurity())
+	generator.SetAppConfig(a.config)
+	generator.SetOpenAPIVersion(a.config.OpenAPIVersion)
+	generator.SetMediaTypes(a.codecs.MediaTypes())
+	return generator.Generate(a.config.Title, a.config.Version)
+}
+
+// WriteSpec writes the OpenAPI specification in the given format.
+// Output is deterministic, so the spec can be committed and diffed in code review.
+// Usage: app.WriteSpec(file, core.SpecYAML)
+func (a *App) WriteSpec(w io.Writer, format SpecFormat) error {
+	spec, err := a.generateSpec()
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case SpecJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(spec)
+	case SpecYAML:
+		return infrastructure.EncodeYAML(w, spec)
+	default:
+		return fmt.Errorf("unsupported spec format %q", format)
+	}
+}
+
+// VerifySpec compares the registered routes with a source OpenAPI document (JSON or YAML)
+// and returns an error listing every difference. Used in spec-first mode at startup:
+// if err := app.VerifySpec(source); err != nil { log.Fatal(err) }
+func (a *App) VerifySpec(source []byte) error {
+	want, err := infrastructure.DecodeSpec(source)
+	if err != nil {
+		return fmt.Errorf("source spec: %w", err)
+	}
+
+	got, err := a.decodedSpec()
+	if err != nil {
+		return err
+	}
+
+	// Guard clause: the app implements the source spec
+	differences := application.CompareSpecs(want, got)
+	if len(differences) == 0 {
+		return nil
+	}
+	return fmt.Errorf("app does not match the source spec:\n  %s", strings.Join(differences, "\n  "))
+}
+
+// DiffSpec compares a previous spec (JSON or YAML, e.g. the committed openapi.json)
+// with the current routes. Fail CI when application.HasBreakingChanges(changes).
+func (a *App) DiffSpec(previous []byte) ([]application.SpecChange, error) {
+	old, err := infrastructure.DecodeSpec(previous)
+	if err != nil {
+		return nil, fmt.Errorf("previous spec: %w", err)
+	}
+
+	current, err := a.decodedSpec()
+	if err != nil {
+		return nil, err
+	}
+	return application.DiffSpecs(old, current), nil
+}
+
+// decodedSpec returns the generated spec as decoded JSON, the same shapes
+// a spec read from disk has.
+func (a *App) decodedSpec() (map[string]interface{}, error) {
+	spec, err := a.generateSpec()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// GenerateClient writes a typed Go client package for the registered routes.
+// Usage: app.GenerateClient("usersclient", file)
+func (a *App) GenerateClient(pkg string, w io.Writer) error {
+	return codegen.GenerateGoClient(pkg, a.routeRegistry.GetRoutes(), w)
+}
+
+// GenerateTypeScript writes TypeScript types and a fetch-based client for the registered routes.
+// Usage: app.GenerateTypeScript(file)
+func (a *App) GenerateTypeScript(w io.Writer) error {
+	spec, err := a.generateSpec()
+	if err != nil {
+		return err
+	}
+	return codegen.GenerateTypeScript(spec, w)
 }
 
 // GetRouteRegistry returns the route registry (for testing).
@@ -199,3 +600,8 @@ func (a *App) GetMiddlewareRegistry() *MiddlewareRegistry {
 	return a.middlewareRegistry
 }
 
+
+// GetCodecRegistry returns the codec registry (for custom adapters).
+func (a *App) GetCodecRegistry() *application.CodecRegistry {
+	return a.codecs
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "io"
+
+// Codec encodes and decodes bodies of one media type (JSON, XML, MessagePack, ...).
+// Codecs are registered on the App: Content-Type selects the decoder and Accept the encoder.
+type Codec interface {
+	MediaType() string // e.g. "application/json"
+	Encode(w io.Writer, v interface{}) error
+	Decode(r io.Reader, v interface{}) error
+}
+
+// JSONEngine marshals and unmarshals JSON. Plug in a faster library with the
+// same semantics as encoding/json (sonic, go-json, jsoniter) via App.JSONEngine.
+type JSONEngine interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// Render writes v with the codec negotiated from the request's Accept header.
+// Clients accepting none of the registered media types get 406.
+func (c *Context) Render(statusCode int, data interface{}) error {
+	c.StatusCode = statusCode
+	c.Body = data
+	c.ContentType = ""
+	return nil
+}
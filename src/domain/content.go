@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Content is a response body sent as is rather than encoded by a codec (File,
+// Attachment, Blob). Transports answer Range and If-Range requests with partial
+// content when Body can seek.
+type Content struct {
+	Name        string    // File name: media type by extension and Content-Disposition filename
+	Type        string    // Media type; empty detects it from Name, then from the first bytes
+	ModTime     time.Time // Last-Modified, also checked by If-Range and If-Modified-Since; zero omits it
+	Body        io.Reader // An io.ReadSeeker enables Range requests
+	Disposition string    // "inline" or "attachment"; empty sends no Content-Disposition
+}
+
+// File sends a file from disk with its media type, Last-Modified and Range
+// support. The path is opened as given: never build it from unchecked user
+// input (App.Static serves directories safely). Missing files are 404.
+func (c *Context) File(path string) error {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return NewHTTPException(404, "Not Found")
+	}
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		file.Close()
+		return NewHTTPException(404, "Not Found")
+	}
+
+	return c.Content(&Content{
+		Name:        filepath.Base(path),
+		ModTime:     info.ModTime(),
+		Body:        file,
+		Disposition: "inline", // Keeps the file name for "Save as"
+	})
+}
+
+// Attachment sends a download named name. Seekable readers (*os.File,
+// *bytes.Reader, ...) support Range requests, so interrupted downloads resume;
+// other readers are sent whole.
+func (c *Context) Attachment(reader io.Reader, name string) error {
+	return c.Content(&Content{Name: name, Body: reader, Disposition: "attachment"})
+}
+
+// Blob sends raw bytes with a status code. An empty contentType is detected from
+// the data; 200 responses support Range requests.
+func (c *Context) Blob(statusCode int, contentType string, data []byte) error {
+	c.StatusCode = statusCode
+	return c.Content(&Content{Type: contentType, Body: bytes.NewReader(data)})
+}
+
+// Content sends content as the response body, keeping the status code set so far.
+// A Body that is an io.Closer is closed after the response, or right away when
+// the request already ended (a handler that outlived its timeout).
+func (c *Context) Content(content *Content) error {
+	// Guard clause: nothing to send
+	if content == nil || content.Body == nil {
+		return NewHTTPException(500, "content body is required")
+	}
+	if closer, ok := content.Body.(io.Closer); ok {
+		c.Cleanup(func() { closer.Close() })
+	}
+
+	// Guard clause: the response was already sent
+	if c.isReleased() {
+		return ServiceUnavailable
+	}
+	c.Body = content
+	c.ContentType = ""
+	return nil
+}
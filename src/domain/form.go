@@ -0,0 +1,69 @@
+package domain
+
+import "io"
+
+// FormFile is a file uploaded in a multipart form. Its content is spooled to a
+// temporary file while the request is read, so Open streams it from disk.
+type FormFile struct {
+	Field       string              // Form field name
+	Filename    string              // Name sent by the client; never use it as a path as-is
+	Size        int64               // Bytes
+	ContentType string              // Sniffed from the content, not the client's header
+	Header      map[string][]string // Part headers sent by the client
+
+	open func() (io.ReadCloser, error)
+}
+
+// NewFormFile creates a form file read through open. Transports call it.
+func NewFormFile(field, filename string, size int64, contentType string, header map[string][]string, open func() (io.ReadCloser, error)) *FormFile {
+	return &FormFile{
+		Field:       field,
+		Filename:    filename,
+		Size:        size,
+		ContentType: contentType,
+		Header:      header,
+		open:        open,
+	}
+}
+
+// Open returns a reader for the file content. Close it when done.
+func (f *FormFile) Open() (io.ReadCloser, error) {
+	if f.open == nil {
+		return nil, NewHTTPException(500, "form file not available")
+	}
+	return f.open()
+}
+
+// UploadLimits bounds the multipart forms accepted by a route.
+// Requests over a limit fail with 413; files of other types with 415.
+type UploadLimits struct {
+	MaxFiles     int      // Files per request (default 10)
+	MaxFileSize  int64    // Bytes per file (default 32 MiB)
+	MaxFormSize  int64    // Bytes of non-file fields (default 10 MiB)
+	AllowedTypes []string // Sniffed types such as "image/png" or "image/*"; empty allows any
+}
+
+// BindForm binds an urlencoded or multipart form to a struct using `form:"..."` tags
+// and validates it. *FormFile and []*FormFile fields receive uploaded files.
+func (c *Context) BindForm(v interface{}) error {
+	if c.Binder == nil {
+		return NewHTTPException(500, "binder not available")
+	}
+	return c.Binder.BindForm(c, v)
+}
+
+// FormFile returns the first file uploaded under a form field.
+func (c *Context) FormFile(name string) (*FormFile, error) {
+	if c.Binder == nil {
+		return nil, NewHTTPException(500, "binder not available")
+	}
+	return c.Binder.FormFile(c, name)
+}
+
+// FormFiles returns every file uploaded under a form field.
+func (c *Context) FormFiles(name string) ([]*FormFile, error) {
+	if c.Binder == nil {
+		return nil, NewHTTPException(500, "binder not available")
+	}
+	return c.Binder.FormFiles(c, name)
+}
@@ -26,11 +26,12 @@ func NewHTTPException(statusCode int, message string) *HTTPException {
 
 // Common HTTP exceptions
 var (
-	BadRequest    = NewHTTPException(400, "Bad Request")
-	Unauthorized  = NewHTTPException(401, "Unauthorized")
-	Forbidden     = NewHTTPException(403, "Forbidden")
-	NotFound      = NewHTTPException(404, "Not Found")
-	Conflict      = NewHTTPException(409, "Conflict")
-	InternalError = NewHTTPException(500, "Internal Server Error")
+	BadRequest         = NewHTTPException(400, "Bad Request")
+	Unauthorized       = NewHTTPException(401, "Unauthorized")
+	Forbidden          = NewHTTPException(403, "Forbidden")
+	NotFound           = NewHTTPException(404, "Not Found")
+	Conflict           = NewHTTPException(409, "Conflict")
+	InternalError      = NewHTTPException(500, "Internal Server Error")
+	ServiceUnavailable = NewHTTPException(503, "Service Unavailable")
+	GatewayTimeout     = NewHTTPException(504, "Gateway Timeout")
 )
-
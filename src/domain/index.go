@@ -5,6 +5,11 @@ package domain
 // Exports:
 // - Route: HTTP route entity
 // - Context: Request context value object
+// - Key: Typed keys for request-scoped values (Context.Set/Get)
+// - Stream, EventStream: Streamed responses and Server-Sent Events
+// - WSConn, WSHandler: WebSocket connections (Context.Upgrade, App.WS)
+// - Content: Files, downloads and raw bytes (Context.File, Attachment, Blob)
+// - JSONLines: NDJSON request items (BindJSONStream) and responses (StreamJSONLines)
 // - HTTPException: Domain exceptions
 // - Types: Middleware, AppConfig, etc.
 //
@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+)
+
+// NDJSONType is the media type of newline-delimited JSON (JSON Lines).
+const NDJSONType = "application/x-ndjson"
+
+// jsonLinesTypes are the request Content-Types accepted by BindJSONStream.
+var jsonLinesTypes = map[string]bool{
+	NDJSONType:              true,
+	"application/ndjson":    true,
+	"application/jsonl":     true,
+	"application/jsonlines": true,
+}
+
+const defaultJSONLineLimit = 1 << 20
+
+// JSONLines reads a newline-delimited JSON request body item by item, like
+// bufio.Scanner. Invalid lines are reported one by one, so a bulk import can
+// skip or collect them and keep going.
+type JSONLines[T any] struct {
+	ctx     *Context
+	scanner *bufio.Scanner
+	limit   int
+	started bool
+	line    int
+	item    T
+	itemErr error
+	err     error
+}
+
+// LineError reports an invalid line of a JSON Lines body. Err is the same
+// *HTTPException BindJSON returns: 400 for invalid JSON, 422 for failed validation.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+// Error implements error.
+func (e *LineError) Error() string {
+	message := e.Err.Error()
+	var httpErr *HTTPException
+	if errors.As(e.Err, &httpErr) {
+		message = httpErr.Message
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, message)
+}
+
+// Unwrap returns the line's decoding or validation error.
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// BindJSONStream reads the request body as JSON Lines, decoding and validating
+// one T per non-blank line (a generic function, since methods cannot have type
+// parameters):
+//
+//	items := domain.BindJSONStream[Order](ctx)
+//	for items.Next() {
+//		order, err := items.Item()
+//		if err != nil { failures = append(failures, err); continue } // *LineError
+//		...
+//	}
+//	if err := items.Err(); err != nil { return err }
+//
+// Bodies that are not JSON Lines fail with 415 and lines over the limit with 413.
+func BindJSONStream[T any](c *Context) *JSONLines[T] {
+	lines := &JSONLines[T]{ctx: c, limit: defaultJSONLineLimit}
+
+	// Guard clause: transports without body access
+	if c.Binder == nil {
+		lines.err = NewHTTPException(500, "binder not available")
+		return lines
+	}
+	if contentType := c.Header("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || !jsonLinesTypes[mediaType] {
+			lines.err = NewHTTPException(415, fmt.Sprintf("unsupported media type %q, want %s", contentType, NDJSONType))
+			return lines
+		}
+	}
+
+	body, err := c.Binder.RequestBody(c)
+	if err != nil {
+		lines.err = err
+		return lines
+	}
+	lines.scanner = bufio.NewScanner(body)
+	return lines
+}
+
+// Limit sets the longest accepted line in bytes (default 1 MiB). Call it before Next.
+func (s *JSONLines[T]) Limit(bytes int) *JSONLines[T] {
+	if bytes > 0 && !s.started {
+		s.limit = bytes
+	}
+	return s
+}
+
+// Next reads the next non-blank line. It returns false at the end of the body
+// or when reading fails; see Err.
+func (s *JSONLines[T]) Next() bool {
+	// Guard clause: failed or finished
+	if s.err != nil || s.scanner == nil {
+		return false
+	}
+	if !s.started {
+		s.started = true
+		s.scanner.Buffer(make([]byte, 0, min(s.limit, 64<<10)), s.limit)
+	}
+
+	for s.scanner.Scan() {
+		s.line++
+		data := bytes.TrimSpace(s.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var item T
+		s.item, s.itemErr = item, nil
+		if err := s.ctx.Binder.BindJSONBytes(s.ctx, data, &s.item); err != nil {
+			s.itemErr = &LineError{Line: s.line, Err: err}
+		}
+		return true
+	}
+
+	switch err := s.scanner.Err(); {
+	case errors.Is(err, bufio.ErrTooLong):
+		s.err = NewHTTPException(413, fmt.Sprintf("line %d is longer than %d bytes", s.line+1, s.limit))
+	case err != nil:
+		s.err = NewHTTPException(400, "failed to read request body")
+	}
+	s.scanner = nil
+	return false
+}
+
+// Item returns the current item, or a *LineError when its line is invalid.
+func (s *JSONLines[T]) Item() (T, error) {
+	return s.item, s.itemErr
+}
+
+// Line returns the 1-based line number of the current item.
+func (s *JSONLines[T]) Line() int {
+	return s.line
+}
+
+// Err returns the error that stopped reading, nil at the end of the body.
+// Invalid lines are not reported here; see Item.
+func (s *JSONLines[T]) Err() error {
+	return s.err
+}
+
+// StreamJSONLines streams a JSON Lines response. produce calls emit once per item;
+// emit blocks until the item is written, so a slow client slows the producer down
+// instead of piling items up in memory, and fails once the client disconnects:
+//
+//	return ctx.StreamJSONLines(func(emit func(interface{}) error) error {
+//		for rows.Next() { ...; if err := emit(order); err != nil { return err } }
+//		return rows.Err()
+//	})
+func (c *Context) StreamJSONLines(produce func(emit func(item interface{}) error) error) error {
+	return c.Stream(NDJSONType, func(w io.Writer) error {
+		return produce(func(item interface{}) error {
+			if err := c.Context().Err(); err != nil {
+				return err
+			}
+			return c.writeJSONLine(w, item)
+		})
+	})
+}
+
+// StreamJSONLinesFrom streams the items of a channel as JSON Lines until it is
+// closed or the client disconnects. Items already waiting are written in one
+// batch; the response is flushed whenever the channel is drained.
+func StreamJSONLinesFrom[T any](c *Context, items <-chan T) error {
+	return c.Stream(NDJSONType, func(w io.Writer) error {
+		buffered := bufio.NewWriterSize(w, 32<<10)
+		for {
+			select {
+			case <-c.Context().Done():
+				return c.Context().Err()
+			case item, ok := <-items:
+				if !ok {
+					return buffered.Flush()
+				}
+				if err := c.writeJSONLine(buffered, item); err != nil {
+					return err
+				}
+				if len(items) > 0 {
+					continue
+				}
+				if err := buffered.Flush(); err != nil {
+					return err
+				}
+			}
+		}
+	})
+}
+
+// writeJSONLine writes one item and a newline, encoded like JSON responses
+// (the app's JSON engine and generated encoders).
+func (c *Context) writeJSONLine(w io.Writer, item interface{}) error {
+	data, err := c.encodeJSON(item)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
@@ -1,5 +1,13 @@
 package domain
 
+import (
+	"context"
+	"io"
+	"net/textproto"
+	"sync"
+	"time"
+)
+
 // Route represents an HTTP route with its handler and options.
 // This is a pure domain entity with no external dependencies.
 type Route struct {
@@ -24,24 +32,165 @@ type Context struct {
 	Headers      map[string]string
 	StatusCode   int
 	Body         interface{}
+	ContentType  string // Media type of Body; empty negotiates from Accept (see Render)
 	
 	// Infrastructure adapter for BindJSON (set by infrastructure)
 	Binder       Binder
+
+	// Infrastructure adapter for Stream and SSE (set by infrastructure)
+	Streamer     Streamer
+
+	// Infrastructure adapter for Upgrade (set by infrastructure)
+	Upgrader     Upgrader
+
+	// Request-scoped context.Context (set by the transport, see WithContext)
+	ctx context.Context
+
+	// Request whose state a detached copy shares (see Detach); nil on the original
+	parent *Context
+
+	// Set on a detached copy once its request stopped waiting for it (see Abandon)
+	abandoned bool
+
+	// Held while a handler starts writing to the connection (streams, WebSocket
+	// upgrades), so Abandon waits for it to be registered or rejects it
+	responseMu sync.Mutex
+
+	// Request-scoped values (see Set and Get); guarded because a timed-out
+	// handler may still run while the transport releases the store
+	valuesMu sync.Mutex
+	values   *valueStore
+
+	// Background work queued by the handler (see AddTask)
+	tasks []Task
+
+	// Cleanups run by Release (see Cleanup); released marks the end of the request
+	cleanups []func()
+	released bool
 }
 
 // Binder interface allows Context to bind JSON without knowing the implementation.
 type Binder interface {
 	BindJSON(*Context, interface{}) error
+	BindQuery(*Context, interface{}) error
+	BindHeader(*Context, interface{}) error
+	BindCookie(*Context, interface{}) error
+	BindPath(*Context, interface{}) error
+	Bind(*Context, interface{}) error
+	BindForm(*Context, interface{}) error
+	FormFile(*Context, string) (*FormFile, error)
+	FormFiles(*Context, string) ([]*FormFile, error)
+	RequestBody(*Context) (io.Reader, error)
+	BindJSONBytes(*Context, []byte, interface{}) error
 }
 
 // RouteOptions contains additional metadata for a route.
 type RouteOptions struct {
 	Body       interface{}          // Request body type
 	Response   interface{}          // Response type
+	ResponseStatus int              // Success status code for Response (default 200)
 	Summary    string               // Endpoint summary
+	Description string              // Long endpoint description (Markdown)
+	OperationID string              // Unique OpenAPI operationId
+	Deprecated bool                 // Marks the endpoint as deprecated
+	RequestExample  interface{}     // Example request body
+	ResponseExample interface{}     // Example response body
+	ExternalDocs *ExternalDocs      // Link to external documentation
 	Tags       []string             // OpenAPI tags
 	Params     map[string]ParamSpec  // Path parameters
+	Query      interface{}          // Query parameters struct (`query:"..."` tags)
+	Headers    interface{}          // Header parameters struct (`header:"..."` tags)
+	Cookies    interface{}          // Cookie parameters struct (`cookie:"..."` tags)
+	Form       interface{}          // Form body struct (`form:"..."` tags, *FormFile fields)
+	Upload     *UploadLimits        // Multipart limits for this route
+	Timeout    time.Duration        // Handler deadline; 504 when exceeded
+	Stream     string               // Streamed response media type ("text/event-stream"); Response describes each chunk or event
+	BodyStream string               // Streamed request media type ("application/x-ndjson"); Body describes each item
+	WebSocket  *WSOptions           // WebSocket connection options (App.WS); Body and Response describe client and server messages
+	Hidden     bool                 // Left out of the OpenAPI spec and generated clients (static files)
 	Middlewares []Middleware        // Middlewares for this route
+	Security   []SecurityScheme     // Schemes enforced by the route's middlewares (SecuredMiddleware.Options)
+}
+
+// MergeRouteOptions combines route options into one.
+// Later options override earlier ones; zero values are ignored.
+func MergeRouteOptions(opts ...RouteOptions) RouteOptions {
+	var merged RouteOptions
+	for _, opt := range opts {
+		if opt.Body != nil {
+			merged.Body = opt.Body
+		}
+		if opt.Response != nil {
+			merged.Response = opt.Response
+		}
+		if opt.ResponseStatus != 0 {
+			merged.ResponseStatus = opt.ResponseStatus
+		}
+		if opt.Summary != "" {
+			merged.Summary = opt.Summary
+		}
+		if opt.Description != "" {
+			merged.Description = opt.Description
+		}
+		if opt.OperationID != "" {
+			merged.OperationID = opt.OperationID
+		}
+		if opt.Deprecated {
+			merged.Deprecated = true
+		}
+		if opt.Hidden {
+			merged.Hidden = true
+		}
+		if opt.RequestExample != nil {
+			merged.RequestExample = opt.RequestExample
+		}
+		if opt.ResponseExample != nil {
+			merged.ResponseExample = opt.ResponseExample
+		}
+		if opt.ExternalDocs != nil {
+			merged.ExternalDocs = opt.ExternalDocs
+		}
+		if len(opt.Tags) > 0 {
+			merged.Tags = opt.Tags
+		}
+		if opt.Params != nil {
+			merged.Params = opt.Params
+		}
+		if opt.Query != nil {
+			merged.Query = opt.Query
+		}
+		if opt.Headers != nil {
+			merged.Headers = opt.Headers
+		}
+		if opt.Cookies != nil {
+			merged.Cookies = opt.Cookies
+		}
+		if opt.Form != nil {
+			merged.Form = opt.Form
+		}
+		if opt.Upload != nil {
+			merged.Upload = opt.Upload
+		}
+		if opt.Stream != "" {
+			merged.Stream = opt.Stream
+		}
+		if opt.BodyStream != "" {
+			merged.BodyStream = opt.BodyStream
+		}
+		if opt.WebSocket != nil {
+			merged.WebSocket = opt.WebSocket
+		}
+		if opt.Timeout != 0 {
+			merged.Timeout = opt.Timeout
+		}
+		if len(opt.Middlewares) > 0 {
+			merged.Middlewares = opt.Middlewares
+		}
+		if len(opt.Security) > 0 {
+			merged.Security = opt.Security
+		}
+	}
+	return merged
 }
 
 // ParamSpec specifies validation rules for path parameters.
@@ -52,6 +201,89 @@ type ParamSpec struct {
 	Max      *int
 }
 
+// Context returns the request's context.Context: canceled when the client
+// disconnects and carrying the route's Timeout as a deadline.
+// Pass it to database and HTTP calls: db.QueryContext(c.Context(), ...)
+func (c *Context) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// WithContext replaces the request context, e.g. to add values or a tighter deadline.
+// Transports call it with the request's context; it updates c in place and returns it.
+func (c *Context) WithContext(ctx context.Context) *Context {
+	// Guard clause: a nil context would panic in downstream calls
+	if ctx == nil {
+		panic("domain: nil context")
+	}
+	c.ctx = ctx
+	return c
+}
+
+// Detach returns a copy of c for a handler that may outlive the request, such as
+// one bounded by a timeout. The copy has its own status, body and headers, which
+// the caller copies back once the handler returns; values, cleanups and tasks are
+// shared with c. Call Abandon when the request stops waiting for the copy.
+func (c *Context) Detach() *Context {
+	headers := make(map[string]string, len(c.Headers))
+	for name, value := range c.Headers {
+		headers[name] = value
+	}
+
+	return &Context{
+		Request:     c.Request,
+		Response:    c.Response,
+		Params:      c.Params,
+		QueryParams: c.QueryParams,
+		Headers:     headers,
+		StatusCode:  c.StatusCode,
+		Body:        c.Body,
+		ContentType: c.ContentType,
+		Binder:      c.Binder,
+		Streamer:    c.Streamer,
+		Upgrader:    c.Upgrader,
+		ctx:         c.ctx,
+		parent:      c.shared(),
+	}
+}
+
+// Abandon marks a detached copy as no longer awaited: from then on its values
+// and tasks are dropped, and streams and WebSocket upgrades fail. A stream or
+// upgrade already starting completes first, so the transport sees it.
+func (c *Context) Abandon() {
+	shared := c.shared()
+	shared.responseMu.Lock()
+	defer shared.responseMu.Unlock()
+
+	shared.valuesMu.Lock()
+	c.abandoned = true
+	shared.valuesMu.Unlock()
+}
+
+// shared returns the context holding the request state (values, cleanups, tasks).
+func (c *Context) shared() *Context {
+	if c.parent != nil {
+		return c.parent
+	}
+	return c
+}
+
+// beginResponse runs write, which takes over the connection, unless the request
+// stopped waiting for c (see Abandon).
+func (c *Context) beginResponse(write func() error) error {
+	shared := c.shared()
+	shared.responseMu.Lock()
+	defer shared.responseMu.Unlock()
+
+	// Guard clause: the transport already answered the request
+	if c.isReleased() {
+		return ServiceUnavailable
+	}
+	return write()
+}
+
 // Param returns a path parameter by name.
 func (c *Context) Param(name string) string {
 	if c.Params == nil {
@@ -68,12 +300,16 @@ func (c *Context) Query(name string) string {
 	return c.QueryParams[name]
 }
 
-// Header returns a header value by name.
+// Header returns a header value by name. Names are case-insensitive:
+// "X-API-Key" finds the request's canonical "X-Api-Key".
 func (c *Context) Header(name string) string {
 	if c.Headers == nil {
 		return ""
 	}
-	return c.Headers[name]
+	if value, ok := c.Headers[name]; ok {
+		return value
+	}
+	return c.Headers[textproto.CanonicalMIMEHeaderKey(name)]
 }
 
 // SetHeader sets a response header.
@@ -98,11 +334,53 @@ func (c *Context) BindJSON(v interface{}) error {
 	return c.Binder.BindJSON(c, v)
 }
 
+// BindQuery binds query parameters to a struct using `query:"..."` tags and validates it.
+func (c *Context) BindQuery(v interface{}) error {
+	if c.Binder == nil {
+		return NewHTTPException(500, "binder not available")
+	}
+	return c.Binder.BindQuery(c, v)
+}
+
+// BindHeader binds request headers to a struct using `header:"..."` tags and validates it.
+func (c *Context) BindHeader(v interface{}) error {
+	if c.Binder == nil {
+		return NewHTTPException(500, "binder not available")
+	}
+	return c.Binder.BindHeader(c, v)
+}
+
+// BindCookie binds request cookies to a struct using `cookie:"..."` tags and validates it.
+func (c *Context) BindCookie(v interface{}) error {
+	if c.Binder == nil {
+		return NewHTTPException(500, "binder not available")
+	}
+	return c.Binder.BindCookie(c, v)
+}
+
+// BindPath binds path parameters to a struct using `path:"..."` tags and validates it.
+func (c *Context) BindPath(v interface{}) error {
+	if c.Binder == nil {
+		return NewHTTPException(500, "binder not available")
+	}
+	return c.Binder.BindPath(c, v)
+}
+
+// Bind fills a struct from every source at once: the JSON body (when the struct has
+// json tags), then `path`, `query`, `header` and `cookie` fields, and validates it once.
+func (c *Context) Bind(v interface{}) error {
+	if c.Binder == nil {
+		return NewHTTPException(500, "binder not available")
+	}
+	return c.Binder.Bind(c, v)
+}
+
 // JSON writes a JSON response.
 // This will be implemented by the infrastructure layer.
 func (c *Context) JSON(statusCode int, data interface{}) error {
 	c.StatusCode = statusCode
 	c.Body = data
+	c.ContentType = "application/json"
 	// This will be implemented by infrastructure
 	return nil
 }
@@ -0,0 +1,40 @@
+package domain
+
+// SecurityScheme describes how a security middleware authenticates requests.
+// Pure metadata: the OpenAPI generator turns it into components/securitySchemes.
+type SecurityScheme struct {
+	Name         string // Key in components/securitySchemes (e.g. "bearerAuth")
+	Type         string // "http", "apiKey", "oauth2", "openIdConnect"
+	Scheme       string // HTTP auth scheme for Type "http" (e.g. "bearer")
+	BearerFormat string // Hint for bearer tokens (e.g. "JWT")
+	In           string // "header", "query" or "cookie" for Type "apiKey"
+	ParamName    string // Header, query or cookie name for Type "apiKey"
+	Description  string
+}
+
+// SecuredMiddleware is a middleware together with the security schemes it
+// enforces. The OpenAPI generator documents the schemes of the secured
+// middlewares an app, group or route is given (App.UseSecured, App.Secure,
+// RouteOptions.Security) without calling them.
+type SecuredMiddleware struct {
+	Middleware Middleware
+	Schemes    []SecurityScheme
+}
+
+// WithSecurity annotates a middleware with the security scheme it enforces.
+func WithSecurity(scheme SecurityScheme, mw Middleware) SecuredMiddleware {
+	return SecuredMiddleware{Middleware: mw, Schemes: []SecurityScheme{scheme}}
+}
+
+// WithScheme returns a copy that also enforces scheme, for middlewares checking
+// several credentials at once.
+func (s SecuredMiddleware) WithScheme(scheme SecurityScheme) SecuredMiddleware {
+	schemes := make([]SecurityScheme, 0, len(s.Schemes)+1)
+	schemes = append(schemes, s.Schemes...)
+	return SecuredMiddleware{Middleware: s.Middleware, Schemes: append(schemes, scheme)}
+}
+
+// Options returns route options applying the middleware and documenting its schemes.
+func (s SecuredMiddleware) Options() RouteOptions {
+	return RouteOptions{Middlewares: []Middleware{s.Middleware}, Security: s.Schemes}
+}
@@ -0,0 +1,218 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventStreamType is the media type of Server-Sent Events.
+const EventStreamType = "text/event-stream"
+
+// Streamer opens a response that is written while the handler runs (set by infrastructure).
+type Streamer interface {
+	// OpenStream sends the status and headers; every write to the returned writer
+	// reaches the client immediately.
+	OpenStream(c *Context, contentType string, headers map[string]string) (io.Writer, error)
+
+	// EncodeJSON encodes v the way JSON responses are encoded (the app's JSON
+	// engine and generated encoders), without a trailing newline.
+	EncodeJSON(c *Context, v interface{}) ([]byte, error)
+}
+
+// Event is one Server-Sent Event. Data is written as is when it is a string or
+// []byte and as JSON otherwise; multi-line data becomes several data fields.
+type Event struct {
+	ID    string // Sent back by the browser as Last-Event-ID when it reconnects
+	Event string // Event type; empty is "message"
+	Data  interface{}
+	Retry time.Duration // Reconnection delay advised to the client
+}
+
+// EventStream writes Server-Sent Events. Safe for concurrent use, so heartbeats
+// and the handler can share it.
+type EventStream struct {
+	mu          sync.Mutex
+	w           io.Writer
+	ctx         context.Context
+	encode      func(interface{}) ([]byte, error)
+	lastEventID string
+}
+
+// Stream sends the status and headers, then calls fn with a writer that flushes
+// every write to the client. Use for large or incremental bodies:
+//
+//	return ctx.Stream("text/csv", func(w io.Writer) error { ... })
+//
+// Once streaming starts, errors can no longer change the response status.
+func (c *Context) Stream(contentType string, fn func(w io.Writer) error) error {
+	w, err := c.openStream(contentType, nil)
+	if err != nil {
+		return err
+	}
+	return fn(w)
+}
+
+// SSE starts a Server-Sent Events response and returns its event writer.
+// Send events until the client goes away (Done) or the handler returns:
+//
+//	events, err := ctx.SSE()
+//	events.Heartbeat(15 * time.Second)
+//	for msg := range updates { if err := events.Send(Event{Data: msg}); err != nil { return err } }
+func (c *Context) SSE() (*EventStream, error) {
+	w, err := c.openStream(EventStreamType, map[string]string{
+		"Cache-Control":     "no-cache",
+		"X-Accel-Buffering": "no", // Keep reverse proxies from buffering events
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &EventStream{w: w, ctx: c.Context(), encode: c.encodeJSON, lastEventID: c.Header("Last-Event-Id")}, nil
+}
+
+// openStream asks the transport for a streaming writer.
+func (c *Context) openStream(contentType string, headers map[string]string) (io.Writer, error) {
+	// Guard clause: transports without streaming
+	if c.Streamer == nil {
+		return nil, NewHTTPException(500, "streaming not available")
+	}
+
+	var w io.Writer
+	err := c.beginResponse(func() (err error) {
+		w, err = c.Streamer.OpenStream(c, contentType, headers)
+		return err
+	})
+	return w, err
+}
+
+// encodeJSON encodes v with the transport's JSON codec.
+func (c *Context) encodeJSON(v interface{}) ([]byte, error) {
+	// Guard clause: no transport, plain encoding/json
+	if c.Streamer == nil {
+		return json.Marshal(v)
+	}
+	return c.Streamer.EncodeJSON(c, v)
+}
+
+// Send writes one event. It fails once the client has disconnected.
+func (s *EventStream) Send(event Event) error {
+	// Guard clause: fields must stay on one line
+	if strings.ContainsAny(event.ID, "\r\n\x00") || strings.ContainsAny(event.Event, "\r\n") {
+		return fmt.Errorf("sse: id and event must not contain line breaks")
+	}
+
+	var b strings.Builder
+	if event.ID != "" {
+		b.WriteString("id: " + event.ID + "\n")
+	}
+	if event.Event != "" {
+		b.WriteString("event: " + event.Event + "\n")
+	}
+	if event.Retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(event.Retry.Milliseconds(), 10) + "\n")
+	}
+	if event.Data != nil {
+		data, err := s.eventData(event.Data)
+		if err != nil {
+			return err
+		}
+		for _, line := range splitLines(data) {
+			b.WriteString("data: " + line + "\n")
+		}
+	}
+	b.WriteString("\n")
+	return s.write(b.String())
+}
+
+// Data sends an unnamed event carrying v.
+func (s *EventStream) Data(v interface{}) error {
+	return s.Send(Event{Data: v})
+}
+
+// Retry advises the client how long to wait before reconnecting.
+func (s *EventStream) Retry(d time.Duration) error {
+	return s.Send(Event{Retry: d})
+}
+
+// Comment writes a comment line, ignored by clients; useful to keep connections open.
+func (s *EventStream) Comment(text string) error {
+	var b strings.Builder
+	for _, line := range splitLines(text) {
+		b.WriteString(": " + line + "\n")
+	}
+	b.WriteString("\n")
+	return s.write(b.String())
+}
+
+// Heartbeat sends a comment every interval until the client disconnects or the
+// handler returns, so proxies do not close idle streams.
+func (s *EventStream) Heartbeat(interval time.Duration) {
+	// Guard clause: no interval, no heartbeat
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+				if s.Comment("heartbeat") != nil {
+					return
+				}
+			}
+		}
+	}()
+}
+
+// LastEventID returns the Last-Event-ID sent by a reconnecting client, to resume
+// after the last event it received. Empty on the first connection.
+func (s *EventStream) LastEventID() string {
+	return s.lastEventID
+}
+
+// Done is closed when the client disconnects or the handler's context ends.
+func (s *EventStream) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
+// write sends raw text, failing once the request context is over.
+func (s *EventStream) write(text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(s.w, text)
+	return err
+}
+
+// eventData renders event data: strings and bytes as is, anything else as JSON.
+func (s *EventStream) eventData(v interface{}) (string, error) {
+	switch data := v.(type) {
+	case string:
+		return data, nil
+	case []byte:
+		return string(data), nil
+	}
+	encoded, err := s.encode(v)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// splitLines splits text on "\r\n", "\r" and "\n", the line endings clients
+// recognize; each line then gets its own field, so data cannot start new fields.
+func splitLines(text string) []string {
+	return strings.Split(strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\r", "\n"), "\n")
+}
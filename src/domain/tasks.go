@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+)
+
+// Task is background work queued by a handler with Context.AddTask.
+// It runs after the response is written; the context is canceled only when
+// a graceful shutdown runs out of time.
+type Task func(context.Context) error
+
+// TaskConfig configures the background task pool.
+type TaskConfig struct {
+	Workers   int             // Concurrent tasks (default GOMAXPROCS)
+	QueueSize int             // Tasks waiting for a worker before submitters block (default 1024)
+	OnError   func(err error) // Called with task errors and recovered panics
+}
+
+// TaskPanicError is reported to TaskConfig.OnError when a task panics.
+type TaskPanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+// Error implements the error interface.
+func (e *TaskPanicError) Error() string {
+	return fmt.Sprintf("task panic: %v", e.Value)
+}
+
+// AddTask queues work to run after the response is written, e.g. sending an email.
+// Tasks are dropped when the handler returns an error.
+func (c *Context) AddTask(task Task) {
+	// Guard clause: nothing to queue
+	if task == nil {
+		return
+	}
+
+	shared := c.shared()
+	shared.valuesMu.Lock()
+	defer shared.valuesMu.Unlock()
+
+	// Guard clause: a timed-out handler's request already failed
+	if c.abandoned {
+		return
+	}
+	shared.tasks = append(shared.tasks, task)
+}
+
+// TakeTasks returns the queued tasks and clears the queue. Transports call it once
+// the response is written; tasks added afterwards are not run.
+func (c *Context) TakeTasks() []Task {
+	shared := c.shared()
+	shared.valuesMu.Lock()
+	defer shared.valuesMu.Unlock()
+
+	tasks := shared.tasks
+	shared.tasks = nil
+	return tasks
+}
@@ -1,15 +1,107 @@
 package domain
 
+import "time"
+
 // Middleware is a function that wraps a Handler.
 // Used for cross-cutting concerns like logging, authentication, etc.
 type Middleware func(HandlerFunc) HandlerFunc
 
+// Supported OpenAPI versions for generated specs.
+const (
+	OpenAPI30 = "3.0.3"
+	OpenAPI31 = "3.1.0"
+)
+
 // AppConfig holds the application configuration.
 type AppConfig struct {
 	Title       string
 	Version     string
+	OpenAPIVersion string // OpenAPI30 (default) or OpenAPI31
+	Description string
+	Contact     *Contact
+	License     *License
+	Servers     []Server
+	Tags        []TagInfo
 	Swagger     bool
 	SwaggerPath string
 	Port        string
+	Mock        *MockConfig // Serve synthesized responses instead of handlers
+	Tasks       TaskConfig  // Background task pool (Context.AddTask)
+	ShutdownTimeout time.Duration // Graceful shutdown on SIGINT/SIGTERM when > 0
+	JSONEngine  JSONEngine    // JSON encoder/decoder (nil means encoding/json)
+}
+
+// MockConfig configures mock mode: every route answers with data that fits its
+// response schema (examples first), so clients can be built before the handlers.
+type MockConfig struct {
+	Seed        int64         // Same seed and URL give the same response body
+	Latency     time.Duration // Delay added to every response
+	Jitter      time.Duration // Random extra delay in [0, Jitter)
+	ErrorRate   float64       // Fraction of requests failed with ErrorStatus (0 to 1)
+	ErrorStatus int           // Status of injected errors (default 500)
+}
+
+// StaticOptions configures App.Static.
+type StaticOptions struct {
+	Index  string        // File served for directories (default "index.html")
+	SPA    bool          // Serve Index for unknown paths without a file extension (client-side routing)
+	Browse bool          // List directories that have no Index; otherwise they are 404
+	MaxAge time.Duration // Cache-Control max-age of files; Index is always revalidated
+}
+
+// Scope controls how long a provided dependency lives.
+type Scope int
+
+const (
+	// ScopeRequest builds the value once per request; cleanup runs after the response.
+	ScopeRequest Scope = iota
+	// ScopeSingleton builds the value once per app; cleanup runs on App.Close.
+	ScopeSingleton
+	// ScopeTransient builds a new value every time it is injected.
+	ScopeTransient
+)
+
+// String returns the scope name.
+func (s Scope) String() string {
+	switch s {
+	case ScopeSingleton:
+		return "singleton"
+	case ScopeTransient:
+		return "transient"
+	default:
+		return "request"
+	}
+}
+
+// Contact is the API contact published in the OpenAPI info object.
+type Contact struct {
+	Name  string
+	URL   string
+	Email string
+}
+
+// License is the API license published in the OpenAPI info object.
+type License struct {
+	Name string
+	URL  string
+}
+
+// Server is a base URL where the API is served.
+type Server struct {
+	URL         string
+	Description string
+}
+
+// TagInfo documents an OpenAPI tag used by routes.
+type TagInfo struct {
+	Name         string
+	Description  string
+	ExternalDocs *ExternalDocs
+}
+
+// ExternalDocs links to documentation outside the spec.
+type ExternalDocs struct {
+	URL         string
+	Description string
 }
 
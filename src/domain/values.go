@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Key is a typed key for values stored on a Context.
+// Keys are compared by identity, so two packages using the same name never collide.
+// Declare them once at package level:
+//
+//	var UserKey = domain.NewKey[*User]("user")
+type Key[T any] struct {
+	name string
+}
+
+// NewKey creates a unique key for values of type T. The name is used in messages only.
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+// String returns the key name.
+func (k *Key[T]) String() string {
+	return k.name
+}
+
+// Set stores a value under the key.
+func (k *Key[T]) Set(c *Context, value T) {
+	c.Set(k, value)
+}
+
+// Get returns the value stored under the key.
+func (k *Key[T]) Get(c *Context) (T, bool) {
+	return GetValue[T](c, k)
+}
+
+// GetValue returns the value stored under key as a T.
+// The boolean is false when the key is missing or holds another type.
+func GetValue[T any](c *Context, key interface{}) (T, bool) {
+	value, ok := c.Get(key)
+	typed, isT := value.(T)
+	return typed, ok && isT
+}
+
+// valueStore holds the values of one request. Stores are pooled.
+type valueStore struct {
+	values map[interface{}]interface{}
+}
+
+var valueStorePool = sync.Pool{
+	New: func() interface{} {
+		return &valueStore{values: make(map[interface{}]interface{}, 4)}
+	},
+}
+
+// Set stores a value for the rest of the request, readable by later middlewares
+// and the handler. Prefer *Key[T] keys over strings to avoid collisions.
+// Values set after Release or Abandon (by a timed-out handler) are dropped.
+func (c *Context) Set(key, value interface{}) {
+	shared := c.shared()
+	shared.valuesMu.Lock()
+	defer shared.valuesMu.Unlock()
+
+	// Guard clause: the request is over, the store went back to the pool
+	if shared.released || c.abandoned {
+		return
+	}
+	if shared.values == nil {
+		shared.values = valueStorePool.Get().(*valueStore)
+	}
+	shared.values.values[key] = value
+}
+
+// Get returns the value stored under key.
+func (c *Context) Get(key interface{}) (interface{}, bool) {
+	shared := c.shared()
+	shared.valuesMu.Lock()
+	defer shared.valuesMu.Unlock()
+
+	if shared.values == nil {
+		return nil, false
+	}
+	value, ok := shared.values.values[key]
+	return value, ok
+}
+
+// MustGet returns the value stored under key and panics when it is missing,
+// for values a middleware guarantees (e.g. the authenticated user).
+func (c *Context) MustGet(key interface{}) interface{} {
+	value, ok := c.Get(key)
+	if !ok {
+		panic(fmt.Sprintf("domain: no value for key %v", key))
+	}
+	return value
+}
+
+// Cleanup registers fn to run once the response is written or the error sent,
+// e.g. closing a transaction whose data the response encodes. Cleanups run in
+// reverse order; registered after the request ended, fn runs immediately.
+func (c *Context) Cleanup(fn func()) {
+	// Guard clause: nothing to run
+	if fn == nil {
+		return
+	}
+
+	shared := c.shared()
+	shared.valuesMu.Lock()
+	if shared.released {
+		shared.valuesMu.Unlock()
+		fn()
+		return
+	}
+	shared.cleanups = append(shared.cleanups, fn)
+	shared.valuesMu.Unlock()
+}
+
+// isReleased reports whether the transport has finished the request (see Release)
+// or stopped waiting for this detached copy (see Abandon).
+func (c *Context) isReleased() bool {
+	shared := c.shared()
+	shared.valuesMu.Lock()
+	defer shared.valuesMu.Unlock()
+	return shared.released || c.abandoned
+}
+
+// Release runs the cleanups and returns the value store to the pool. Transports
+// call it once the response is written; later Set calls are ignored.
+func (c *Context) Release() {
+	c = c.shared()
+	c.valuesMu.Lock()
+	store, cleanups := c.values, c.cleanups
+	c.values, c.cleanups, c.released = nil, nil, true
+	c.valuesMu.Unlock()
+
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+
+	// Guard clause: nothing was stored
+	if store == nil {
+		return
+	}
+	for key := range store.values {
+		delete(store.values, key)
+	}
+	valueStorePool.Put(store)
+}
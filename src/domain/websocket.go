@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+// WSMessageType is the kind of a WebSocket data message.
+type WSMessageType int
+
+const (
+	// WSText is a UTF-8 text message (JSON messages are text).
+	WSText WSMessageType = 1
+	// WSBinary is a binary message.
+	WSBinary WSMessageType = 2
+)
+
+// WebSocket close codes (RFC 6455, section 7.4.1). Applications may use 4000-4999.
+const (
+	WSCloseNormal          = 1000
+	WSCloseGoingAway       = 1001 // Server shutting down or browser leaving the page
+	WSCloseProtocolError   = 1002
+	WSCloseUnsupportedData = 1003
+	WSCloseNoStatus        = 1005 // Close frame without a code; never sent
+	WSCloseAbnormal        = 1006 // Connection lost without a close frame; never sent
+	WSCloseInvalidPayload  = 1007 // Text message that is not UTF-8
+	WSClosePolicyViolation = 1008
+	WSCloseTooBig          = 1009 // Message over WSOptions.ReadLimit
+	WSCloseInternalError   = 1011
+)
+
+// WSHandler handles a WebSocket connection. It runs after the route's middlewares
+// accepted the upgrade request; the connection is closed when it returns, normally
+// on nil, with the code of a returned *WSCloseError, and with 1011 otherwise.
+type WSHandler func(c *Context, conn WSConn) error
+
+// WSConn is an upgraded WebSocket connection. Reads and writes may run in separate
+// goroutines; ping, pong and close frames are answered automatically.
+type WSConn interface {
+	// ReadMessage waits for the next data message. Messages the handler does not
+	// read wait in order, so slow readers apply backpressure to the client.
+	ReadMessage() (WSMessageType, []byte, error)
+	// WriteMessage sends one data message.
+	WriteMessage(messageType WSMessageType, data []byte) error
+	// ReadJSON reads the next message into v and validates it like BindJSON:
+	// 400 for invalid JSON and 422 for failed validate tags. The connection stays open.
+	ReadJSON(v interface{}) error
+	// WriteJSON sends v as a JSON text message.
+	WriteJSON(v interface{}) error
+	// Close sends a close frame and waits briefly for the client's reply.
+	Close(code int, reason string) error
+	// Subprotocol returns the negotiated subprotocol, empty when none.
+	Subprotocol() string
+	// Done is closed when the connection ends, from either side.
+	Done() <-chan struct{}
+}
+
+// WSCloseError reports why a connection ended. Reads and writes return it once
+// the connection is closed; Code is WSCloseAbnormal when it was lost.
+// Handlers may return one to close with a specific code.
+type WSCloseError struct {
+	Code   int
+	Reason string
+}
+
+// Error implements error.
+func (e *WSCloseError) Error() string {
+	if e.Reason == "" {
+		return "websocket closed: " + strconv.Itoa(e.Code)
+	}
+	return "websocket closed: " + strconv.Itoa(e.Code) + " " + e.Reason
+}
+
+// WSOptions configures WebSocket connections of a route.
+type WSOptions struct {
+	ReadLimit    int64         // Bytes per message; larger messages close with 1009 (default 1 MiB)
+	PingInterval time.Duration // Keepalive pings; negative disables them (default 30s)
+	PongTimeout  time.Duration // Silence after a ping before the connection is dropped (default 10s)
+	WriteTimeout time.Duration // Deadline of each write (default 10s)
+	Origins      []string      // Allowed browser origins ("https://app.example.com", "*"); empty allows the same host only
+	Subprotocols []string      // Supported subprotocols, picked in the client's order of preference
+}
+
+// Upgrader switches a request to the WebSocket protocol (set by infrastructure).
+type Upgrader interface {
+	Upgrade(c *Context, options WSOptions) (WSConn, error)
+}
+
+// Upgrade switches the request to the WebSocket protocol. App.WS calls it before
+// the handler; call it directly to upgrade from a regular GET route.
+// Requests that are not a valid handshake fail with 4xx before anything is sent.
+func (c *Context) Upgrade(options WSOptions) (WSConn, error) {
+	// Guard clause: transports without WebSocket support
+	if c.Upgrader == nil {
+		return nil, NewHTTPException(500, "websocket not available")
+	}
+
+	var conn WSConn
+	err := c.beginResponse(func() (err error) {
+		conn, err = c.Upgrader.Upgrade(c, options)
+		return err
+	})
+	return conn, err
+}
@@ -0,0 +1,66 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+
+	"github.com/syntropysoft/syntrogo/src/application"
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// JSONCodec reads and writes application/json, decoding registered unions by discriminator.
+type JSONCodec struct {
+	Engine domain.JSONEngine // nil means encoding/json
+}
+
+// MediaType implements domain.Codec.
+func (JSONCodec) MediaType() string { return "application/json" }
+
+// Encode implements domain.Codec.
+func (c JSONCodec) Encode(w io.Writer, v interface{}) error {
+	// Guard clause: default engine
+	if c.Engine == nil {
+		return json.NewEncoder(w).Encode(v)
+	}
+
+	data, err := c.Engine.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// Decode implements domain.Codec.
+func (c JSONCodec) Decode(r io.Reader, v interface{}) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return application.DecodeJSONWith(c.Engine, data, v)
+}
+
+// XMLCodec reads and writes application/xml with encoding/xml.
+type XMLCodec struct{}
+
+// MediaType implements domain.Codec.
+func (XMLCodec) MediaType() string { return "application/xml" }
+
+// Encode implements domain.Codec.
+func (XMLCodec) Encode(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(v)
+}
+
+// Decode implements domain.Codec.
+func (XMLCodec) Decode(r io.Reader, v interface{}) error {
+	return xml.NewDecoder(r).Decode(v)
+}
+
+// DefaultCodecs returns the codecs every app starts with: JSON (the default) and XML.
+func DefaultCodecs() []domain.Codec {
+	return []domain.Codec{JSONCodec{}, XMLCodec{}}
+}
@@ -0,0 +1,75 @@
+package infrastructure
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// writeContent sends a *domain.Content body (File, Attachment, Blob). Seekable
+// 200 responses go through http.ServeContent for Range, If-Range, conditional
+// requests and HEAD; other bodies are copied whole.
+func writeContent(w http.ResponseWriter, r *http.Request, ctx *domain.Context, content *domain.Content) error {
+	header := w.Header()
+	body := content.Body
+
+	contentType := content.Type
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(content.Name))
+	}
+	if contentType == "" {
+		sniffed, head, err := sniffContentType(body)
+		if err != nil {
+			return err
+		}
+		contentType, body = sniffed, head
+	}
+	header.Set("Content-Type", contentType)
+
+	if content.Disposition != "" {
+		disposition := content.Disposition
+		if content.Name != "" {
+			// Non-ASCII names are sent as filename* (RFC 2231)
+			if formatted := mime.FormatMediaType(disposition, map[string]string{"filename": content.Name}); formatted != "" {
+				disposition = formatted
+			}
+		}
+		header.Set("Content-Disposition", disposition)
+	}
+
+	if seeker, ok := body.(io.ReadSeeker); ok && ctx.StatusCode == http.StatusOK {
+		http.ServeContent(w, r, content.Name, content.ModTime, seeker)
+		return nil
+	}
+
+	if !content.ModTime.IsZero() {
+		header.Set("Last-Modified", content.ModTime.UTC().Format(http.TimeFormat))
+	}
+	w.WriteHeader(ctx.StatusCode)
+	_, _ = io.Copy(w, body) // The status is sent; a failed copy means the client left
+	return nil
+}
+
+// sniffContentType detects the media type from the first 512 bytes. It returns
+// a reader that still yields the whole body: the seeker rewound, or the read
+// bytes followed by the rest.
+func sniffContentType(body io.Reader) (string, io.Reader, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(body, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", nil, err
+	}
+	contentType := http.DetectContentType(head[:n])
+
+	if seeker, ok := body.(io.ReadSeeker); ok {
+		if _, err := seeker.Seek(-int64(n), io.SeekCurrent); err != nil {
+			return "", nil, err
+		}
+		return contentType, seeker, nil
+	}
+	return contentType, io.MultiReader(bytes.NewReader(head[:n]), body), nil
+}
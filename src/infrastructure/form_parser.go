@@ -0,0 +1,283 @@
+package infrastructure
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/syntropysoft/syntrogo/src/application"
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// Default upload limits (see domain.UploadLimits).
+const (
+	defaultMaxFiles    = 10
+	defaultMaxFileSize = 32 << 20
+	defaultMaxFormSize = 10 << 20
+	sniffLength        = 512 // Bytes read by http.DetectContentType
+)
+
+var (
+	formKey         = domain.NewKey[*requestForm]("infrastructure.form")
+	uploadLimitsKey = domain.NewKey[*domain.UploadLimits]("infrastructure.upload_limits")
+	formFileType    = reflect.TypeOf((*domain.FormFile)(nil))
+)
+
+// requestForm is a parsed form, cached on the context for the rest of the request.
+type requestForm struct {
+	values url.Values
+	files  map[string][]*domain.FormFile
+	spool  []string // Temporary files removed after the response
+}
+
+// BindForm binds an urlencoded or multipart form to a struct and validates it.
+func (a *HTTPAdapter) BindForm(ctx *domain.Context, v interface{}) error {
+	form, err := a.form(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := a.paramBinder.Bind(v, "form", func(name string) []string {
+		return form.values[name]
+	}); err != nil {
+		return err
+	}
+	setFormFiles(v, form.files)
+
+	// Validate
+	return a.validate(v)
+}
+
+// FormFile returns the first file uploaded under name; 400 when there is none.
+func (a *HTTPAdapter) FormFile(ctx *domain.Context, name string) (*domain.FormFile, error) {
+	files, err := a.FormFiles(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, domain.NewHTTPException(400, fmt.Sprintf("file %q is required", name))
+	}
+	return files[0], nil
+}
+
+// FormFiles returns every file uploaded under name.
+func (a *HTTPAdapter) FormFiles(ctx *domain.Context, name string) ([]*domain.FormFile, error) {
+	form, err := a.form(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return form.files[name], nil
+}
+
+// form parses the request form once per request.
+func (a *HTTPAdapter) form(ctx *domain.Context) (*requestForm, error) {
+	if form, ok := formKey.Get(ctx); ok {
+		return form, nil
+	}
+
+	req, err := a.httpRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	limits := uploadLimits(ctx)
+
+	mediaType, params, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	var form *requestForm
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		form, err = parseURLEncoded(req.Body, limits)
+	case "multipart/form-data":
+		form, err = parseMultipart(multipart.NewReader(req.Body, params["boundary"]), limits)
+	default:
+		return nil, domain.NewHTTPException(415, "expected an urlencoded or multipart form")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	formKey.Set(ctx, form)
+	ctx.Cleanup(form.remove) // Spooled files go once the response is written
+	return form, nil
+}
+
+// uploadLimits returns the route limits with defaults applied.
+func uploadLimits(ctx *domain.Context) domain.UploadLimits {
+	var limits domain.UploadLimits
+	if route, ok := uploadLimitsKey.Get(ctx); ok && route != nil {
+		limits = *route
+	}
+	if limits.MaxFiles <= 0 {
+		limits.MaxFiles = defaultMaxFiles
+	}
+	if limits.MaxFileSize <= 0 {
+		limits.MaxFileSize = defaultMaxFileSize
+	}
+	if limits.MaxFormSize <= 0 {
+		limits.MaxFormSize = defaultMaxFormSize
+	}
+	return limits
+}
+
+// parseURLEncoded reads an application/x-www-form-urlencoded body.
+func parseURLEncoded(body io.Reader, limits domain.UploadLimits) (*requestForm, error) {
+	data, err := io.ReadAll(io.LimitReader(body, limits.MaxFormSize+1))
+	if err != nil {
+		return nil, domain.NewHTTPException(400, "failed to read request body")
+	}
+	if int64(len(data)) > limits.MaxFormSize {
+		return nil, domain.NewHTTPException(413, fmt.Sprintf("form exceeds %d bytes", limits.MaxFormSize))
+	}
+
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return nil, domain.NewHTTPException(400, "invalid form")
+	}
+	return &requestForm{values: values, files: map[string][]*domain.FormFile{}}, nil
+}
+
+// parseMultipart reads a multipart body part by part. Fields stay in memory up to
+// MaxFormSize; files are spooled to disk, enforcing the limits while they stream.
+func parseMultipart(reader *multipart.Reader, limits domain.UploadLimits) (*requestForm, error) {
+	form := &requestForm{values: url.Values{}, files: map[string][]*domain.FormFile{}}
+	fieldBytes, fileCount := int64(0), 0
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return form, nil
+		}
+		if err != nil {
+			form.remove()
+			return nil, domain.NewHTTPException(400, "invalid multipart form")
+		}
+
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+
+		// Plain field
+		if part.FileName() == "" {
+			data, err := io.ReadAll(io.LimitReader(part, limits.MaxFormSize-fieldBytes+1))
+			fieldBytes += int64(len(data))
+			if err != nil || fieldBytes > limits.MaxFormSize {
+				form.remove()
+				return nil, domain.NewHTTPException(413, fmt.Sprintf("form fields exceed %d bytes", limits.MaxFormSize))
+			}
+			form.values.Add(name, string(data))
+			continue
+		}
+
+		// File
+		fileCount++
+		if fileCount > limits.MaxFiles {
+			form.remove()
+			return nil, domain.NewHTTPException(413, fmt.Sprintf("more than %d files", limits.MaxFiles))
+		}
+		file, err := form.spoolFile(part, limits)
+		if err != nil {
+			form.remove()
+			return nil, err
+		}
+		form.files[name] = append(form.files[name], file)
+	}
+}
+
+// spoolFile copies one file part to a temporary file, sniffing its type first.
+func (f *requestForm) spoolFile(part *multipart.Part, limits domain.UploadLimits) (*domain.FormFile, error) {
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(part, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, domain.NewHTTPException(400, "failed to read uploaded file")
+	}
+	head = head[:n]
+
+	// Guard clause: type sniffed from content, the client's Content-Type is not trusted
+	contentType := http.DetectContentType(head)
+	if !allowedType(contentType, limits.AllowedTypes) {
+		return nil, domain.NewHTTPException(415, fmt.Sprintf("file type %s is not allowed", mediaTypeOf(contentType)))
+	}
+
+	tmp, err := os.CreateTemp("", "syntrogo-upload-*")
+	if err != nil {
+		return nil, domain.NewHTTPException(500, "failed to store uploaded file")
+	}
+	path := tmp.Name()
+	f.spool = append(f.spool, path)
+
+	size, err := io.Copy(tmp, io.LimitReader(io.MultiReader(bytes.NewReader(head), part), limits.MaxFileSize+1))
+	closeErr := tmp.Close()
+	if size > limits.MaxFileSize {
+		return nil, domain.NewHTTPException(413, fmt.Sprintf("file %q exceeds %d bytes", part.FileName(), limits.MaxFileSize))
+	}
+	if err != nil || closeErr != nil {
+		return nil, domain.NewHTTPException(400, "failed to read uploaded file")
+	}
+
+	return domain.NewFormFile(part.FormName(), part.FileName(), size, contentType, part.Header, func() (io.ReadCloser, error) {
+		return os.Open(path)
+	}), nil
+}
+
+// remove deletes the spooled files.
+func (f *requestForm) remove() {
+	for _, path := range f.spool {
+		os.Remove(path)
+	}
+	f.spool = nil
+}
+
+// allowedType matches a sniffed content type against "type/subtype" or "type/*" patterns.
+func allowedType(contentType string, allowed []string) bool {
+	// Guard clause: no restriction
+	if len(allowed) == 0 {
+		return true
+	}
+
+	mediaType := mediaTypeOf(contentType)
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == mediaType || pattern == "*/*" {
+			return true
+		}
+		if strings.HasSuffix(pattern, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(pattern, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// mediaTypeOf drops parameters: "text/plain; charset=utf-8" becomes "text/plain".
+func mediaTypeOf(contentType string) string {
+	return strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+}
+
+// setFormFiles fills *FormFile and []*FormFile fields tagged `form:"..."`.
+func setFormFiles(v interface{}, files map[string][]*domain.FormFile) {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Ptr || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return
+	}
+	target = target.Elem()
+
+	for i, field := range application.TypeInfoOf(target.Type()).Fields {
+		name := field.Params["form"]
+		if name == "" || !field.IsExported() || len(files[name]) == 0 {
+			continue
+		}
+
+		switch field.Type {
+		case formFileType:
+			target.Field(i).Set(reflect.ValueOf(files[name][0]))
+		case reflect.SliceOf(formFileType):
+			target.Field(i).Set(reflect.ValueOf(files[name]))
+		}
+	}
+}
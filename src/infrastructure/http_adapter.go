@@ -1,9 +1,15 @@
 package infrastructure
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"errors"
 	"io"
 	"net/http"
+	"reflect"
+	"sync"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/syntropysoft/syntrogo/src/application"
@@ -24,8 +30,15 @@ type HTTPAdapter struct {
 	routeRegistry      *application.RouteRegistry
 	middlewareRegistry *application.MiddlewareRegistry
 	validator          *validator.Validate
+	paramBinder        *application.ParamBinder
 	swaggerEnabled     bool
 	swaggerSpec        map[string]interface{}
+	tasks              *application.TaskPool // Runs Context.AddTask work after responses
+	codecs             *application.CodecRegistry // Body codecs by media type
+
+	mu      sync.Mutex // Guards server and sockets
+	server  *http.Server
+	sockets map[*wsConn]struct{} // Open WebSocket connections, closed by Shutdown
 }
 
 // NewHTTPAdapter creates a new HTTP adapter.
@@ -34,6 +47,30 @@ func NewHTTPAdapter(routeRegistry *application.RouteRegistry, middlewareRegistry
 		routeRegistry:      routeRegistry,
 		middlewareRegistry: middlewareRegistry,
 		validator:          validator.New(),
+		paramBinder:        application.NewParamBinder(),
+		tasks:              application.NewTaskPool(domain.TaskConfig{}),
+		codecs:             application.NewCodecRegistry(DefaultCodecs()...),
+	}
+}
+
+// WarmUp primes the type metadata and validator caches with every declared route
+// type, so the first request does not pay for parsing tags. Routes registered
+// through the App are already prepared; this covers registries filled directly.
+func (a *HTTPAdapter) WarmUp() {
+	for _, route := range a.routeRegistry.GetRoutes() {
+		opts := route.Options
+		application.PrepareTypes(opts.Body, opts.Query, opts.Headers, opts.Cookies, opts.Form, opts.Response)
+		for _, value := range []interface{}{opts.Body, opts.Query, opts.Headers, opts.Cookies, opts.Form} {
+			t := reflect.TypeOf(value)
+			for t != nil && t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t == nil || t.Kind() != reflect.Struct {
+				continue
+			}
+			// Validation errors on the zero value are expected; only the cache matters
+			_ = a.validator.Struct(reflect.New(t).Interface())
+		}
 	}
 }
 
@@ -45,9 +82,13 @@ func (a *HTTPAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		a.handleSwagger(w)
 		return
 	}
+	if r.URL.Path == "/openapi.yaml" && a.swaggerEnabled {
+		a.handleSwaggerYAML(w)
+		return
+	}
 
 	// Find route
-	route := a.routeRegistry.Find(r.Method, r.URL.Path)
+	route, params := a.routeRegistry.Match(r.Method, r.URL.Path)
 	
 	// If not found
 	if route == nil {
@@ -57,11 +98,15 @@ func (a *HTTPAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	
 	// Create domain context
 	ctx := &domain.Context{
-		Params:      make(map[string]string),
+		Params:      params,
 		QueryParams: make(map[string]string),
 		Headers:     make(map[string]string),
 		StatusCode:  200,
 	}
+	// Handlers that do not pick a status answer with the route's declared one
+	if route.Options.ResponseStatus != 0 {
+		ctx.StatusCode = route.Options.ResponseStatus
+	}
 	
 	// Bind query parameters
 	for key, values := range r.URL.Query() {
@@ -81,9 +126,20 @@ func (a *HTTPAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx.Request = r
 	ctx.Response = w
 	ctx.Binder = a // Set binder for BindJSON
+	ctx.Streamer = a
+	ctx.Upgrader = a
+	ctx.WithContext(r.Context()) // Canceled when the client disconnects
+	defer ctx.Release()          // Run cleanups and return pooled values after the response
+	defer closeStream(ctx)       // Late writes of a timed-out handler fail
+	defer closeWebSocket(ctx)    // Hijacked connections outlive ServeHTTP otherwise
+	if route.Options.Upload != nil {
+		uploadLimitsKey.Set(ctx, route.Options.Upload)
+	}
 	
+	// Bound the handler by the route timeout
+	handler := application.Timeout(route.Options.Timeout)(route.Handler)
+
 	// Apply global middlewares
-	handler := route.Handler
 	handler = a.middlewareRegistry.Apply(handler)
 	
 	// Apply route-specific middlewares
@@ -91,20 +147,110 @@ func (a *HTTPAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		handler = route.Middlewares[i](handler)
 	}
 	
-	// Call handler with all middlewares applied
-	if err := handler(ctx); err != nil {
-		a.handleError(w, err)
+	// Call handler with all middlewares applied; once streaming, the status is sent
+	err := handler(ctx)
+	if upgraded(ctx) {
+		finishWebSocket(ctx, err)
+	} else if err != nil {
+		if !streaming(ctx) {
+			a.handleError(w, application.ContextError(err))
+		}
 		return
 	}
 	
-	// Write response
-	if ctx.Body != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(ctx.StatusCode)
-		json.NewEncoder(w).Encode(ctx.Body)
-	} else if ctx.StatusCode != 200 {
+	// Write response (streams were written by Stream or SSE, WebSockets own the connection)
+	switch {
+	case streaming(ctx), upgraded(ctx):
+	case ctx.Body != nil:
+		if err := a.writeBody(w, r, ctx); err != nil {
+			a.handleError(w, err)
+			return
+		}
+	case ctx.StatusCode != 200:
 		w.WriteHeader(ctx.StatusCode)
 	}
+
+	// Hand background tasks to the pool once the response is written
+	if tasks := ctx.TakeTasks(); len(tasks) > 0 {
+		_ = a.tasks.Submit(tasks...) // Rejections are reported to TaskConfig.OnError
+	}
+}
+
+// writeBody encodes the response body with the codec chosen by the handler (JSON)
+// or negotiated from Accept (Render); Content bodies are written unencoded.
+// Values the negotiated codec cannot encode are sent with the default codec when
+// the client accepts it, and answered with 406 otherwise. Encoding happens before the status is sent,
+// so failures still produce an error response.
+func (a *HTTPAdapter) writeBody(w http.ResponseWriter, r *http.Request, ctx *domain.Context) error {
+	// Files and raw bytes are sent as is
+	if content, ok := ctx.Body.(*domain.Content); ok {
+		return writeContent(w, r, ctx, content)
+	}
+
+	var codec domain.Codec
+	if ctx.ContentType != "" {
+		found, ok := a.codecs.ForContentType(ctx.ContentType)
+		if !ok {
+			return fmt.Errorf("no codec for %s", ctx.ContentType)
+		}
+		codec = found
+	} else {
+		w.Header().Add("Vary", "Accept")
+		negotiated, ok := a.codecs.Negotiate(r.Header.Get("Accept"))
+		if !ok {
+			return domain.NewHTTPException(406, "Not Acceptable")
+		}
+		codec = negotiated
+	}
+
+	var body bytes.Buffer
+	if err := encodeBody(&body, codec, ctx.Body); err != nil {
+		// A negotiated codec may not handle the value (encoding/xml and maps): use the default
+		fallback, ok := a.codecs.Default()
+		if ctx.ContentType != "" || !ok || fallback.MediaType() == codec.MediaType() {
+			return err
+		}
+		// Guard clause: the client refused the default's media type
+		if !a.codecs.Acceptable(r.Header.Get("Accept"), fallback.MediaType()) {
+			return domain.NewHTTPException(406, "Not Acceptable")
+		}
+		body.Reset()
+		if err := encodeBody(&body, fallback, ctx.Body); err != nil {
+			return err
+		}
+		codec = fallback
+	}
+	w.Header().Set("Content-Type", codec.MediaType())
+	w.WriteHeader(ctx.StatusCode)
+	_, err := w.Write(body.Bytes())
+	return err
+}
+
+// encodeBody encodes v with the codec, through generated code when the codec is
+// JSON and `syntrogo gen binders` covered the type.
+func encodeBody(w *bytes.Buffer, codec domain.Codec, v interface{}) error {
+	if _, isJSON := codec.(JSONCodec); isJSON {
+		if generated, ok := generatedCodec(reflect.TypeOf(v)); ok {
+			data, err := generated.Marshal(v)
+			if err != nil {
+				return err
+			}
+			w.Write(data)
+			return w.WriteByte('\n')
+		}
+	}
+	return codec.Encode(w, v)
+}
+
+// generatedCodec returns the generated code for T or *T.
+func generatedCodec(t reflect.Type) (application.TypeCodec, bool) {
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return application.TypeCodec{}, false
+	}
+	return application.LookupTypeCodec(t)
 }
 
 // handleError handles errors from handlers.
@@ -126,8 +272,47 @@ func (a *HTTPAdapter) handleError(w http.ResponseWriter, err error) {
 }
 
 // StartServer starts the HTTP server on the given port.
+// It returns nil once Shutdown stops the server.
 func (a *HTTPAdapter) StartServer(port string) error {
-	return http.ListenAndServe(":"+port, a)
+	a.mu.Lock()
+	a.server = &http.Server{Addr: ":" + port, Handler: a}
+	server := a.server
+	a.mu.Unlock()
+
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown closes WebSocket connections with 1001, stops accepting connections,
+// waits for in-flight requests and then drains background tasks, all within ctx.
+func (a *HTTPAdapter) Shutdown(ctx context.Context) error {
+	a.mu.Lock()
+	server := a.server
+	a.mu.Unlock()
+
+	// Hijacked connections are not tracked by the server
+	a.closeWebSockets(domain.WSCloseGoingAway, "server shutting down")
+
+	var err error
+	if server != nil {
+		err = server.Shutdown(ctx)
+	}
+	if taskErr := a.tasks.Shutdown(ctx); err == nil {
+		err = taskErr
+	}
+	return err
+}
+
+// SetCodecs sets the codecs used for request and response bodies.
+func (a *HTTPAdapter) SetCodecs(codecs *application.CodecRegistry) {
+	a.codecs = codecs
+}
+
+// SetTaskPool sets the pool that runs background tasks.
+func (a *HTTPAdapter) SetTaskPool(pool *application.TaskPool) {
+	a.tasks = pool
 }
 
 // SetSwaggerEnabled enables Swagger documentation.
@@ -153,16 +338,56 @@ func (a *HTTPAdapter) handleSwagger(w http.ResponseWriter) {
 	json.NewEncoder(w).Encode(a.swaggerSpec)
 }
 
-// BindJSON decodes JSON from the request body and validates it.
+// handleSwaggerYAML serves the OpenAPI specification as YAML.
+func (a *HTTPAdapter) handleSwaggerYAML(w http.ResponseWriter) {
+	if a.swaggerSpec == nil {
+		http.Error(w, "Swagger spec not available", 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	EncodeYAML(w, a.swaggerSpec)
+}
+
+// BindJSON decodes the request body and validates it. The codec follows the
+// Content-Type header (JSON when absent); other types get 415.
 func (a *HTTPAdapter) BindJSON(ctx *domain.Context, v interface{}) error {
-	if ctx.Request == nil {
-		return domain.NewHTTPException(400, "request not available")
+	if err := a.decodeBody(ctx, v); err != nil {
+		return err
+	}
+	return a.validateBody(v)
+}
+
+// validateBody validates a decoded body; a union target is validated through its
+// concrete value.
+func (a *HTTPAdapter) validateBody(v interface{}) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() == reflect.Ptr && target.Elem().Kind() == reflect.Interface {
+		if target.Elem().IsNil() {
+			return domain.NewHTTPException(400, "request body is required")
+		}
+		target = target.Elem().Elem()
+	}
+	if reflect.Indirect(target).Kind() == reflect.Struct {
+		return a.validate(target.Interface())
 	}
 	
-	// Type assert to *http.Request
-	req, ok := ctx.Request.(*http.Request)
+	return nil
+}
+
+// decodeBody decodes the request body into v with the codec for its Content-Type
+// (JSON when absent), without validating it.
+func (a *HTTPAdapter) decodeBody(ctx *domain.Context, v interface{}) error {
+	req, err := a.httpRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Guard clause: unsupported body format
+	codec, ok := a.codecs.ForContentType(req.Header.Get("Content-Type"))
 	if !ok {
-		return domain.NewHTTPException(500, "invalid request type")
+		return domain.NewHTTPException(415, fmt.Sprintf("unsupported media type %q", req.Header.Get("Content-Type")))
 	}
 	
 	// Read body
@@ -171,18 +396,201 @@ func (a *HTTPAdapter) BindJSON(ctx *domain.Context, v interface{}) error {
 		return domain.NewHTTPException(400, "failed to read request body")
 	}
 	
-	// Decode JSON
-	if len(body) > 0 {
-		if err := json.Unmarshal(body, v); err != nil {
+	// Guard clause: an empty body leaves v unchanged
+	if len(body) == 0 {
+		return nil
+	}
+	return decodeBytes(codec, body, v)
+}
+
+// decodeBytes decodes data into v, through generated code when the codec is JSON
+// and `syntrogo gen binders` covered the type. The JSON codec resolves registered
+// unions by discriminator. Failures are 400.
+func decodeBytes(codec domain.Codec, data []byte, v interface{}) error {
+	decode := func() error { return codec.Decode(bytes.NewReader(data), v) }
+	if _, isJSON := codec.(JSONCodec); isJSON {
+		if generated, ok := generatedCodec(reflect.TypeOf(v)); ok && reflect.TypeOf(v).Kind() == reflect.Ptr {
+			decode = func() error { return generated.Unmarshal(data, v) }
+		}
+	}
+	if err := decode(); err != nil {
+		if httpErr, ok := err.(*domain.HTTPException); ok {
+			return httpErr
+		}
+		if _, isJSON := codec.(JSONCodec); isJSON {
 			return domain.NewHTTPException(400, "invalid JSON")
 		}
+		return domain.NewHTTPException(400, fmt.Sprintf("invalid %s body", codec.MediaType()))
 	}
-	
+	return nil
+}
+
+// RequestBody returns the request body for handlers that read it incrementally.
+func (a *HTTPAdapter) RequestBody(ctx *domain.Context) (io.Reader, error) {
+	req, err := a.httpRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return req.Body, nil
+}
+
+// BindJSONBytes decodes one JSON document, such as a JSON Lines item, and
+// validates it like BindJSON. v may point to a pointer to a struct.
+func (a *HTTPAdapter) BindJSONBytes(ctx *domain.Context, data []byte, v interface{}) error {
+	codec, ok := a.codecs.ForContentType("application/json")
+	if !ok {
+		return domain.NewHTTPException(500, "no JSON codec")
+	}
+	if err := decodeBytes(codec, data, v); err != nil {
+		return err
+	}
+
+	target := reflect.ValueOf(v)
+	for target.Kind() == reflect.Ptr && target.Elem().Kind() == reflect.Ptr && !target.Elem().IsNil() {
+		target = target.Elem()
+	}
+	return a.validateBody(target.Interface())
+}
+
+// Bind fills v from the JSON body, path, query, headers and cookies, then validates it.
+// The body is read only when v declares json tags; parameters win over body fields.
+func (a *HTTPAdapter) Bind(ctx *domain.Context, v interface{}) error {
+	req, err := a.httpRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	if hasJSONFields(v) {
+		if err := a.decodeBody(ctx, v); err != nil {
+			return err
+		}
+	}
+
+	query, cookies := req.URL.Query(), req.Cookies()
+	sources := []struct {
+		tag    string
+		lookup func(string) []string
+	}{
+		{"path", pathLookup(ctx)},
+		{"query", func(name string) []string { return query[name] }},
+		{"header", req.Header.Values},
+		{"cookie", cookieLookup(cookies)},
+	}
+	for _, source := range sources {
+		if err := a.paramBinder.Bind(v, source.tag, source.lookup); err != nil {
+			return err
+		}
+	}
+
+	// Validate once, after every source is applied
+	return a.validate(v)
+}
+
+// BindQuery binds query parameters to a struct and validates it.
+func (a *HTTPAdapter) BindQuery(ctx *domain.Context, v interface{}) error {
+	req, err := a.httpRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := req.URL.Query()
+	return a.bindParams(v, "query", func(name string) []string {
+		return query[name]
+	})
+}
+
+// BindHeader binds request headers to a struct and validates it.
+func (a *HTTPAdapter) BindHeader(ctx *domain.Context, v interface{}) error {
+	req, err := a.httpRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	return a.bindParams(v, "header", req.Header.Values)
+}
+
+// BindCookie binds request cookies to a struct and validates it.
+func (a *HTTPAdapter) BindCookie(ctx *domain.Context, v interface{}) error {
+	req, err := a.httpRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	return a.bindParams(v, "cookie", cookieLookup(req.Cookies()))
+}
+
+// BindPath binds path parameters to a struct and validates it.
+func (a *HTTPAdapter) BindPath(ctx *domain.Context, v interface{}) error {
+	return a.bindParams(v, "path", pathLookup(ctx))
+}
+
+// bindParams fills v from tagged values and validates the result.
+func (a *HTTPAdapter) bindParams(v interface{}, tag string, lookup func(string) []string) error {
+	if err := a.paramBinder.Bind(v, tag, lookup); err != nil {
+		return err
+	}
+
 	// Validate
-	if err := a.validator.Struct(v); err != nil {
+	return a.validate(v)
+}
+
+// validate checks v with its generated validator when one exists, otherwise with
+// go-playground/validator. Failures are 422.
+func (a *HTTPAdapter) validate(v interface{}) error {
+	var err error
+	if generated, ok := generatedCodec(reflect.TypeOf(v)); ok && generated.Validate != nil {
+		err = generated.Validate(v)
+	} else {
+		err = a.validator.Struct(v)
+	}
+
+	if err != nil {
 		return domain.NewHTTPException(422, err.Error())
 	}
-	
 	return nil
 }
 
+// httpRequest extracts the *http.Request stored in the domain context.
+func (a *HTTPAdapter) httpRequest(ctx *domain.Context) (*http.Request, error) {
+	if ctx.Request == nil {
+		return nil, domain.NewHTTPException(400, "request not available")
+	}
+
+	req, ok := ctx.Request.(*http.Request)
+	if !ok {
+		return nil, domain.NewHTTPException(500, "invalid request type")
+	}
+	return req, nil
+}
+
+// pathLookup returns the path parameter lookup of a request.
+func pathLookup(ctx *domain.Context) func(string) []string {
+	return func(name string) []string {
+		if value, ok := ctx.Params[name]; ok {
+			return []string{value}
+		}
+		return nil
+	}
+}
+
+// cookieLookup returns every value sent for a cookie name.
+func cookieLookup(cookies []*http.Cookie) func(string) []string {
+	return func(name string) []string {
+		values := []string{}
+		for _, cookie := range cookies {
+			if cookie.Name == name {
+				values = append(values, cookie.Value)
+			}
+		}
+		return values
+	}
+}
+
+// hasJSONFields reports whether v points to a struct with json-tagged fields.
+func hasJSONFields(v interface{}) bool {
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	return application.TypeInfoOf(t).HasJSON
+}
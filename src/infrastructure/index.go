@@ -4,6 +4,12 @@ package infrastructure
 //
 // Adapters:
 // - HTTPAdapter: Adapts net/http to our domain
+// - Form parser: Urlencoded and multipart forms, uploads spooled to disk
+// - JSONCodec, XMLCodec: Built-in body codecs
+// - Stream writer: Flushed streaming responses (Context.Stream, Context.SSE)
+// - WebSocket: RFC 6455 over net/http hijacking, keepalive and origin checks
+// - Content writer: Files, downloads and blobs with Range, If-Range and type detection
+// - StaticHandler: Files from an fs.FS with validators, ranges, precompressed variants and SPA fallback
 // - Future: Redis, Database, etc.
 //
 // Principles:
@@ -0,0 +1,250 @@
+package infrastructure
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// StaticParam is the catch-all parameter holding the file path of static routes.
+const StaticParam = "filepath"
+
+// precompressed lists the encoded variants looked up next to each file, preferred first.
+var precompressed = []struct {
+	suffix   string
+	encoding string
+}{
+	{".br", "br"},
+	{".gz", "gzip"},
+}
+
+// staticServer serves an fs.FS. Conditional requests, Range and If-Range are
+// handled by http.ServeContent.
+type staticServer struct {
+	files   fs.FS
+	options domain.StaticOptions
+	etags   sync.Map // Content hashes of files without a modification time (embed.FS)
+}
+
+// StaticHandler serves files from files for a route ending in "*filepath" (App.Static).
+func StaticHandler(files fs.FS, options domain.StaticOptions) domain.HandlerFunc {
+	if options.Index == "" {
+		options.Index = "index.html"
+	}
+	server := &staticServer{files: files, options: options}
+	return server.serve
+}
+
+// serve answers one request for a file, a directory or the SPA entry point.
+func (s *staticServer) serve(ctx *domain.Context) error {
+	r, ok := ctx.Request.(*http.Request)
+	if !ok {
+		return domain.NewHTTPException(500, "invalid request type")
+	}
+	w, ok := ctx.Response.(http.ResponseWriter)
+	if !ok {
+		return domain.NewHTTPException(500, "response writer not available")
+	}
+
+	name := staticName(ctx.Param(StaticParam))
+	info, err := fs.Stat(s.files, name)
+	switch {
+	case err == nil && !info.IsDir():
+		return s.serveFile(w, r, name, info)
+	case err == nil:
+		// Directories end with a slash so relative links in their pages resolve
+		if !strings.HasSuffix(r.URL.Path, "/") {
+			target := r.URL.Path + "/"
+			if r.URL.RawQuery != "" {
+				target += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, target, http.StatusMovedPermanently)
+			return nil
+		}
+		index := path.Join(name, s.options.Index)
+		if indexInfo, err := fs.Stat(s.files, index); err == nil && !indexInfo.IsDir() {
+			return s.serveFile(w, r, index, indexInfo)
+		}
+		if s.options.Browse {
+			return s.list(w, r, name)
+		}
+	}
+
+	// Client-side routes have no extension; missing assets stay 404
+	if s.options.SPA && path.Ext(name) == "" {
+		if indexInfo, err := fs.Stat(s.files, s.options.Index); err == nil && !indexInfo.IsDir() {
+			return s.serveFile(w, r, s.options.Index, indexInfo)
+		}
+	}
+	return domain.NewHTTPException(404, "Not Found")
+}
+
+// serveFile writes a file, or its precompressed variant when the client accepts it.
+func (s *staticServer) serveFile(w http.ResponseWriter, r *http.Request, name string, info fs.FileInfo) error {
+	header := w.Header()
+	served, encoding, varies := name, "", false
+	for _, variant := range precompressed {
+		variantInfo, err := fs.Stat(s.files, name+variant.suffix)
+		if err != nil || variantInfo.IsDir() {
+			continue
+		}
+		varies = true
+		if encoding == "" && acceptsEncoding(r.Header.Get("Accept-Encoding"), variant.encoding) {
+			served, encoding, info = name+variant.suffix, variant.encoding, variantInfo
+		}
+	}
+	if varies {
+		header.Add("Vary", "Accept-Encoding")
+	}
+
+	file, err := s.files.Open(served)
+	if err != nil {
+		return domain.NewHTTPException(404, "Not Found")
+	}
+	defer file.Close()
+	content, err := readSeeker(file)
+	if err != nil {
+		return err
+	}
+
+	etag, err := s.etag(served, info, content)
+	if err != nil {
+		return err
+	}
+
+	// The type comes from the original name; encoded bodies cannot be sniffed
+	if encoding != "" {
+		etag = strings.TrimSuffix(etag, `"`) + "-" + encoding + `"` // Never equal to the identity ETag
+		header.Set("Content-Encoding", encoding)
+		header.Set("Content-Type", s.contentType(name))
+	}
+	header.Set("ETag", etag)
+
+	switch {
+	case path.Base(name) == s.options.Index:
+		header.Set("Cache-Control", "no-cache") // New deploys must reach the entry point
+	case s.options.MaxAge > 0:
+		header.Set("Cache-Control", "public, max-age="+strconv.Itoa(int(s.options.MaxAge.Seconds())))
+	}
+
+	http.ServeContent(w, r, name, info.ModTime(), content)
+	return nil
+}
+
+// etag identifies a file version by modification time and size, or by a hash
+// of its content when the file system has no modification times.
+func (s *staticServer) etag(name string, info fs.FileInfo, content io.ReadSeeker) (string, error) {
+	if !info.ModTime().IsZero() {
+		return fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()), nil
+	}
+
+	key := name + "\x00" + strconv.FormatInt(info.Size(), 10)
+	if cached, ok := s.etags.Load(key); ok {
+		return cached.(string), nil
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	etag := fmt.Sprintf(`"%x"`, hash.Sum(nil)[:16])
+	s.etags.Store(key, etag)
+	return etag, nil
+}
+
+// contentType returns the media type of a file by extension, sniffing its
+// content when the extension is unknown.
+func (s *staticServer) contentType(name string) string {
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		return contentType
+	}
+
+	file, err := s.files.Open(name)
+	if err != nil {
+		return "application/octet-stream"
+	}
+	defer file.Close()
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(file, head)
+	return http.DetectContentType(head[:n])
+}
+
+// list writes an HTML listing of a directory.
+func (s *staticServer) list(w http.ResponseWriter, r *http.Request, name string) error {
+	entries, err := fs.ReadDir(s.files, name)
+	if err != nil {
+		return domain.NewHTTPException(404, "Not Found")
+	}
+
+	var page bytes.Buffer
+	title := html.EscapeString(r.URL.Path)
+	page.WriteString("<!doctype html>\n<meta charset=\"utf-8\">\n<title>Index of " + title + "</title>\n")
+	page.WriteString("<h1>Index of " + title + "</h1>\n<pre>\n")
+	for _, entry := range entries {
+		label := entry.Name()
+		if entry.IsDir() {
+			label += "/"
+		}
+		link := url.URL{Path: label} // Escapes characters such as '?' and '#'
+		page.WriteString("<a href=\"" + html.EscapeString(link.String()) + "\">" + html.EscapeString(label) + "</a>\n")
+	}
+	page.WriteString("</pre>\n")
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	_, err = w.Write(page.Bytes())
+	return err
+}
+
+// staticName converts a request path to an fs.FS name; ".." cannot leave the root.
+func staticName(requested string) string {
+	name := strings.TrimPrefix(path.Clean("/"+requested), "/")
+	if name == "" {
+		return "."
+	}
+	return name
+}
+
+// acceptsEncoding reports whether an Accept-Encoding header allows an encoding.
+func acceptsEncoding(accept, encoding string) bool {
+	for _, item := range strings.Split(accept, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), encoding) {
+			continue
+		}
+		quality, weighted := strings.CutPrefix(strings.TrimSpace(params), "q=")
+		if !weighted {
+			return true
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(quality), 64)
+		return err == nil && weight > 0
+	}
+	return false
+}
+
+// readSeeker returns the file as an io.ReadSeeker, buffering it when the file
+// system does not provide one.
+func readSeeker(file fs.File) (io.ReadSeeker, error) {
+	if seeker, ok := file.(io.ReadSeeker); ok {
+		return seeker, nil
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
@@ -0,0 +1,107 @@
+package infrastructure
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// errStreamClosed is returned by writes after the handler has returned.
+var errStreamClosed = errors.New("stream closed: the handler has returned")
+
+var streamKey = domain.NewKey[*streamWriter]("infrastructure.stream")
+
+// streamWriter flushes every write to the client. Writes after the handler
+// returns (a timed-out handler still running) fail instead of touching the
+// finished response.
+type streamWriter struct {
+	mu         sync.Mutex
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	closed     bool
+}
+
+// OpenStream implements domain.Streamer.
+func (a *HTTPAdapter) OpenStream(ctx *domain.Context, contentType string, headers map[string]string) (io.Writer, error) {
+	// Guard clause: one stream per response
+	if _, open := streamKey.Get(ctx); open {
+		return nil, domain.NewHTTPException(500, "response is already streaming")
+	}
+	w, ok := ctx.Response.(http.ResponseWriter)
+	if !ok {
+		return nil, domain.NewHTTPException(500, "response writer not available")
+	}
+
+	for name, value := range headers {
+		w.Header().Set(name, value)
+	}
+	w.Header().Set("Content-Type", contentType)
+
+	// Long-lived streams outlive the server's WriteTimeout; unsupported writers ignore this
+	controller := http.NewResponseController(w)
+	_ = controller.SetWriteDeadline(time.Time{})
+
+	w.WriteHeader(ctx.StatusCode)
+	_ = controller.Flush()
+
+	stream := &streamWriter{w: w, controller: controller}
+	streamKey.Set(ctx, stream)
+	return stream, nil
+}
+
+// EncodeJSON implements domain.Streamer with the codec JSON responses use.
+func (a *HTTPAdapter) EncodeJSON(ctx *domain.Context, v interface{}) ([]byte, error) {
+	codec, ok := a.codecs.ForContentType("application/json")
+	if !ok {
+		return nil, domain.NewHTTPException(500, "no JSON codec")
+	}
+	var body bytes.Buffer
+	if err := encodeBody(&body, codec, v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(body.Bytes(), []byte("\n")), nil
+}
+
+// Write implements io.Writer.
+func (s *streamWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, errStreamClosed
+	}
+	n, err := s.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	if err := s.controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return n, err
+	}
+	return n, nil
+}
+
+// close rejects further writes.
+func (s *streamWriter) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+}
+
+// streaming reports whether the handler opened a stream.
+func streaming(ctx *domain.Context) bool {
+	_, open := streamKey.Get(ctx)
+	return open
+}
+
+// closeStream ends the stream of a request, if any.
+func closeStream(ctx *domain.Context) {
+	if stream, ok := streamKey.Get(ctx); ok {
+		stream.close()
+	}
+}
@@ -0,0 +1,631 @@
+package infrastructure
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// WebSocket protocol (RFC 6455) on top of net/http hijacking.
+
+const (
+	wsAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	defaultWSReadLimit    = 1 << 20
+	defaultWSPingInterval = 30 * time.Second
+	defaultWSPongTimeout  = 10 * time.Second
+	defaultWSWriteTimeout = 10 * time.Second
+
+	// wsCloseTimeout bounds the wait for the client's close reply
+	wsCloseTimeout = time.Second
+)
+
+// Frame opcodes.
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+var wsKey = domain.NewKey[*wsConn]("infrastructure.websocket")
+
+// wsConn is an upgraded connection. A read loop handles control frames as they
+// arrive and hands data messages to ReadMessage one at a time.
+type wsConn struct {
+	adapter     *HTTPAdapter
+	conn        net.Conn
+	reader      *bufio.Reader
+	options     domain.WSOptions
+	subprotocol string
+
+	writeMu   sync.Mutex
+	closeSent bool          // A close frame was written; guarded by writeMu
+	closing   chan struct{} // Closed with closeSent: unread messages are dropped
+
+	messages chan wsMessage
+	done     chan struct{} // Closed when the connection ends
+	endOnce  sync.Once
+	err      *domain.WSCloseError // Why the connection ended, set before done closes
+}
+
+// wsMessage is a complete data message.
+type wsMessage struct {
+	kind domain.WSMessageType
+	data []byte
+}
+
+// wsFrame is one frame as read from the client, unmasked.
+type wsFrame struct {
+	fin     bool
+	opcode  byte
+	payload []byte
+}
+
+// Upgrade implements domain.Upgrader.
+func (a *HTTPAdapter) Upgrade(ctx *domain.Context, options domain.WSOptions) (domain.WSConn, error) {
+	// Guard clause: one response per request
+	if streaming(ctx) || upgraded(ctx) {
+		return nil, domain.NewHTTPException(500, "response already started")
+	}
+	r, err := a.httpRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	w, ok := ctx.Response.(http.ResponseWriter)
+	if !ok {
+		return nil, domain.NewHTTPException(500, "response writer not available")
+	}
+
+	// Guard clause: validate the handshake before taking over the connection
+	if r.Method != http.MethodGet || !headerHasToken(r.Header, "Connection", "upgrade") || !headerHasToken(r.Header, "Upgrade", "websocket") {
+		w.Header().Set("Upgrade", "websocket")
+		return nil, domain.NewHTTPException(426, "websocket upgrade required")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		return nil, domain.NewHTTPException(426, "unsupported websocket version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if nonce, err := base64.StdEncoding.DecodeString(key); err != nil || len(nonce) != 16 {
+		return nil, domain.NewHTTPException(400, "invalid Sec-WebSocket-Key")
+	}
+	if !originAllowed(r, options.Origins) {
+		return nil, domain.NewHTTPException(403, "origin not allowed")
+	}
+	subprotocol := selectSubprotocol(r.Header, options.Subprotocols)
+
+	netConn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return nil, domain.NewHTTPException(500, "websocket not supported by the connection")
+	}
+	// The server's read and write timeouts were meant for the HTTP exchange
+	_ = netConn.SetDeadline(time.Time{})
+
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+	rw.WriteString("Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n")
+	if subprotocol != "" {
+		rw.WriteString("Sec-WebSocket-Protocol: " + subprotocol + "\r\n")
+	}
+	rw.WriteString("\r\n")
+	if err := rw.Flush(); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	conn := &wsConn{
+		adapter:     a,
+		conn:        netConn,
+		reader:      rw.Reader, // May already hold frames sent right after the handshake
+		options:     wsDefaults(options),
+		subprotocol: subprotocol,
+		closing:     make(chan struct{}),
+		messages:    make(chan wsMessage),
+		done:        make(chan struct{}),
+	}
+	wsKey.Set(ctx, conn)
+	a.trackWebSocket(conn, true)
+
+	// The request context is not canceled by a hijacked client going away
+	connCtx, cancel := context.WithCancel(ctx.Context())
+	ctx.WithContext(connCtx)
+	go func() {
+		<-conn.done
+		cancel()
+	}()
+
+	go conn.readLoop()
+	if conn.options.PingInterval > 0 {
+		go conn.keepAlive()
+	}
+	return conn, nil
+}
+
+// wsDefaults fills unset options.
+func wsDefaults(options domain.WSOptions) domain.WSOptions {
+	if options.ReadLimit <= 0 {
+		options.ReadLimit = defaultWSReadLimit
+	}
+	if options.PingInterval == 0 {
+		options.PingInterval = defaultWSPingInterval
+	}
+	if options.PongTimeout <= 0 {
+		options.PongTimeout = defaultWSPongTimeout
+	}
+	if options.WriteTimeout <= 0 {
+		options.WriteTimeout = defaultWSWriteTimeout
+	}
+	return options
+}
+
+// ReadMessage implements domain.WSConn.
+func (c *wsConn) ReadMessage() (domain.WSMessageType, []byte, error) {
+	select {
+	case message := <-c.messages:
+		return message.kind, message.data, nil
+	case <-c.done:
+		return 0, nil, c.err
+	}
+}
+
+// WriteMessage implements domain.WSConn.
+func (c *wsConn) WriteMessage(messageType domain.WSMessageType, data []byte) error {
+	switch messageType {
+	case domain.WSText:
+		return c.writeFrame(wsOpText, data)
+	case domain.WSBinary:
+		return c.writeFrame(wsOpBinary, data)
+	default:
+		return errors.New("websocket: unknown message type")
+	}
+}
+
+// ReadJSON implements domain.WSConn.
+func (c *wsConn) ReadJSON(v interface{}) error {
+	_, data, err := c.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return c.adapter.BindJSONBytes(nil, data, v)
+}
+
+// WriteJSON implements domain.WSConn.
+func (c *wsConn) WriteJSON(v interface{}) error {
+	codec, ok := c.adapter.codecs.ForContentType("application/json")
+	if !ok {
+		return domain.NewHTTPException(500, "no JSON codec")
+	}
+	var body bytes.Buffer
+	if err := encodeBody(&body, codec, v); err != nil {
+		return err
+	}
+	return c.writeFrame(wsOpText, bytes.TrimSuffix(body.Bytes(), []byte("\n")))
+}
+
+// Close implements domain.WSConn.
+func (c *wsConn) Close(code int, reason string) error {
+	// Guard clause: already ended
+	select {
+	case <-c.done:
+		return nil
+	default:
+	}
+
+	err := c.sendClose(code, reason)
+
+	// The read loop ends the connection when the client replies
+	timer := time.NewTimer(wsCloseTimeout)
+	defer timer.Stop()
+	select {
+	case <-c.done:
+	case <-timer.C:
+		c.end(&domain.WSCloseError{Code: code, Reason: reason})
+	}
+	return err
+}
+
+// Subprotocol implements domain.WSConn.
+func (c *wsConn) Subprotocol() string {
+	return c.subprotocol
+}
+
+// Done implements domain.WSConn.
+func (c *wsConn) Done() <-chan struct{} {
+	return c.done
+}
+
+// readLoop reads until the connection ends, answering control frames right away.
+func (c *wsConn) readLoop() {
+	for {
+		kind, data, err := c.readMessage()
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		select {
+		case c.messages <- wsMessage{kind: kind, data: data}:
+		case <-c.closing:
+			// Closing: drop data and keep reading for the client's close frame
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// readMessage reads frames until a data message is complete.
+func (c *wsConn) readMessage() (domain.WSMessageType, []byte, error) {
+	var kind domain.WSMessageType
+	var message []byte
+	for {
+		frame, err := c.readFrame(c.options.ReadLimit - int64(len(message)))
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch frame.opcode {
+		case wsOpPing:
+			// A failed write ends the connection; the next read reports it
+			_ = c.writeFrame(wsOpPong, frame.payload)
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			return 0, nil, c.closeReceived(frame.payload)
+		case wsOpText, wsOpBinary:
+			if kind != 0 {
+				return 0, nil, wsProtocolError("new message inside a fragmented message")
+			}
+			kind = domain.WSMessageType(frame.opcode)
+		case wsOpContinuation:
+			if kind == 0 {
+				return 0, nil, wsProtocolError("continuation without a message")
+			}
+		default:
+			return 0, nil, wsProtocolError("unknown opcode")
+		}
+
+		message = append(message, frame.payload...)
+		if !frame.fin {
+			continue
+		}
+		if kind == domain.WSText && !utf8.Valid(message) {
+			return 0, nil, &domain.WSCloseError{Code: domain.WSCloseInvalidPayload, Reason: "text message is not UTF-8"}
+		}
+		return kind, message, nil
+	}
+}
+
+// readFrame reads one frame; data frames over limit bytes are rejected before
+// their payload is read.
+func (c *wsConn) readFrame(limit int64) (wsFrame, error) {
+	// Keepalive: pongs and messages must keep arriving
+	if c.options.PingInterval > 0 {
+		_ = c.conn.SetReadDeadline(time.Now().Add(c.options.PingInterval + c.options.PongTimeout))
+	}
+
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return wsFrame{}, err
+	}
+	frame := wsFrame{fin: header[0]&0x80 != 0, opcode: header[0] & 0x0f}
+
+	// Guard clause: no extensions are negotiated and clients must mask
+	if header[0]&0x70 != 0 {
+		return wsFrame{}, wsProtocolError("reserved bits set")
+	}
+	if header[1]&0x80 == 0 {
+		return wsFrame{}, wsProtocolError("client frames must be masked")
+	}
+
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return wsFrame{}, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return wsFrame{}, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+
+	if frame.opcode >= wsOpClose {
+		if !frame.fin || length > 125 {
+			return wsFrame{}, wsProtocolError("invalid control frame")
+		}
+	} else if length > uint64(limit) {
+		return wsFrame{}, &domain.WSCloseError{Code: domain.WSCloseTooBig, Reason: "message too big"}
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+		return wsFrame{}, err
+	}
+	frame.payload = make([]byte, length)
+	if _, err := io.ReadFull(c.reader, frame.payload); err != nil {
+		return wsFrame{}, err
+	}
+	for i := range frame.payload {
+		frame.payload[i] ^= mask[i%4]
+	}
+	return frame, nil
+}
+
+// closeReceived answers the client's close frame and returns the close error.
+func (c *wsConn) closeReceived(payload []byte) error {
+	closeErr := &domain.WSCloseError{Code: domain.WSCloseNoStatus}
+	switch {
+	case len(payload) == 1:
+		return wsProtocolError("invalid close frame")
+	case len(payload) >= 2:
+		closeErr.Code = int(binary.BigEndian.Uint16(payload))
+		closeErr.Reason = string(payload[2:])
+		if !validCloseCode(closeErr.Code) || !utf8.ValidString(closeErr.Reason) {
+			return wsProtocolError("invalid close frame")
+		}
+	}
+
+	// Echo the code; a no-op when this frame answers our own close
+	code := closeErr.Code
+	if code == domain.WSCloseNoStatus {
+		code = 0
+	}
+	_ = c.sendClose(code, "")
+	c.end(closeErr)
+	return closeErr
+}
+
+// fail ends the connection after a read error, telling the client why when the
+// protocol was violated.
+func (c *wsConn) fail(err error) {
+	var closeErr *domain.WSCloseError
+	if !errors.As(err, &closeErr) {
+		c.end(&domain.WSCloseError{Code: domain.WSCloseAbnormal, Reason: err.Error()})
+		return
+	}
+	_ = c.sendClose(closeErr.Code, closeErr.Reason)
+	c.end(closeErr)
+}
+
+// keepAlive pings the client every interval until the connection ends.
+func (c *wsConn) keepAlive() {
+	ticker := time.NewTicker(c.options.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if c.writeFrame(wsOpPing, nil) != nil {
+				return
+			}
+		}
+	}
+}
+
+// sendClose writes a close frame once; code 0 sends one without a code.
+func (c *wsConn) sendClose(code int, reason string) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	// Guard clause: already closing
+	if c.closeSent {
+		return nil
+	}
+	c.closeSent = true
+	close(c.closing)
+
+	var payload []byte
+	if code != 0 {
+		if len(reason) > 123 {
+			reason = reason[:123] // Control frames carry at most 125 bytes
+		}
+		payload = binary.BigEndian.AppendUint16(nil, uint16(code))
+		payload = append(payload, reason...)
+	}
+	return c.write(wsOpClose, payload)
+}
+
+// writeFrame writes one unfragmented frame; nothing may follow a close frame.
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.err
+	default:
+	}
+	if c.closeSent {
+		return &domain.WSCloseError{Code: domain.WSCloseNormal, Reason: "connection closing"}
+	}
+	return c.write(opcode, payload)
+}
+
+// write sends a frame; the caller holds writeMu. Server frames are not masked.
+func (c *wsConn) write(opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	switch length := len(payload); {
+	case length <= 125:
+		header = append(header, byte(length))
+	case length <= 0xffff:
+		header = binary.BigEndian.AppendUint16(append(header, 126), uint16(length))
+	default:
+		header = binary.BigEndian.AppendUint64(append(header, 127), uint64(length))
+	}
+
+	_ = c.conn.SetWriteDeadline(time.Now().Add(c.options.WriteTimeout))
+	buffers := net.Buffers{header, payload}
+	if _, err := buffers.WriteTo(c.conn); err != nil {
+		closeErr := &domain.WSCloseError{Code: domain.WSCloseAbnormal, Reason: err.Error()}
+		c.end(closeErr)
+		return closeErr
+	}
+	return nil
+}
+
+// end closes the connection once, recording why.
+func (c *wsConn) end(err *domain.WSCloseError) {
+	c.endOnce.Do(func() {
+		c.err = err
+		close(c.done)
+		c.conn.Close()
+		c.adapter.trackWebSocket(c, false)
+	})
+}
+
+// upgraded reports whether the handler switched the request to a WebSocket.
+func upgraded(ctx *domain.Context) bool {
+	_, ok := wsKey.Get(ctx)
+	return ok
+}
+
+// finishWebSocket closes the connection once the handler returns: normally on
+// nil, with the code of a *WSCloseError, and with 1011 for other errors.
+func finishWebSocket(ctx *domain.Context, err error) {
+	conn, ok := wsKey.Get(ctx)
+	if !ok {
+		return
+	}
+
+	var closeErr *domain.WSCloseError
+	switch {
+	case err == nil:
+		conn.Close(domain.WSCloseNormal, "")
+	case errors.As(err, &closeErr):
+		conn.Close(closeErr.Code, closeErr.Reason)
+	default:
+		conn.Close(domain.WSCloseInternalError, "internal error")
+	}
+}
+
+// closeWebSocket closes a connection the handler left open (it panicked).
+func closeWebSocket(ctx *domain.Context) {
+	if conn, ok := wsKey.Get(ctx); ok {
+		conn.Close(domain.WSCloseInternalError, "internal error")
+	}
+}
+
+// trackWebSocket adds or removes an open connection.
+func (a *HTTPAdapter) trackWebSocket(conn *wsConn, open bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !open {
+		delete(a.sockets, conn)
+		return
+	}
+	if a.sockets == nil {
+		a.sockets = map[*wsConn]struct{}{}
+	}
+	a.sockets[conn] = struct{}{}
+}
+
+// closeWebSockets closes every open connection and waits for the close handshakes.
+func (a *HTTPAdapter) closeWebSockets(code int, reason string) {
+	a.mu.Lock()
+	conns := make([]*wsConn, 0, len(a.sockets))
+	for conn := range a.sockets {
+		conns = append(conns, conn)
+	}
+	a.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, conn := range conns {
+		wg.Add(1)
+		go func(conn *wsConn) {
+			defer wg.Done()
+			conn.Close(code, reason)
+		}(conn)
+	}
+	wg.Wait()
+}
+
+// wsProtocolError is a violation of RFC 6455, closed with 1002.
+func wsProtocolError(reason string) *domain.WSCloseError {
+	return &domain.WSCloseError{Code: domain.WSCloseProtocolError, Reason: reason}
+}
+
+// validCloseCode reports whether a client may send code in a close frame.
+func validCloseCode(code int) bool {
+	switch {
+	case code >= 3000 && code <= 4999:
+		return true
+	case code < 1000 || code > 1014:
+		return false
+	}
+	return code != 1004 && code != domain.WSCloseNoStatus && code != domain.WSCloseAbnormal
+}
+
+// acceptKey computes Sec-WebSocket-Accept for a client key.
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + wsAcceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// headerHasToken reports whether a comma-separated header lists token (case-insensitive).
+func headerHasToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, item := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(item), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// originAllowed checks the browser Origin against the allowed list, or against
+// the request host when the list is empty. Clients without Origin are not browsers.
+func originAllowed(r *http.Request, allowed []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, candidate := range allowed {
+		if candidate == "*" || strings.EqualFold(candidate, origin) {
+			return true
+		}
+	}
+	if len(allowed) > 0 {
+		return false
+	}
+
+	parsed, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(parsed.Host, r.Host)
+}
+
+// selectSubprotocol picks the client's most preferred supported subprotocol.
+func selectSubprotocol(header http.Header, supported []string) string {
+	for _, value := range header.Values("Sec-WebSocket-Protocol") {
+		for _, offered := range strings.Split(value, ",") {
+			offered = strings.TrimSpace(offered)
+			for _, candidate := range supported {
+				if offered == candidate {
+					return offered
+				}
+			}
+		}
+	}
+	return ""
+}
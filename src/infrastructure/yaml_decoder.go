@@ -0,0 +1,120 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DecodeYAML parses a YAML document into the same shapes encoding/json produces:
+// map[string]interface{}, []interface{}, string, float64, bool and nil.
+// The full YAML 1.2 syntax is accepted, anchors and aliases included; a stream
+// with more than one document is rejected.
+func DecodeYAML(data []byte) (interface{}, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		// Guard clause: empty document
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var next interface{}
+	if err := decoder.Decode(&next); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("yaml: multiple documents are not supported")
+	}
+	return normalizeYAML(document)
+}
+
+// DecodeSpec parses an OpenAPI document written as JSON or YAML.
+func DecodeSpec(data []byte) (map[string]interface{}, error) {
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("{")) {
+		var spec map[string]interface{}
+		if err := json.Unmarshal(trimmed, &spec); err != nil {
+			return nil, err
+		}
+		return spec, nil
+	}
+
+	document, err := DecodeYAML(data)
+	if err != nil {
+		return nil, err
+	}
+	spec, ok := document.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("spec must be a mapping at the top level")
+	}
+	return spec, nil
+}
+
+// normalizeYAML converts decoded YAML values to their encoding/json shapes.
+// Non-string keys (such as unquoted status codes) become strings.
+func normalizeYAML(v interface{}) (interface{}, error) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			normalized, err := normalizeYAML(item)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = normalized
+		}
+		return result, nil
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			normalized, err := normalizeYAML(item)
+			if err != nil {
+				return nil, err
+			}
+			result[yamlKey(key)] = normalized
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			normalized, err := normalizeYAML(item)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = normalized
+		}
+		return result, nil
+	case int:
+		return float64(value), nil
+	case int64:
+		return float64(value), nil
+	case uint64:
+		return float64(value), nil
+	case float64, string, bool, nil:
+		return value, nil
+	case time.Time:
+		return value.Format(time.RFC3339Nano), nil
+	default:
+		return nil, fmt.Errorf("yaml: unsupported value of type %T", v)
+	}
+}
+
+// yamlKey formats a mapping key as its JSON object key.
+func yamlKey(key interface{}) string {
+	switch value := key.(type) {
+	case nil:
+		return "null"
+	case string:
+		return value
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,59 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EncodeYAML writes v as a block-style YAML document.
+// Values are normalized through encoding/json first, so json tags and
+// Marshaler implementations apply exactly as they do for /swagger.json.
+// Map keys are sorted, which keeps committed specs diff-friendly.
+func EncodeYAML(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var normalized interface{}
+	if err := decoder.Decode(&normalized); err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(yamlNumbers(normalized)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// yamlNumbers replaces json.Number with int64 or float64, which yaml.v3
+// writes as plain numbers instead of quoted strings.
+func yamlNumbers(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			value[key] = yamlNumbers(item)
+		}
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = yamlNumbers(item)
+		}
+		return value
+	case json.Number:
+		if n, err := value.Int64(); err == nil {
+			return n
+		}
+		n, _ := value.Float64()
+		return n
+	default:
+		return value
+	}
+}
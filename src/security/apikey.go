@@ -4,10 +4,22 @@ import (
 	"github.com/syntropysoft/syntrogo/src/domain"
 )
 
+// APIKeyScheme is the OpenAPI security scheme enforced by APIKey.
+var APIKeyScheme = domain.SecurityScheme{
+	Name:        "apiKeyAuth",
+	Type:        "apiKey",
+	In:          "header",
+	ParamName:   "X-API-Key",
+	Description: "API key in the X-API-Key header",
+}
+
+// APIKeyKey holds the validated API key for handlers: key, _ := security.APIKeyKey.Get(ctx)
+var APIKeyKey = domain.NewKey[string]("security.api_key")
+
 // APIKey middleware validates API keys.
-// Usage: app.Use(APIKey("sk_live_..."))
-func APIKey(expectedKey string) domain.Middleware {
-	return func(next domain.HandlerFunc) domain.HandlerFunc {
+// Usage: app.UseSecured(APIKey("sk_live_..."))
+func APIKey(expectedKey string) domain.SecuredMiddleware {
+	return domain.WithSecurity(APIKeyScheme, func(next domain.HandlerFunc) domain.HandlerFunc {
 		return func(ctx *domain.Context) error {
 			// Extract API key from header
 			apiKey := ctx.Header("X-API-Key")
@@ -20,9 +32,12 @@ func APIKey(expectedKey string) domain.Middleware {
 				return domain.NewHTTPException(401, "Invalid API key")
 			}
 
+			// Make the key available to handlers
+			APIKeyKey.Set(ctx, apiKey)
+
 			// Continue to next handler
 			return next(ctx)
 		}
-	}
+	})
 }
 
@@ -5,10 +5,21 @@ import (
 	"github.com/syntropysoft/syntrogo/src/domain"
 )
 
+// BearerScheme is the OpenAPI security scheme enforced by BearerToken.
+var BearerScheme = domain.SecurityScheme{
+	Name:        "bearerAuth",
+	Type:        "http",
+	Scheme:      "bearer",
+	Description: "Bearer token in the Authorization header",
+}
+
+// BearerTokenKey holds the validated token for handlers: token, _ := security.BearerTokenKey.Get(ctx)
+var BearerTokenKey = domain.NewKey[string]("security.bearer_token")
+
 // BearerToken middleware validates Bearer tokens.
-// Usage: app.Use(BearerToken("secret123"))
-func BearerToken(expectedToken string) domain.Middleware {
-	return func(next domain.HandlerFunc) domain.HandlerFunc {
+// Usage: app.UseSecured(BearerToken("secret123")), or app.Group("/api").Secure(BearerToken("secret123"))
+func BearerToken(expectedToken string) domain.SecuredMiddleware {
+	return domain.WithSecurity(BearerScheme, func(next domain.HandlerFunc) domain.HandlerFunc {
 		return func(ctx *domain.Context) error {
 			// Extract Authorization header
 			auth := ctx.Header("Authorization")
@@ -32,12 +43,12 @@ func BearerToken(expectedToken string) domain.Middleware {
 				return domain.NewHTTPException(401, "Invalid token")
 			}
 
-			// Set user in context (can be extended)
-			ctx.Headers["X-Authenticated-User"] = "user"
+			// Make the token available to handlers (request headers can be spoofed)
+			BearerTokenKey.Set(ctx, token)
 
 			// Continue to next handler
 			return next(ctx)
 		}
-	}
+	})
 }
 
@@ -6,11 +6,13 @@
 // - CORS: Handles CORS headers
 // - RateLimit: Applies rate limiting
 //
+// Validated credentials are stored on the context: security.BearerTokenKey.Get(ctx)
+//
 // Usage:
 //   import "github.com/syntropysoft/syntrogo/src/security"
 //
-//   app.Use(security.BearerToken("token123"))
-//   app.Use(security.APIKey("key123"))
+//   app.UseSecured(security.BearerToken("token123"))
+//   app.UseSecured(security.APIKey("key123"))
 //   app.Use(security.CORS("*"))
 //   app.Use(security.RateLimit(limiter))
 package security
@@ -0,0 +1,184 @@
+package testing
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	gotesting "testing"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/syntropysoft/syntrogo/src/application"
+	"github.com/syntropysoft/syntrogo/src/domain"
+	"github.com/syntropysoft/syntrogo/src/infrastructure"
+)
+
+// Benchmarks compare SyntroGo against hand-written net/http handlers doing the
+// same work. Run: go test -bench . -benchmem ./src/testing
+
+type benchUser struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name" validate:"required,min=2"`
+	Email string `json:"email" validate:"required,email"`
+	Age   int    `json:"age" validate:"gte=0,lte=150"`
+}
+
+type benchSearch struct {
+	Query string   `query:"q" validate:"required"`
+	Page  int      `query:"page" default:"1"`
+	Tags  []string `query:"tag"`
+}
+
+var benchBody = []byte(`{"name":"Ada Lovelace","email":"ada@example.com","age":36}`)
+
+// newBenchAdapter registers the benchmark routes on a SyntroGo adapter.
+func newBenchAdapter() http.Handler {
+	routes := application.NewRouteRegistry()
+	_ = routes.Register("GET", "/users", func(c *domain.Context) error {
+		return c.JSON(200, benchUser{ID: 1, Name: "Ada Lovelace", Email: "ada@example.com", Age: 36})
+	}, domain.RouteOptions{})
+	_ = routes.Register("POST", "/users", func(c *domain.Context) error {
+		var user benchUser
+		if err := c.BindJSON(&user); err != nil {
+			return err
+		}
+		user.ID = 1
+		return c.JSON(201, user)
+	}, domain.RouteOptions{Body: benchUser{}})
+	_ = routes.Register("GET", "/users/:id", func(c *domain.Context) error {
+		id, _ := strconv.Atoi(c.Params["id"])
+		return c.JSON(200, benchUser{ID: id})
+	}, domain.RouteOptions{})
+	_ = routes.Register("GET", "/search", func(c *domain.Context) error {
+		var search benchSearch
+		if err := c.BindQuery(&search); err != nil {
+			return err
+		}
+		return c.JSON(200, search)
+	}, domain.RouteOptions{Query: benchSearch{}})
+
+	adapter := infrastructure.NewHTTPAdapter(routes, application.NewMiddlewareRegistry())
+	adapter.WarmUp()
+	return adapter
+}
+
+// newBenchBaseline serves the same routes with net/http, encoding/json and validator.
+func newBenchBaseline() http.Handler {
+	validate := validator.New()
+	writeJSON := func(w http.ResponseWriter, status int, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			writeJSON(w, 200, benchUser{ID: 1, Name: "Ada Lovelace", Email: "ada@example.com", Age: 36})
+			return
+		}
+		var user benchUser
+		if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+			http.Error(w, "invalid JSON", 400)
+			return
+		}
+		if err := validate.Struct(&user); err != nil {
+			http.Error(w, err.Error(), 422)
+			return
+		}
+		user.ID = 1
+		writeJSON(w, 201, user)
+	})
+	mux.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/users/"))
+		writeJSON(w, 200, benchUser{ID: id})
+	})
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		search := benchSearch{Query: query.Get("q"), Page: 1, Tags: query["tag"]}
+		if page := query.Get("page"); page != "" {
+			n, err := strconv.Atoi(page)
+			if err != nil {
+				http.Error(w, "invalid page", 400)
+				return
+			}
+			search.Page = n
+		}
+		if err := validate.Struct(&search); err != nil {
+			http.Error(w, err.Error(), 422)
+			return
+		}
+		writeJSON(w, 200, search)
+	})
+	return mux
+}
+
+// benchServe runs one request per iteration against SyntroGo and the baseline.
+func benchServe(b *gotesting.B, method, target string, body []byte, status int) {
+	handlers := []struct {
+		name    string
+		handler http.Handler
+	}{
+		{"syntrogo", newBenchAdapter()},
+		{"net_http", newBenchBaseline()},
+	}
+
+	for _, h := range handlers {
+		b.Run(h.name, func(b *gotesting.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				req := httptest.NewRequest(method, target, bytes.NewReader(body))
+				if body != nil {
+					req.Header.Set("Content-Type", "application/json")
+				}
+				rec := httptest.NewRecorder()
+				h.handler.ServeHTTP(rec, req)
+				if rec.Code != status {
+					b.Fatalf("status %d, want %d: %s", rec.Code, status, rec.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkJSONResponse(b *gotesting.B) {
+	benchServe(b, "GET", "/users", nil, 200)
+}
+
+func BenchmarkBindJSONValidate(b *gotesting.B) {
+	benchServe(b, "POST", "/users", benchBody, 201)
+}
+
+func BenchmarkPathParam(b *gotesting.B) {
+	benchServe(b, "GET", "/users/42", nil, 200)
+}
+
+func BenchmarkBindQuery(b *gotesting.B) {
+	benchServe(b, "GET", "/search?q=go&page=2&tag=a&tag=b", nil, 200)
+}
+
+// BenchmarkDecodeJSON measures the union-aware decoder against encoding/json
+// on a type without unions, where it must add no more than a cache lookup.
+func BenchmarkDecodeJSON(b *gotesting.B) {
+	b.Run("syntrogo", func(b *gotesting.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var user benchUser
+			if err := application.DecodeJSON(benchBody, &user); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("encoding_json", func(b *gotesting.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var user benchUser
+			if err := json.Unmarshal(benchBody, &user); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
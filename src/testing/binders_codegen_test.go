@@ -0,0 +1,220 @@
+package testing
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	gotesting "testing"
+
+	"github.com/syntropysoft/syntrogo/src/codegen"
+)
+
+// binderTypes is the package scanned by `syntrogo gen binders`.
+const binderTypes = `package binderpkg
+
+import "time"
+
+type Status string
+
+type Address struct {
+	Street string ` + "`json:\"street\" validate:\"required\"`" + `
+	City   string ` + "`json:\"city,omitempty\"`" + `
+}
+
+type Line struct {
+	SKU string ` + "`json:\"sku\"`" + `
+	N   int    ` + "`json:\"n\" validate:\"gte=0\"`" + `
+}
+
+type Order struct {
+	ID       int64          ` + "`json:\"id\" validate:\"min=1\"`" + `
+	Qty      uint8          ` + "`json:\"qty\"`" + `
+	Price    float64        ` + "`json:\"price\"`" + `
+	Ratio    float32        ` + "`json:\"ratio,omitempty\"`" + `
+	Paid     bool           ` + "`json:\"paid\"`" + `
+	Status   Status         ` + "`json:\"status\" validate:\"oneof=new paid\"`" + `
+	Note     *string        ` + "`json:\"note\"`" + `
+	Tags     []string       ` + "`json:\"tags\" validate:\"max=3\"`" + `
+	Lines    []Line         ` + "`json:\"lines\"`" + `
+	Meta     map[string]int ` + "`json:\"meta\"`" + `
+	Ship     *Address       ` + "`json:\"ship\"`" + `
+	Bill     Address        ` + "`json:\"bill\"`" + `
+	Data     []byte         ` + "`json:\"data\"`" + `
+	At       time.Time      ` + "`json:\"at\"`" + `
+	Internal string         ` + "`json:\"-\"`" + `
+	Plain    string
+}
+
+// Body marks Order as a request body for the generator.
+func Body(v interface{}) interface{} { return v }
+
+var _ = Body(Order{})
+`
+
+// binderEquivalenceTest compares the generated code with encoding/json and the validator.
+const binderEquivalenceTest = `package binderpkg
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/syntropysoft/syntrogo/src/application"
+)
+
+func orderCodec(t *testing.T) application.TypeCodec {
+	codec, ok := application.LookupTypeCodec(reflect.TypeOf(Order{}))
+	if !ok {
+		t.Fatal("Order has no generated codec")
+	}
+	return codec
+}
+
+// prefilled is the target before decoding; fields absent from the input keep these values.
+func prefilled() Order {
+	note := "keep"
+	return Order{ID: 9, Note: &note, Tags: []string{"t"}, Meta: map[string]int{"old": 1}, Ship: &Address{Street: "Old"}, Internal: "x"}
+}
+
+func TestDecodeMatchesEncodingJSON(t *testing.T) {
+	codec := orderCodec(t)
+	inputs := []string{
+		"{}",
+		"null",
+		" { } ",
+		` + "`" + `{"id":5,"qty":255,"price":-1.5e3,"ratio":0.25,"paid":true,"status":"new","note":"caf\u00e9 \"q\"\n","tags":["a","b"],"lines":[{"sku":"x","n":2},{"sku":"y"}],"meta":{"a":1,"b":2},"ship":{"street":"Main","city":"Oslo"},"bill":{"street":"Side"},"data":"aGk=","at":"2024-05-01T10:00:00+02:00","Internal":"no","Plain":"yes"}` + "`" + `,
+		` + "`" + `{"ID":7,"QTY":3,"Status":"paid","pLaIn":"mixed"}` + "`" + `,
+		` + "`" + `{"note":null,"tags":null,"meta":null,"ship":null,"data":null,"lines":[]}` + "`" + `,
+		` + "`" + `{"meta":{"new":2},"ship":{"city":"Rome"}}` + "`" + `,
+		` + "`" + `{"id":1,"id":2,"unknown":{"deep":[1,{"x":null}]},"more":[true,false]}` + "`" + `,
+		` + "`" + `{"id":null,"qty":null,"paid":null,"status":null,"bill":null}` + "`" + `,
+		` + "`" + `{"at":"2024-05-01"}` + "`" + `,
+		` + "`" + `{"id":"5"}` + "`" + `,
+		` + "`" + `{"qty":256}` + "`" + `,
+		` + "`" + `{"qty":-1}` + "`" + `,
+		` + "`" + `{"id":1.5}` + "`" + `,
+		` + "`" + `{"tags":"a"}` + "`" + `,
+		` + "`" + `{"data":"not base64!"}` + "`" + `,
+		` + "`" + `{"meta":{"a":"b"}}` + "`" + `,
+		` + "`" + `{"ship":{"street":1}}` + "`" + `,
+		` + "`" + `{"id":1,"note":"changed","meta":{"a":1},"ship":{"street":"New"},"paid":tru}` + "`" + `,
+		` + "`" + `{"id":1` + "`" + `,
+		` + "`" + `{"id":1}x` + "`" + `,
+		` + "`" + `[1]` + "`" + `,
+		"",
+	}
+
+	for _, input := range inputs {
+		want := prefilled()
+		wantErr := json.Unmarshal([]byte(input), &want)
+
+		original := prefilled()
+		got := original // Shares Note, Meta and Ship with original
+		gotErr := codec.Unmarshal([]byte(input), &got)
+
+		switch {
+		case (wantErr == nil) != (gotErr == nil):
+			t.Errorf("%s: encoding/json error %v, generated error %v", input, wantErr, gotErr)
+		case gotErr == nil && !reflect.DeepEqual(got, want):
+			t.Errorf("%s:\ngenerated     %+v\nencoding/json %+v", input, got, want)
+		case gotErr != nil && (!reflect.DeepEqual(got, prefilled()) || !reflect.DeepEqual(original, prefilled())):
+			t.Errorf("%s: failed decode changed the target: %+v", input, got)
+		}
+	}
+}
+
+func TestEncodeMatchesEncodingJSON(t *testing.T) {
+	codec := orderCodec(t)
+	note := "<b>&amp;</b> \u2028 \x00 caf\u00e9"
+	values := []Order{
+		{},
+		prefilled(),
+		{ID: -1, Qty: 255, Price: 1e21, Ratio: 0.1, Paid: true, Status: "paid", Note: &note, Tags: []string{},
+			Lines: []Line{{SKU: "a", N: 1}}, Meta: map[string]int{"z": 1, "a": 2, "é": 3}, Ship: &Address{Street: "s", City: "c"},
+			Data: []byte{0, 1, 2, 255}, Plain: "p"},
+		{Price: 0.000001, Ratio: 3.4e38, Meta: map[string]int{}, Data: []byte{}},
+	}
+
+	for _, value := range values {
+		want, err := json.Marshal(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range []interface{}{value, &value} {
+			got, err := codec.Marshal(v)
+			if err != nil || string(got) != string(want) {
+				t.Errorf("generated %s (%v)\nencoding/json %s", got, err, want)
+			}
+		}
+	}
+}
+
+func TestValidateMatchesValidator(t *testing.T) {
+	codec := orderCodec(t)
+	valid := Order{ID: 1, Status: "new", Bill: Address{Street: "b"}}
+	mutations := []func(o *Order){
+		func(o *Order) {},
+		func(o *Order) { o.ID = 0 },
+		func(o *Order) { o.Status = "lost" },
+		func(o *Order) { o.Tags = []string{"a", "b", "c", "d"} },
+		func(o *Order) { o.Bill.Street = "" },
+		func(o *Order) { o.Ship = &Address{} },
+		func(o *Order) { o.Ship = &Address{Street: "s"} },
+		func(o *Order) { o.ID, o.Status, o.Bill.Street = 0, "", "" },
+	}
+
+	validate := validator.New()
+	for i, mutate := range mutations {
+		order := valid
+		mutate(&order)
+		want := validate.Struct(order)
+		got := codec.Validate(&order)
+		if (want == nil) != (got == nil) || (got != nil && got.Error() != want.Error()) {
+			t.Errorf("case %d:\ngenerated %v\nvalidator %v", i, got, want)
+		}
+	}
+}
+`
+
+func TestGeneratedBindersMatchReflection(t *gotesting.T) {
+	dir, err := os.MkdirTemp(".", "binderpkg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	if err := os.WriteFile(filepath.Join(dir, "types.go"), []byte(binderTypes), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var source bytes.Buffer
+	if err := codegen.GenerateBinders(dir, &source); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"func syntrogoDecodeOrder(", "func syntrogoEncodeAddress(", "func syntrogoValidateOrder("} {
+		if !strings.Contains(source.String(), want) {
+			t.Fatalf("generated binders lack %q:\n%s", want, source.String())
+		}
+	}
+	if strings.Contains(source.String(), "uses reflection") || strings.Contains(source.String(), "validated with the validator") {
+		t.Fatalf("generated binders fell back to reflection:\n%s", source.String())
+	}
+
+	// Compile the package and compare it with encoding/json inside this module
+	if gotesting.Short() {
+		t.Skip("builds the generated package")
+	}
+	files := map[string]string{codegen.BindersFile: source.String(), "binders_test.go": binderEquivalenceTest}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out, err := exec.Command("go", "test", "./"+filepath.Base(dir)).CombinedOutput()
+	if err != nil {
+		t.Fatalf("generated binders: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,150 @@
+package testing
+
+import (
+	"strings"
+	gotesting "testing"
+
+	"github.com/syntropysoft/syntrogo/src/application"
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+	"github.com/syntropysoft/syntrogo/src/infrastructure"
+)
+
+// browserAccept is the Accept header browsers send for page navigations.
+const browserAccept = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+
+// textCodec is a registered third codec.
+type textCodec struct {
+	infrastructure.XMLCodec
+}
+
+func (textCodec) MediaType() string { return "text/csv" }
+
+func TestCodecNegotiate(t *gotesting.T) {
+	registry := application.NewCodecRegistry(append(infrastructure.DefaultCodecs(), textCodec{})...)
+	tests := []struct {
+		accept string
+		want   string // Empty means 406
+	}{
+		{"", "application/json"},
+		{"application/xml", "application/xml"},
+		{"APPLICATION/XML", "application/xml"},
+		{"text/csv", "text/csv"},
+		{"application/xml;q=0.5, application/json;q=0.9", "application/json"},
+		{"application/json;q=0.2, application/xml", "application/xml"},
+		{"application/json;q=0.5, application/xml;q=0.5", "application/json"}, // Ties follow registration order
+		{"application/*", "application/json"},
+		{"text/*, application/json;q=0.1", "text/csv"},
+		{"application/*;q=0.4, application/xml;q=0", "application/json"},
+		{"*/*", "application/json"},
+		{browserAccept, "application/json"},
+		{"application/xml, */*;q=0.1", "application/xml"}, // Explicit q-values win outside browsers
+		{"*/*;q=0.1, application/xml", "application/xml"},
+		{"text/html, application/xml, */*;q=0.1", "application/json"},
+		{"application/json;q=0, */*", "application/xml"}, // The default can still be excluded
+		{"application/xml, */*;q=0", "application/xml"},
+		{"text/html", ""},
+		{"image/*, text/html;q=0.9", ""},
+		{"application/json;q=0", ""},
+	}
+
+	for _, test := range tests {
+		codec, ok := registry.Negotiate(test.accept)
+		got := ""
+		if ok {
+			got = codec.MediaType()
+		}
+		if got != test.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", test.accept, got, test.want)
+		}
+	}
+}
+
+func TestCodecForContentType(t *gotesting.T) {
+	registry := application.NewCodecRegistry(infrastructure.DefaultCodecs()...)
+	tests := []struct {
+		contentType string
+		want        string // Empty means 415
+	}{
+		{"", "application/json"},
+		{"application/json; charset=utf-8", "application/json"},
+		{"application/problem+json", "application/json"},
+		{"application/xml", "application/xml"},
+		{"application/atom+xml", "application/xml"},
+		{"text/plain", ""},
+		{"application/x-www-form-urlencoded", ""},
+	}
+
+	for _, test := range tests {
+		codec, ok := registry.ForContentType(test.contentType)
+		got := ""
+		if ok {
+			got = codec.MediaType()
+		}
+		if got != test.want {
+			t.Errorf("ForContentType(%q) = %q, want %q", test.contentType, got, test.want)
+		}
+	}
+}
+
+type codecPet struct {
+	Name string `json:"name" xml:"name" validate:"required"`
+}
+
+func TestCodecHTTP(t *gotesting.T) {
+	app := core.New()
+	app.GET("/pet", func(c *domain.Context) error {
+		return c.Render(200, codecPet{Name: "Rex"})
+	})
+	app.GET("/stats", func(c *domain.Context) error {
+		return c.Render(200, map[string]interface{}{"pets": 3})
+	})
+	app.POST("/pets", func(c *domain.Context) error {
+		var pet codecPet
+		if err := c.BindJSON(&pet); err != nil {
+			return err
+		}
+		return c.Render(201, pet)
+	})
+	server := newAppServer(t, app)
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		body        string
+		header      map[string]string
+		status      int
+		contentType string
+		text        string
+	}{
+		{"browser gets JSON", "GET", "/pet", "", map[string]string{"Accept": browserAccept}, 200, "application/json", `{"name":"Rex"}`},
+		{"explicit XML", "GET", "/pet", "", map[string]string{"Accept": "application/xml"}, 200, "application/xml", "<codecPet><name>Rex</name></codecPet>"},
+		{"map falls back to JSON", "GET", "/stats", "", map[string]string{"Accept": "application/xml, application/json;q=0.5"}, 200, "application/json", `{"pets":3}`},
+		{"map without an acceptable fallback", "GET", "/stats", "", map[string]string{"Accept": "application/xml"}, 406, "", "Not Acceptable"},
+		{"map with */*", "GET", "/stats", "", map[string]string{"Accept": "application/xml, */*;q=0.1"}, 200, "application/json", `{"pets":3}`},
+		{"map for browsers", "GET", "/stats", "", map[string]string{"Accept": browserAccept}, 200, "application/json", `{"pets":3}`},
+		{"nothing acceptable", "GET", "/pet", "", map[string]string{"Accept": "text/html"}, 406, "", "Not Acceptable"},
+		{"XML body", "POST", "/pets", "<codecPet><name>Tom</name></codecPet>", map[string]string{"Content-Type": "application/xml", "Accept": "application/json"}, 201, "application/json", `{"name":"Tom"}`},
+		{"unsupported body", "POST", "/pets", "name=Tom", map[string]string{"Content-Type": "text/plain"}, 415, "", "unsupported media type"},
+		{"invalid XML", "POST", "/pets", "<codecPet>", map[string]string{"Content-Type": "application/xml"}, 400, "", "invalid application/xml body"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *gotesting.T) {
+			resp := send(t, test.method, server.URL+test.path, strings.NewReader(test.body), test.header)
+			if resp.StatusCode != test.status {
+				t.Fatalf("got %d %q, want %d", resp.StatusCode, resp.Text, test.status)
+			}
+			if test.contentType != "" && resp.Header.Get("Content-Type") != test.contentType {
+				t.Errorf("Content-Type %q, want %q", resp.Header.Get("Content-Type"), test.contentType)
+			}
+			if !strings.Contains(resp.Text, test.text) {
+				t.Errorf("body %q does not contain %q", resp.Text, test.text)
+			}
+			if test.status < 300 && resp.Header.Get("Vary") != "Accept" {
+				t.Errorf("Vary %q, want Accept", resp.Header.Get("Vary"))
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package testing
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	gotesting "testing"
+	"time"
+
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// onlyReader hides every method but Read, so the body cannot seek.
+type onlyReader struct{ io.Reader }
+
+func TestContentFile(t *gotesting.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(path, []byte("0123456789"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	app := core.New()
+	app.GET("/file", func(c *domain.Context) error { return c.File(path) })
+	app.GET("/missing", func(c *domain.Context) error { return c.File(filepath.Join(dir, "missing.txt")) })
+	app.GET("/dir", func(c *domain.Context) error { return c.File(dir) })
+	url := newAppServer(t, app).URL
+
+	resp := send(t, "GET", url+"/file", nil, nil)
+	lastModified := "Wed, 01 May 2024 10:00:00 GMT"
+	switch {
+	case resp.StatusCode != 200 || resp.Text != "0123456789":
+		t.Fatalf("file: %d %q", resp.StatusCode, resp.Text)
+	case resp.Header.Get("Content-Type") != "text/plain; charset=utf-8":
+		t.Errorf("Content-Type %q", resp.Header.Get("Content-Type"))
+	case resp.Header.Get("Content-Disposition") != "inline; filename=notes.txt":
+		t.Errorf("Content-Disposition %q", resp.Header.Get("Content-Disposition"))
+	case resp.Header.Get("Last-Modified") != lastModified || resp.Header.Get("Accept-Ranges") != "bytes":
+		t.Errorf("Last-Modified %q, Accept-Ranges %q", resp.Header.Get("Last-Modified"), resp.Header.Get("Accept-Ranges"))
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		header map[string]string
+		status int
+		body   string
+	}{
+		{"Range", "/file", map[string]string{"Range": "bytes=2-4"}, 206, "234"},
+		{"open Range", "/file", map[string]string{"Range": "bytes=7-"}, 206, "789"},
+		{"unsatisfiable Range", "/file", map[string]string{"Range": "bytes=20-30"}, 416, "invalid range: failed to overlap\n"},
+		{"If-Range current", "/file", map[string]string{"Range": "bytes=0-1", "If-Range": lastModified}, 206, "01"},
+		{"If-Range stale", "/file", map[string]string{"Range": "bytes=0-1", "If-Range": "Tue, 30 Apr 2024 10:00:00 GMT"}, 200, "0123456789"},
+		{"If-Modified-Since", "/file", map[string]string{"If-Modified-Since": lastModified}, 304, ""},
+		{"If-None-Match any", "/file", map[string]string{"If-None-Match": "*"}, 304, ""},
+		{"If-None-Match wins", "/file", map[string]string{"If-None-Match": `"v0"`, "If-Modified-Since": lastModified}, 200, "0123456789"},
+		{"If-Unmodified-Since", "/file", map[string]string{"If-Unmodified-Since": "Tue, 30 Apr 2024 10:00:00 GMT"}, 412, ""},
+		{"missing", "/missing", nil, 404, "{\"error\":\"Not Found\"}\n"},
+		{"directory", "/dir", nil, 404, "{\"error\":\"Not Found\"}\n"},
+	}
+	for _, test := range tests {
+		resp := send(t, "GET", url+test.path, nil, test.header)
+		if resp.StatusCode != test.status || resp.Text != test.body {
+			t.Errorf("%s: %d %q, want %d %q", test.name, resp.StatusCode, resp.Text, test.status, test.body)
+		}
+	}
+}
+
+func TestContentAttachment(t *gotesting.T) {
+	app := core.New()
+	app.GET("/seekable", func(c *domain.Context) error {
+		return c.Attachment(strings.NewReader("0123456789"), c.Query("name"))
+	})
+	app.GET("/stream", func(c *domain.Context) error {
+		return c.Attachment(onlyReader{strings.NewReader("%PDF-1.4 streamed")}, "report")
+	})
+	url := newAppServer(t, app).URL
+
+	tests := []struct {
+		name        string
+		path        string
+		header      map[string]string
+		status      int
+		body        string
+		contentType string
+	}{
+		{"by extension", "/seekable?name=data.csv", nil, 200, "0123456789", "text/csv; charset=utf-8"},
+		{"sniffed", "/seekable?name=data", nil, 200, "0123456789", "text/plain; charset=utf-8"},
+		{"resumed download", "/seekable?name=data.bin", map[string]string{"Range": "bytes=5-"}, 206, "56789", "application/octet-stream"},
+		{"multiple ranges", "/seekable?name=data.csv", map[string]string{"Range": "bytes=0-0,9-9"}, 206, "", ""},
+		{"stream sniffed whole", "/stream", nil, 200, "%PDF-1.4 streamed", "application/pdf"},
+		{"stream ignores Range", "/stream", map[string]string{"Range": "bytes=0-3"}, 200, "%PDF-1.4 streamed", "application/pdf"},
+	}
+	for _, test := range tests {
+		resp := send(t, "GET", url+test.path, nil, test.header)
+		if resp.StatusCode != test.status || test.body != "" && resp.Text != test.body ||
+			test.contentType != "" && resp.Header.Get("Content-Type") != test.contentType {
+			t.Errorf("%s: %d %q %q, want %d %q %q", test.name, resp.StatusCode, resp.Text, resp.Header.Get("Content-Type"), test.status, test.body, test.contentType)
+		}
+	}
+	if resp := send(t, "GET", url+"/seekable?name=a.csv", nil, map[string]string{"Range": "bytes=0-0,9-9"}); !strings.HasPrefix(resp.Header.Get("Content-Type"), "multipart/byteranges; boundary=") {
+		t.Errorf("multiple ranges Content-Type %q", resp.Header.Get("Content-Type"))
+	}
+}
+
+func TestContentDispositionEscaping(t *gotesting.T) {
+	app := core.New()
+	app.GET("/download", func(c *domain.Context) error {
+		return c.Attachment(strings.NewReader("data"), c.Query("name"))
+	})
+	url := newAppServer(t, app).URL
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"report.pdf", "attachment; filename=report.pdf"},
+		{"my report.pdf", `attachment; filename="my report.pdf"`},
+		{`say "hi"\now.txt`, `attachment; filename="say \"hi\"\\now.txt"`},
+		{"a;b=c.txt", `attachment; filename="a;b=c.txt"`},
+		{"résumé.pdf", "attachment; filename*=utf-8''r%C3%A9sum%C3%A9.pdf"},
+		{"evil.txt\r\nX-Injected: 1", "attachment; filename*=utf-8''evil.txt%0D%0AX-Injected%3A%201"},
+		{"", "attachment"},
+	}
+	for _, test := range tests {
+		req, err := http.NewRequest("GET", url+"/download", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.URL.RawQuery = "name=" + strings.NewReplacer("%", "%25", "&", "%26", "+", "%2B", "#", "%23", "\r", "%0D", "\n", "%0A", " ", "%20", `"`, "%22", `\`, "%5C", ";", "%3B", "=", "%3D").Replace(test.name)
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if got := resp.Header.Get("Content-Disposition"); got != test.want || resp.Header.Get("X-Injected") != "" {
+			t.Errorf("%q: Content-Disposition %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestContentBlob(t *gotesting.T) {
+	app := core.New()
+	app.GET("/png", func(c *domain.Context) error { return c.Blob(200, "", pngData) })
+	app.GET("/created", func(c *domain.Context) error { return c.Blob(201, "text/plain", []byte("0123456789")) })
+	app.GET("/versioned", func(c *domain.Context) error {
+		c.Response.(http.ResponseWriter).Header().Set("ETag", `"v1"`)
+		return c.Blob(200, "application/json", []byte(`{"version":1}`))
+	})
+	url := newAppServer(t, app).URL
+
+	tests := []struct {
+		name        string
+		path        string
+		header      map[string]string
+		status      int
+		body        []byte
+		contentType string
+	}{
+		{"sniffed", "/png", nil, 200, pngData, "image/png"},
+		{"Range", "/png", map[string]string{"Range": "bytes=1-3"}, 206, []byte("PNG"), "image/png"},
+		{"non-200 status kept", "/created", nil, 201, []byte("0123456789"), "text/plain"},
+		{"non-200 ignores Range", "/created", map[string]string{"Range": "bytes=0-1"}, 201, []byte("0123456789"), "text/plain"},
+		{"If-None-Match", "/versioned", map[string]string{"If-None-Match": `"v1"`}, 304, nil, ""},
+		{"If-None-Match weak", "/versioned", map[string]string{"If-None-Match": `W/"v1"`}, 304, nil, ""},
+		{"If-None-Match stale", "/versioned", map[string]string{"If-None-Match": `"v0"`}, 200, []byte(`{"version":1}`), "application/json"},
+		{"If-Range ETag", "/versioned", map[string]string{"Range": "bytes=1-9", "If-Range": `"v1"`}, 206, []byte(`"version"`), "application/json"},
+		{"If-Range stale ETag", "/versioned", map[string]string{"Range": "bytes=1-9", "If-Range": `"v0"`}, 200, []byte(`{"version":1}`), "application/json"},
+	}
+	for _, test := range tests {
+		resp := send(t, "GET", url+test.path, nil, test.header)
+		if resp.StatusCode != test.status || !bytes.Equal([]byte(resp.Text), test.body) ||
+			test.contentType != "" && resp.Header.Get("Content-Type") != test.contentType {
+			t.Errorf("%s: %d %q %q, want %d %q %q", test.name, resp.StatusCode, resp.Text, resp.Header.Get("Content-Type"), test.status, test.body, test.contentType)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package testing
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	gotesting "testing"
+	"time"
+
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// Dependency tests run injected handlers through the HTTP adapter, so cleanups
+// are checked against the moment the response is written.
+
+// testTx stands for a request-scoped resource such as a database transaction.
+type testTx struct {
+	mu     sync.Mutex
+	closed bool
+	done   chan struct{}
+}
+
+// testRows fails once the transaction is closed, like a cursor of a finished tx.
+type testRows struct {
+	tx *testTx
+}
+
+// MarshalJSON reads from the transaction while the response is encoded.
+func (r testRows) MarshalJSON() ([]byte, error) {
+	r.tx.mu.Lock()
+	defer r.tx.mu.Unlock()
+	if r.tx.closed {
+		return nil, errors.New("transaction closed")
+	}
+	return json.Marshal([]string{"ada", "grace"})
+}
+
+// close marks the transaction closed.
+func (tx *testTx) close() {
+	tx.mu.Lock()
+	tx.closed = true
+	tx.mu.Unlock()
+	close(tx.done)
+}
+
+// waitFor fails the test unless done is closed within a second.
+func waitFor(t *gotesting.T, done <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+}
+
+func TestDependencyCleanupAfterResponse(t *gotesting.T) {
+	app := core.New()
+	var tx *testTx
+	app.Provide(func() (*testTx, func()) {
+		tx = &testTx{done: make(chan struct{})}
+		return tx, tx.close
+	})
+	app.GET("/users", app.Inject(func(tx *testTx) (testRows, error) {
+		return testRows{tx: tx}, nil
+	}))
+	server := newAppServer(t, app)
+
+	resp := send(t, "GET", server.URL+"/users", nil, nil)
+	if resp.StatusCode != 200 || strings.TrimSpace(resp.Text) != `["ada","grace"]` {
+		t.Fatalf("got %d %q, want the rows encoded before the tx closes", resp.StatusCode, resp.Text)
+	}
+	waitFor(t, tx.done, "the request cleanup")
+}
+
+func TestDependencyCleanupAfterError(t *gotesting.T) {
+	app := core.New()
+	done := make(chan struct{})
+	app.Provide(func() (*testTx, func()) {
+		return &testTx{}, func() { close(done) }
+	})
+	app.GET("/fail", app.Inject(func(tx *testTx) error {
+		return domain.NewHTTPException(409, "conflict")
+	}))
+	server := newAppServer(t, app)
+
+	if resp := send(t, "GET", server.URL+"/fail", nil, nil); resp.StatusCode != 409 {
+		t.Fatalf("got %d, want 409", resp.StatusCode)
+	}
+	waitFor(t, done, "the request cleanup")
+}
+
+// Injected handlers answer with the route's declared status or the one they set.
+func TestInjectResponseStatus(t *gotesting.T) {
+	app := core.New()
+	app.Provide(func() *requestValue { return &requestValue{n: 1} })
+	created := func(v *requestValue) (map[string]int, error) { return map[string]int{"n": v.n}, nil }
+	app.POST("/created", app.Inject(created), domain.RouteOptions{Response: map[string]int{}, ResponseStatus: 201})
+	app.POST("/accepted", app.Inject(func(c *domain.Context, v *requestValue) (map[string]int, error) {
+		c.Status(202)
+		return map[string]int{"n": v.n}, nil
+	}), domain.RouteOptions{ResponseStatus: 201})
+	app.GET("/ok", app.Inject(created))
+	app.POST("/explicit", func(c *domain.Context) error { return c.JSON(200, "explicit") }, domain.RouteOptions{ResponseStatus: 201})
+	server := newAppServer(t, app)
+
+	tests := []struct {
+		method, path string
+		status       int
+		body         string
+	}{
+		{"POST", "/created", 201, `{"n":1}`},
+		{"POST", "/accepted", 202, `{"n":1}`},
+		{"GET", "/ok", 200, `{"n":1}`},
+		{"POST", "/explicit", 200, `"explicit"`},
+	}
+	for _, test := range tests {
+		resp := send(t, test.method, server.URL+test.path, nil, nil)
+		if resp.StatusCode != test.status || strings.TrimSpace(resp.Text) != test.body {
+			t.Errorf("%s: %d %s, want %d %s", test.path, resp.StatusCode, resp.Text, test.status, test.body)
+		}
+	}
+}
+
+type requestValue struct{ n int }
+type singletonValue struct{ n int }
+type transientValue struct{ n int }
+
+// scopeUsers takes every dependency twice, through a struct and directly.
+type scopeUsers struct {
+	Request   *requestValue
+	Singleton *singletonValue
+	Transient *transientValue
+}
+
+func TestDependencyScopes(t *gotesting.T) {
+	app := core.New()
+	var mu sync.Mutex
+	built := map[string]int{}
+	count := func(name string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		built[name]++
+		return built[name]
+	}
+	app.Provide(func() *requestValue { return &requestValue{count("request")} })
+	app.Provide(func() *singletonValue { return &singletonValue{count("singleton")} }, domain.ScopeSingleton)
+	app.Provide(func() *transientValue { return &transientValue{count("transient")} }, domain.ScopeTransient)
+
+	app.GET("/scopes", app.Inject(func(users scopeUsers, r *requestValue, s *singletonValue, tr *transientValue) (map[string]bool, error) {
+		return map[string]bool{
+			"request":   users.Request == r,
+			"singleton": users.Singleton == s,
+			"transient": users.Transient == tr,
+		}, nil
+	}))
+	if err := app.CheckDependencies(); err != nil {
+		t.Fatal(err)
+	}
+	server := newAppServer(t, app)
+
+	for i := 0; i < 2; i++ {
+		resp := send(t, "GET", server.URL+"/scopes", nil, nil)
+		if strings.TrimSpace(resp.Text) != `{"request":true,"singleton":true,"transient":false}` {
+			t.Fatalf("request %d: same instances %s", i, resp.Text)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := map[string]int{"request": 2, "singleton": 1, "transient": 4}
+	for name, n := range want {
+		if built[name] != n {
+			t.Errorf("%s built %d times, want %d", name, built[name], n)
+		}
+	}
+}
+
+type outerResource struct{}
+type innerResource struct{}
+type sharedResource struct{}
+
+func TestDependencyCleanupOrder(t *gotesting.T) {
+	app := core.New()
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func() {
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+		}
+	}
+	done := make(chan struct{})
+	app.Provide(func() (*sharedResource, func()) { return &sharedResource{}, record("shared") }, domain.ScopeSingleton)
+	app.Provide(func(*sharedResource) (*innerResource, func()) {
+		return &innerResource{}, func() { record("inner")(); close(done) }
+	})
+	app.Provide(func(*innerResource) (*outerResource, func()) { return &outerResource{}, record("outer") })
+	app.GET("/order", app.Inject(func(*outerResource) error { return nil }))
+	server := newAppServer(t, app)
+
+	send(t, "GET", server.URL+"/order", nil, nil)
+	waitFor(t, done, "the request cleanups")
+	app.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := strings.Join(order, ","); got != "outer,inner,shared" {
+		t.Fatalf("cleanup order %s, want outer,inner,shared", got)
+	}
+}
+
+type cycleA struct{}
+type cycleB struct{}
+type missingDep interface{ Missing() }
+
+func TestDependencyValidation(t *gotesting.T) {
+	tests := []struct {
+		name  string
+		setup func(app *core.App)
+		want  string
+	}{
+		{
+			name: "cycle",
+			setup: func(app *core.App) {
+				app.Provide(func(*cycleB) *cycleA { return &cycleA{} })
+				app.Provide(func(*cycleA) *cycleB { return &cycleB{} })
+			},
+			want: "dependency cycle: *testing.cycleA -> *testing.cycleB -> *testing.cycleA",
+		},
+		{
+			name: "missing provider",
+			setup: func(app *core.App) {
+				app.GET("/", app.Inject(func(missingDep) error { return nil }))
+			},
+			want: "no provider for testing.missingDep",
+		},
+		{
+			name: "singleton capturing the request",
+			setup: func(app *core.App) {
+				app.Provide(func(*domain.Context) *cycleA { return &cycleA{} }, domain.ScopeSingleton)
+			},
+			want: "singleton *testing.cycleA captures request-scoped *domain.Context",
+		},
+		{
+			name: "singleton capturing a request-scoped value",
+			setup: func(app *core.App) {
+				app.Provide(func() *cycleB { return &cycleB{} })
+				app.Provide(func(*cycleB) *cycleA { return &cycleA{} }, domain.ScopeSingleton)
+			},
+			want: "singleton *testing.cycleA captures request-scoped *testing.cycleB",
+		},
+		{
+			name: "bad provider",
+			setup: func(app *core.App) {
+				app.Provide(func() (*cycleA, string) { return nil, "" })
+			},
+			want: "must return T, (T, error), (T, func()) or (T, func(), error)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *gotesting.T) {
+			app := core.New()
+			test.setup(app)
+			err := app.CheckDependencies()
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Fatalf("got %v, want %q", err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package testing
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	gotesting "testing"
+	"time"
+
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// pngData is sniffed as image/png.
+var pngData = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 64)...)
+
+// formPart is one part of a multipart test body.
+type formPart struct {
+	name, filename, contentType string
+	content                     []byte
+}
+
+// multipartBody encodes parts and returns the body with its Content-Type.
+func multipartBody(t *gotesting.T, parts ...formPart) (io.Reader, map[string]string) {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, part := range parts {
+		header := textproto.MIMEHeader{}
+		disposition := fmt.Sprintf(`form-data; name="%s"`, part.name)
+		if part.filename != "" {
+			disposition += fmt.Sprintf(`; filename="%s"`, part.filename)
+		}
+		header.Set("Content-Disposition", disposition)
+		if part.contentType != "" {
+			header.Set("Content-Type", part.contentType)
+		}
+		w, err := writer.CreatePart(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(part.content)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &body, map[string]string{"Content-Type": writer.FormDataContentType()}
+}
+
+type profileForm struct {
+	Name    string             `form:"name" validate:"required"`
+	Age     int                `form:"age"`
+	Tags    []string           `form:"tag"`
+	Avatar  *domain.FormFile   `form:"avatar"`
+	Gallery []*domain.FormFile `form:"photo"`
+}
+
+// newProfileServer binds profileForm under the given upload limits. Bound
+// forms are sent to forms.
+func newProfileServer(t *gotesting.T, limits *domain.UploadLimits, forms chan<- profileForm) string {
+	app := core.New()
+	app.POST("/profile", func(c *domain.Context) error {
+		var form profileForm
+		if err := c.BindForm(&form); err != nil {
+			return err
+		}
+		if forms != nil {
+			forms <- form
+		}
+
+		files := []string{}
+		for _, file := range append([]*domain.FormFile{form.Avatar}, form.Gallery...) {
+			if file == nil {
+				continue
+			}
+			reader, err := file.Open()
+			if err != nil {
+				return err
+			}
+			data, _ := io.ReadAll(reader)
+			reader.Close()
+			files = append(files, fmt.Sprintf("%s:%s:%s:%d:%d", file.Field, file.Filename, file.ContentType, file.Size, len(data)))
+		}
+		return c.JSON(200, map[string]interface{}{"name": form.Name, "age": form.Age, "tags": form.Tags, "files": files})
+	}, domain.RouteOptions{Upload: limits})
+	return newAppServer(t, app).URL
+}
+
+func TestFormURLEncoded(t *gotesting.T) {
+	url := newProfileServer(t, &domain.UploadLimits{MaxFormSize: 64}, nil)
+	header := map[string]string{"Content-Type": "application/x-www-form-urlencoded"}
+
+	tests := []struct {
+		name   string
+		body   string
+		header map[string]string
+		status int
+		want   string
+	}{
+		{"fields", "name=Ada+L&age=36&tag=a&tag=b", header, 200, `{"age":36,"files":[],"name":"Ada L","tags":["a","b"]}`},
+		{"bad integer", "name=Ada&age=old", header, 400, `invalid form parameter \"age\": expected integer`},
+		{"validation", "age=36", header, 422, `'Name' failed on the 'required' tag`},
+		{"too large", "name=" + strings.Repeat("a", 64), header, 413, "form exceeds 64 bytes"},
+		{"not a form", `{"name":"Ada"}`, map[string]string{"Content-Type": "application/json"}, 415, "expected an urlencoded or multipart form"},
+	}
+	for _, test := range tests {
+		resp := send(t, "POST", url+"/profile", strings.NewReader(test.body), test.header)
+		if resp.StatusCode != test.status || !strings.Contains(resp.Text, test.want) {
+			t.Errorf("%s: %d %s\nwant %d %s", test.name, resp.StatusCode, resp.Text, test.status, test.want)
+		}
+	}
+}
+
+func TestFormMultipart(t *gotesting.T) {
+	forms := make(chan profileForm, 1)
+	url := newProfileServer(t, nil, forms)
+
+	body, header := multipartBody(t,
+		formPart{name: "name", content: []byte("Ada")},
+		formPart{name: "tag", content: []byte("x")},
+		formPart{name: "avatar", filename: "me.png", contentType: "application/octet-stream", content: pngData},
+		formPart{name: "photo", filename: "a.txt", content: []byte("hello")},
+		formPart{name: "photo", filename: "b.txt", content: []byte("world!")},
+	)
+	resp := send(t, "POST", url+"/profile", body, header)
+	want := fmt.Sprintf(`{"age":0,"files":["avatar:me.png:image/png:%d:%d","photo:a.txt:text/plain; charset=utf-8:5:5","photo:b.txt:text/plain; charset=utf-8:6:6"],"name":"Ada","tags":["x"]}`+"\n",
+		len(pngData), len(pngData))
+	if resp.StatusCode != 200 || resp.Text != want {
+		t.Fatalf("%d %s\nwant %s", resp.StatusCode, resp.Text, want)
+	}
+
+	// Spooled files are removed once the response is written
+	form := <-forms
+	deadline := time.Now().Add(time.Second)
+	for {
+		reader, err := form.Avatar.Open()
+		if err != nil {
+			break
+		}
+		reader.Close()
+		if time.Now().After(deadline) {
+			t.Fatal("uploaded file still on disk after the response")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestFormUploadLimits(t *gotesting.T) {
+	limits := &domain.UploadLimits{MaxFiles: 2, MaxFileSize: 1024, MaxFormSize: 32, AllowedTypes: []string{"image/*", "text/plain"}}
+	url := newProfileServer(t, limits, nil)
+	name := formPart{name: "name", content: []byte("Ada")}
+	photo := func(content []byte) formPart {
+		return formPart{name: "photo", filename: "p", content: content}
+	}
+
+	tests := []struct {
+		name   string
+		parts  []formPart
+		status int
+		want   string
+	}{
+		{"within limits", []formPart{name, photo(pngData), photo(bytes.Repeat([]byte("a"), 1024))}, 200, `"files":["photo:p:image/png`},
+		{"too many files", []formPart{name, photo(pngData), photo(pngData), photo(pngData)}, 413, "more than 2 files"},
+		{"file too large", []formPart{name, photo(bytes.Repeat([]byte("a"), 1025))}, 413, `file \"p\" exceeds 1024 bytes`},
+		{"fields too large", []formPart{name, {name: "tag", content: bytes.Repeat([]byte("t"), 30)}}, 413, "form fields exceed 32 bytes"},
+		{"type not allowed", []formPart{name, photo([]byte("%PDF-1.4\n"))}, 415, "file type application/pdf is not allowed"},
+		{"client type ignored", []formPart{name, {name: "photo", filename: "x.png", contentType: "image/png", content: []byte{0x1f, 0x8b, 8, 0}}}, 415,
+			"file type application/x-gzip is not allowed"},
+	}
+	for _, test := range tests {
+		body, header := multipartBody(t, test.parts...)
+		resp := send(t, "POST", url+"/profile", body, header)
+		if resp.StatusCode != test.status || !strings.Contains(resp.Text, test.want) {
+			t.Errorf("%s: %d %s\nwant %d %s", test.name, resp.StatusCode, resp.Text, test.status, test.want)
+		}
+	}
+
+	// A body cut short is a bad request
+	body, header := multipartBody(t, name, photo(pngData))
+	data, _ := io.ReadAll(body)
+	resp := send(t, "POST", url+"/profile", bytes.NewReader(data[:len(data)-10]), header)
+	if resp.StatusCode != 400 {
+		t.Errorf("truncated body: %d %s", resp.StatusCode, resp.Text)
+	}
+}
@@ -0,0 +1,133 @@
+package testing
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	gotesting "testing"
+
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// Types mirrored into the generated client.
+type (
+	ListQ struct {
+		Limit int      `query:"limit"`
+		Tags  []string `query:"tag"`
+	}
+	TraceHeaders struct {
+		RequestID string `header:"X-Request-ID"`
+	}
+	SessionCookies struct {
+		Token string `cookie:"session"`
+	}
+	Note struct {
+		Text string `json:"text"`
+	}
+	Echo struct {
+		Method string `json:"method"`
+		Path   string `json:"path"`
+		Query  string `json:"query,omitempty"`
+		Header string `json:"header,omitempty"`
+		Cookie string `json:"cookie,omitempty"`
+		Body   string `json:"body,omitempty"`
+	}
+)
+
+// apiClientTest calls the generated client against a server echoing each request.
+const apiClientTest = `package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			w.WriteHeader(404)
+			io.WriteString(w, ` + "`" + `{"error":"gone"}` + "`" + `)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		echo := Echo{Method: r.Method, Path: r.URL.EscapedPath(), Query: r.URL.RawQuery, Header: r.Header.Get("X-Request-ID"), Body: strings.TrimSpace(string(body))}
+		if cookie, err := r.Cookie("session"); err == nil {
+			echo.Cookie = cookie.Value
+		}
+		json.NewEncoder(w).Encode(echo)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL)
+	ctx := context.Background()
+
+	got, err := client.GetSearchByQuery(ctx, "a b/c", &ListQ{Limit: 2, Tags: []string{"x", "y"}})
+	if want := (Echo{Method: "GET", Path: "/search/a%20b%2Fc", Query: "limit=2&tag=x&tag=y"}); err != nil || *got != want {
+		t.Errorf("GetSearchByQuery: %+v, %v\nwant %+v", got, err, want)
+	}
+
+	got, err = client.PostFilesByURLBody(ctx, "u", "b", &TraceHeaders{RequestID: "r1"}, &SessionCookies{Token: "s1"}, &Note{Text: "hi"})
+	if want := (Echo{Method: "POST", Path: "/files/u/b", Header: "r1", Cookie: "s1", Body: ` + "`" + `{"text":"hi"}` + "`" + `}); err != nil || *got != want {
+		t.Errorf("PostFilesByURLBody: %+v, %v\nwant %+v", got, err, want)
+	}
+
+	var apiErr *APIError
+	err = client.DeleteCByCtxErrOut(ctx, "1", "2", "3")
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 404 || apiErr.Message != "gone" {
+		t.Errorf("DeleteCByCtxErrOut: %v", err)
+	}
+}
+`
+
+func TestGeneratedGoClient(t *gotesting.T) {
+	noop := func(c *domain.Context) error { return nil }
+	app := core.New()
+	app.GET("/search/:query", noop, domain.RouteOptions{Query: ListQ{}, Response: Echo{}})
+	app.POST("/files/:url/:body", noop, domain.RouteOptions{Body: Note{}, Headers: TraceHeaders{}, Cookies: SessionCookies{}, Response: Echo{}})
+	app.DELETE("/c/:ctx/:err/:out", noop)
+
+	var source bytes.Buffer
+	if err := app.GenerateClient("apiclient", &source); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"GetSearchByQuery(ctx context.Context, queryParam string, query *ListQ) (*Echo, error)",
+		`path: "/search/" + url.PathEscape(queryParam)`,
+		"PostFilesByURLBody(ctx context.Context, urlParam string, bodyParam string, headers *TraceHeaders, cookies *SessionCookies, body *Note) (*Echo, error)",
+		"DeleteCByCtxErrOut(ctx context.Context, ctxParam string, errParam string, outParam string) error",
+	} {
+		if !strings.Contains(source.String(), want) {
+			t.Errorf("generated client lacks %q", want)
+		}
+	}
+
+	// Vet the package and run its test inside this module
+	if gotesting.Short() {
+		t.Skip("builds the generated package")
+	}
+	dir, err := os.MkdirTemp(".", "apiclient")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	files := map[string]string{"apiclient.go": source.String(), "apiclient_test.go": apiClientTest}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, command := range []string{"vet", "test"} {
+		out, err := exec.Command("go", command, "./"+filepath.Base(dir)).CombinedOutput()
+		if err != nil {
+			t.Fatalf("go %s generated client: %v\n%s", command, err, out)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package testing
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	gotesting "testing"
+
+	"github.com/syntropysoft/syntrogo/src/application"
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+	"github.com/syntropysoft/syntrogo/src/infrastructure"
+)
+
+// newAppServer serves an app on a local listener with the adapter set up the way
+// Listen does it: the app's codecs and a background task pool.
+func newAppServer(t *gotesting.T, app *core.App, tasks ...domain.TaskConfig) *httptest.Server {
+	t.Helper()
+	config := domain.TaskConfig{}
+	if len(tasks) > 0 {
+		config = tasks[0]
+	}
+	adapter := infrastructure.NewHTTPAdapter(app.GetRouteRegistry(), app.GetMiddlewareRegistry())
+	adapter.SetCodecs(app.GetCodecRegistry())
+	adapter.SetTaskPool(application.NewTaskPool(config))
+	server := httptest.NewServer(adapter)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// testResponse is a response with its body read.
+type testResponse struct {
+	*http.Response
+	Text string
+}
+
+// send makes a request and reads the whole response. Headers are sent as given,
+// and responses are not decompressed.
+func send(t *gotesting.T, method, url string, body io.Reader, header map[string]string) *testResponse {
+	t.Helper()
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testResponse{Response: resp, Text: string(data)}
+}
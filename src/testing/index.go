@@ -3,6 +3,9 @@
 // Modules:
 // - TinyTest: Simple testing API that mirrors app API
 // - SmartMutator: Optimized mutation testing (8-30s)
+// - Benchmarks: SyntroGo against net/http baselines (go test -bench . ./src/testing)
+// - WebSocket tests: Handshake and framing against a raw local client
+// - Behavior tests: Features exercised through HTTPAdapter on a local server
 //
 // Philosophy:
 // - Write tests like you write endpoints
@@ -0,0 +1,173 @@
+package testing
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	gotesting "testing"
+
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+func TestStreamJSONLinesUsesJSONEngine(t *gotesting.T) {
+	app := core.New()
+	app.JSONEngine(markingEngine{})
+	app.GET("/emit", func(c *domain.Context) error {
+		return c.StreamJSONLines(func(emit func(interface{}) error) error {
+			for i := 1; i <= 2; i++ {
+				if err := emit(i); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	app.GET("/channel", func(c *domain.Context) error {
+		items := make(chan int, 2)
+		items <- 1
+		items <- 2
+		close(items)
+		return domain.StreamJSONLinesFrom(c, items)
+	})
+	server := newAppServer(t, app)
+
+	want := "{\"engine\":true,\"value\":1}\n{\"engine\":true,\"value\":2}\n"
+	for _, path := range []string{"/emit", "/channel"} {
+		resp := send(t, "GET", server.URL+path, nil, nil)
+		if resp.Text != want || !strings.HasPrefix(resp.Header.Get("Content-Type"), domain.NDJSONType) {
+			t.Errorf("%s: %s %q, want %q", path, resp.Header.Get("Content-Type"), resp.Text, want)
+		}
+	}
+}
+
+type importLine struct {
+	SKU string `json:"sku" validate:"required"`
+	Qty int    `json:"qty" validate:"gte=1"`
+}
+
+// importApp reports every line BindJSONStream read: "N:sku" for items,
+// "N:status" for invalid lines. ?limit= sets the line limit.
+func importApp() *core.App {
+	app := core.New()
+	app.POST("/import", func(c *domain.Context) error {
+		items := domain.BindJSONStream[importLine](c)
+		if limit := c.Query("limit"); limit != "" {
+			var n int
+			fmt.Sscan(limit, &n)
+			items.Limit(n)
+		}
+
+		report := []string{}
+		for items.Next() {
+			item, err := items.Item()
+			var lineErr *domain.LineError
+			var httpErr *domain.HTTPException
+			switch {
+			case err == nil:
+				report = append(report, fmt.Sprintf("%d:%s", items.Line(), item.SKU))
+			case errors.As(err, &lineErr) && lineErr.Line == items.Line() && errors.As(err, &httpErr):
+				report = append(report, fmt.Sprintf("%d:%d", lineErr.Line, httpErr.StatusCode))
+			default:
+				return fmt.Errorf("line %d: unexpected error %v", items.Line(), err)
+			}
+		}
+		if err := items.Err(); err != nil {
+			return err
+		}
+		return c.Blob(200, "text/plain", []byte(strings.Join(report, " ")))
+	})
+	return app
+}
+
+func TestBindJSONStream(t *gotesting.T) {
+	server := newAppServer(t, importApp())
+	long := `{"sku":"` + strings.Repeat("x", 64) + `","qty":1}`
+	tests := []struct {
+		name        string
+		query       string
+		contentType string
+		body        string
+		status      int
+		want        string
+	}{
+		{"items and invalid lines", "", domain.NDJSONType,
+			"{\"sku\":\"a\",\"qty\":1}\n{not json\n{\"sku\":\"\",\"qty\":1}\n{\"sku\":\"b\",\"qty\":0}\n{\"sku\":\"c\",\"qty\":2}\n",
+			200, "1:a 2:400 3:422 4:422 5:c"},
+		{"blank lines keep their numbers", "", domain.NDJSONType,
+			"\n  \n{\"sku\":\"a\",\"qty\":1}\r\n\r\n\t\n{\"sku\":\"b\",\"qty\":1}", 200, "3:a 6:b"},
+		{"empty body", "", domain.NDJSONType, "", 200, ""},
+		{"other JSON Lines type", "", "application/jsonl; charset=utf-8", `{"sku":"a","qty":1}`, 200, "1:a"},
+		{"no Content-Type", "", "", `{"sku":"a","qty":1}`, 200, "1:a"},
+		{"JSON body", "", "application/json", `{"sku":"a","qty":1}`, 415, `unsupported media type \"application/json\"`},
+		{"line over the limit", "?limit=32", domain.NDJSONType, "{\"sku\":\"a\",\"qty\":1}\n" + long + "\n", 413, "line 2 is longer than 32 bytes"},
+		{"line under the limit", "?limit=128", domain.NDJSONType, long, 200, "1:" + strings.Repeat("x", 64)},
+	}
+
+	for _, test := range tests {
+		header := map[string]string{}
+		if test.contentType != "" {
+			header["Content-Type"] = test.contentType
+		}
+		resp := send(t, "POST", server.URL+"/import"+test.query, strings.NewReader(test.body), header)
+		if resp.StatusCode != test.status || !strings.Contains(resp.Text, test.want) || (test.status == 200 && resp.Text != test.want) {
+			t.Errorf("%s: %d %q, want %d %q", test.name, resp.StatusCode, resp.Text, test.status, test.want)
+		}
+	}
+}
+
+func TestLineError(t *gotesting.T) {
+	err := &domain.LineError{Line: 3, Err: domain.NewHTTPException(422, "qty must be at least 1")}
+	if err.Error() != "line 3: qty must be at least 1" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	var httpErr *domain.HTTPException
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != 422 {
+		t.Errorf("errors.As: %v", httpErr)
+	}
+	if plain := (&domain.LineError{Line: 1, Err: errors.New("boom")}); plain.Error() != "line 1: boom" {
+		t.Errorf("Error() = %q", plain.Error())
+	}
+}
+
+// StreamJSONLinesFrom returns once the client goes away, even while items keep coming.
+func TestStreamJSONLinesFromStopsOnDisconnect(t *gotesting.T) {
+	app := core.New()
+	returned := make(chan struct{})
+	var streamErr error
+	app.GET("/feed", func(c *domain.Context) error {
+		defer close(returned)
+		items := make(chan int)
+		stop := make(chan struct{})
+		defer close(stop)
+		go func() {
+			for i := 0; ; i++ {
+				select {
+				case items <- i:
+				case <-stop:
+					return
+				}
+			}
+		}()
+		streamErr = domain.StreamJSONLinesFrom(c, items)
+		return streamErr
+	})
+	server := newAppServer(t, app)
+
+	resp, err := http.Get(server.URL + "/feed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil || line != "0\n" {
+		t.Fatalf("first line %q, %v", line, err)
+	}
+	resp.Body.Close()
+
+	waitFor(t, returned, "StreamJSONLinesFrom to return")
+	if streamErr == nil {
+		t.Error("StreamJSONLinesFrom returned nil after the client disconnected")
+	}
+}
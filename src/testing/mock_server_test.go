@@ -0,0 +1,181 @@
+package testing
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	gotesting "testing"
+	"time"
+
+	"github.com/syntropysoft/syntrogo/src/application"
+	"github.com/syntropysoft/syntrogo/src/domain"
+	"github.com/syntropysoft/syntrogo/src/infrastructure"
+)
+
+// catalogSpec has synthesized and example responses.
+const catalogSpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Catalog", "version": "1.0.0"},
+  "paths": {
+    "/products/{id}": {
+      "get": {
+        "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+        "responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Product"}}}}}
+      }
+    },
+    "/status": {
+      "get": {
+        "responses": {"200": {"description": "OK", "content": {"application/json": {"example": {"status": "up"}}}}}
+      }
+    }
+  },
+  "components": {"schemas": {
+    "Product": {"type": "object", "required": ["id", "name"], "properties": {
+      "id": {"type": "integer", "minimum": 1, "maximum": 99},
+      "name": {"type": "string", "minLength": 3, "maxLength": 20},
+      "contact": {"type": "string", "format": "email"},
+      "state": {"type": "string", "enum": ["draft", "live"]},
+      "tags": {"type": "array", "minItems": 2, "maxItems": 4, "items": {"type": "string"}}
+    }}
+  }}
+}`
+
+// newMockServer serves catalogSpec in mock mode, as `syntrogo mock` does.
+func newMockServer(t *gotesting.T, config domain.MockConfig) *httptest.Server {
+	t.Helper()
+	spec, err := infrastructure.DecodeSpec([]byte(catalogSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes := application.NewRouteRegistry()
+	if err := application.NewMockServer(spec, config).Register(routes); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(infrastructure.NewHTTPAdapter(routes, application.NewMiddlewareRegistry()))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMockDeterministicBodies(t *gotesting.T) {
+	first := newMockServer(t, domain.MockConfig{Seed: 7})
+	second := newMockServer(t, domain.MockConfig{Seed: 7})
+	reseeded := newMockServer(t, domain.MockConfig{Seed: 8})
+
+	body := send(t, "GET", first.URL+"/products/1?view=full", nil, nil).Text
+	if again := send(t, "GET", first.URL+"/products/1?view=full", nil, nil).Text; again != body {
+		t.Errorf("same URL: %s, then %s", body, again)
+	}
+	if other := send(t, "GET", second.URL+"/products/1?view=full", nil, nil).Text; other != body {
+		t.Errorf("same seed on another server: %s, want %s", other, body)
+	}
+	if other := send(t, "GET", first.URL+"/products/2?view=full", nil, nil).Text; other == body {
+		t.Errorf("another id gave the same body %s", body)
+	}
+	if other := send(t, "GET", first.URL+"/products/1?view=short", nil, nil).Text; other == body {
+		t.Errorf("another query gave the same body %s", body)
+	}
+	if other := send(t, "GET", reseeded.URL+"/products/1?view=full", nil, nil).Text; other == body {
+		t.Errorf("another seed gave the same body %s", body)
+	}
+}
+
+func TestMockBodiesFitSchema(t *gotesting.T) {
+	server := newMockServer(t, domain.MockConfig{Seed: 1})
+
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		resp := send(t, "GET", server.URL+"/products/"+id, nil, nil)
+		var product struct {
+			ID      int64    `json:"id"`
+			Name    string   `json:"name"`
+			Contact string   `json:"contact"`
+			State   string   `json:"state"`
+			Tags    []string `json:"tags"`
+		}
+		if err := json.Unmarshal([]byte(resp.Text), &product); err != nil || resp.StatusCode != 200 {
+			t.Fatalf("product %s: %d %s", id, resp.StatusCode, resp.Text)
+		}
+		switch {
+		case product.ID < 1 || product.ID > 99,
+			len(product.Name) < 3 || len(product.Name) > 20,
+			!strings.HasSuffix(product.Contact, "@example.com"),
+			product.State != "draft" && product.State != "live",
+			len(product.Tags) < 2 || len(product.Tags) > 4:
+			t.Errorf("product %s does not fit its schema: %s", id, resp.Text)
+		}
+	}
+
+	if resp := send(t, "GET", server.URL+"/status", nil, nil); resp.Text != "{\"status\":\"up\"}\n" {
+		t.Errorf("example response %q", resp.Text)
+	}
+}
+
+func TestMockStatusHeader(t *gotesting.T) {
+	server := newMockServer(t, domain.MockConfig{})
+
+	tests := []struct {
+		header string
+		status int
+	}{
+		{"404", 404},
+		{"503", 503},
+		{"200", 200},    // Only errors can be forced
+		{"teapot", 200}, // Not a status
+		{"", 200},
+	}
+	for _, test := range tests {
+		resp := send(t, "GET", server.URL+"/products/1", nil, map[string]string{application.MockStatusHeader: test.header})
+		if resp.StatusCode != test.status {
+			t.Errorf("%s %q: got %d, want %d", application.MockStatusHeader, test.header, resp.StatusCode, test.status)
+		}
+	}
+}
+
+func TestMockErrorInjection(t *gotesting.T) {
+	always := newMockServer(t, domain.MockConfig{ErrorRate: 1, ErrorStatus: 503})
+	for i := 0; i < 5; i++ {
+		if resp := send(t, "GET", always.URL+"/status", nil, nil); resp.StatusCode != 503 {
+			t.Fatalf("error rate 1: got %d", resp.StatusCode)
+		}
+	}
+	if resp := send(t, "GET", newMockServer(t, domain.MockConfig{ErrorRate: 1}).URL+"/status", nil, nil); resp.StatusCode != 500 {
+		t.Errorf("default error status %d, want 500", resp.StatusCode)
+	}
+
+	// The same seed fails the same requests
+	sequence := func() string {
+		server := newMockServer(t, domain.MockConfig{Seed: 3, ErrorRate: 0.5})
+		var statuses []string
+		for i := 0; i < 40; i++ {
+			statuses = append(statuses, send(t, "GET", server.URL+"/status", nil, nil).Status[:3])
+		}
+		return strings.Join(statuses, ",")
+	}
+	first := sequence()
+	failed := strings.Count(first, "500")
+	if failed < 5 || failed > 35 {
+		t.Errorf("error rate 0.5 failed %d of 40 requests", failed)
+	}
+	if second := sequence(); second != first {
+		t.Errorf("same seed failed other requests:\n%s\n%s", first, second)
+	}
+}
+
+func TestMockLatency(t *gotesting.T) {
+	const latency, jitter = 40 * time.Millisecond, 20 * time.Millisecond
+	server := newMockServer(t, domain.MockConfig{Latency: latency, Jitter: jitter})
+
+	for i := 0; i < 3; i++ {
+		start := time.Now()
+		send(t, "GET", server.URL+"/status", nil, nil)
+		if elapsed := time.Since(start); elapsed < latency {
+			t.Errorf("response after %v, want at least %v", elapsed, latency)
+		}
+	}
+
+	// Forced errors are delayed too, like a slow failing backend
+	start := time.Now()
+	send(t, "GET", server.URL+"/status", nil, map[string]string{application.MockStatusHeader: "502"})
+	if elapsed := time.Since(start); elapsed < latency {
+		t.Errorf("error after %v, want at least %v", elapsed, latency)
+	}
+}
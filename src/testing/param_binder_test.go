@@ -0,0 +1,161 @@
+package testing
+
+import (
+	"net"
+	"strings"
+	gotesting "testing"
+	"time"
+
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+type searchQuery struct {
+	Q       string        `query:"q" validate:"required"`
+	Page    int           `query:"page" default:"1"`
+	Limit   *uint8        `query:"limit"`
+	Ratio   float64       `query:"ratio"`
+	Exact   bool          `query:"exact"`
+	Tags    []string      `query:"tag"`
+	IDs     []int         `query:"id" default:"[1,2]"`
+	Since   time.Time     `query:"since"`
+	Timeout time.Duration `query:"timeout"`
+	Addr    net.IP        `query:"addr"`
+}
+
+type searchHeaders struct {
+	RequestID string   `header:"X-Request-ID"`
+	Retries   int      `header:"X-Retries"`
+	Languages []string `header:"Accept-Language"`
+}
+
+type searchCookies struct {
+	Session string `cookie:"session"`
+	Visits  *int   `cookie:"visits"`
+}
+
+// newSearchServer echoes the bound query, headers and cookies.
+func newSearchServer(t *gotesting.T) string {
+	app := core.New()
+	app.GET("/search", func(c *domain.Context) error {
+		var params struct {
+			Query   searchQuery
+			Headers searchHeaders
+			Cookies searchCookies
+		}
+		if err := c.BindQuery(&params.Query); err != nil {
+			return err
+		}
+		if err := c.BindHeader(&params.Headers); err != nil {
+			return err
+		}
+		if err := c.BindCookie(&params.Cookies); err != nil {
+			return err
+		}
+		return c.JSON(200, params)
+	})
+	return newAppServer(t, app).URL
+}
+
+func TestBindParamsConversion(t *gotesting.T) {
+	url := newSearchServer(t)
+
+	tests := []struct {
+		name   string
+		query  string
+		header map[string]string
+		want   string
+	}{
+		{"defaults", "?q=go", nil,
+			`{"Query":{"Q":"go","Page":1,"Limit":null,"Ratio":0,"Exact":false,"Tags":null,"IDs":[1,2],"Since":"0001-01-01T00:00:00Z","Timeout":0,"Addr":""},` +
+				`"Headers":{"RequestID":"","Retries":0,"Languages":null},"Cookies":{"Session":"","Visits":null}}`},
+		{"every type",
+			"?q=go+lang&page=3&limit=0&ratio=0.25&exact=true&tag=a&tag=b&id=7&since=2024-05-01&timeout=1m30s&addr=10.0.0.1",
+			map[string]string{"X-Request-ID": "req-1", "X-Retries": "2", "Accept-Language": "es", "Cookie": "session=abc; visits=4"},
+			`{"Query":{"Q":"go lang","Page":3,"Limit":0,"Ratio":0.25,"Exact":true,"Tags":["a","b"],"IDs":[7],"Since":"2024-05-01T00:00:00Z","Timeout":90000000000,"Addr":"10.0.0.1"},` +
+				`"Headers":{"RequestID":"req-1","Retries":2,"Languages":["es"]},"Cookies":{"Session":"abc","Visits":4}}`},
+		{"date-time", "?q=go&since=2024-05-01T10:30:00%2B02:00", nil,
+			`"Since":"2024-05-01T10:30:00+02:00"`},
+	}
+	for _, test := range tests {
+		resp := send(t, "GET", url+"/search"+test.query, nil, test.header)
+		if resp.StatusCode != 200 || !strings.Contains(resp.Text, test.want) {
+			t.Errorf("%s: %d %s\nwant %s", test.name, resp.StatusCode, resp.Text, test.want)
+		}
+	}
+}
+
+func TestBindParamsBadInput(t *gotesting.T) {
+	url := newSearchServer(t)
+
+	tests := []struct {
+		name   string
+		query  string
+		header map[string]string
+		status int
+		want   string
+	}{
+		{"integer", "?q=go&page=abc", nil, 400, `invalid query parameter \"page\": expected integer`},
+		{"overflow", "?q=go&limit=300", nil, 400, `invalid query parameter \"limit\": expected unsigned integer`},
+		{"negative unsigned", "?q=go&limit=-1", nil, 400, `invalid query parameter \"limit\": expected unsigned integer`},
+		{"number", "?q=go&ratio=half", nil, 400, `invalid query parameter \"ratio\": expected number`},
+		{"boolean", "?q=go&exact=maybe", nil, 400, `invalid query parameter \"exact\": expected boolean`},
+		{"slice item", "?q=go&id=1&id=two", nil, 400, `invalid query parameter \"id\": expected integer`},
+		{"date", "?q=go&since=yesterday", nil, 400, `invalid query parameter \"since\": expected RFC 3339 date-time or date`},
+		{"duration", "?q=go&timeout=soon", nil, 400, `invalid query parameter \"timeout\": expected duration such as 1m30s`},
+		{"text unmarshaler", "?q=go&addr=10.0.0", nil, 400, `invalid query parameter \"addr\"`},
+		{"header", "?q=go", map[string]string{"X-Retries": "many"}, 400, `invalid header parameter \"X-Retries\": expected integer`},
+		{"cookie", "?q=go", map[string]string{"Cookie": "visits=x"}, 400, `invalid cookie parameter \"visits\": expected integer`},
+		{"validation", "?page=2", nil, 422, `'Q' failed on the 'required' tag`},
+	}
+	for _, test := range tests {
+		resp := send(t, "GET", url+"/search"+test.query, nil, test.header)
+		if resp.StatusCode != test.status || !strings.Contains(resp.Text, test.want) {
+			t.Errorf("%s: %d %s\nwant %d %s", test.name, resp.StatusCode, resp.Text, test.status, test.want)
+		}
+	}
+}
+
+// Bind applies the body first; path, query, header and cookie values win over it.
+func TestBindAllSources(t *gotesting.T) {
+	type updateOrder struct {
+		ID      int64  `path:"id"`
+		Note    string `json:"note" query:"note"`
+		Qty     int    `json:"qty" validate:"min=1"`
+		Tenant  string `header:"X-Tenant" validate:"required"`
+		Session string `cookie:"session"`
+	}
+	app := core.New()
+	app.PUT("/orders/:id", func(c *domain.Context) error {
+		var order updateOrder
+		if err := c.Bind(&order); err != nil {
+			return err
+		}
+		return c.JSON(200, order)
+	})
+	url := newAppServer(t, app).URL
+
+	header := map[string]string{"Content-Type": "application/json", "X-Tenant": "acme", "Cookie": "session=s1"}
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		header map[string]string
+		status int
+		want   string
+	}{
+		{"every source", "/orders/9?note=from+query", `{"note":"from body","qty":2}`, header, 200,
+			`{"ID":9,"note":"from query","qty":2,"Tenant":"acme","Session":"s1"}`},
+		{"body only", "/orders/9", `{"note":"from body","qty":2}`, header, 200,
+			`{"ID":9,"note":"from body","qty":2,"Tenant":"acme","Session":"s1"}`},
+		{"bad path", "/orders/nine", `{"qty":2}`, header, 400, `invalid path parameter \"id\": expected integer`},
+		{"validated once", "/orders/9", `{"qty":0}`, header, 422, `'Qty' failed on the 'min' tag`},
+		{"missing header", "/orders/9", `{"qty":1}`, map[string]string{"Content-Type": "application/json"}, 422, `'Tenant' failed on the 'required' tag`},
+	}
+	for _, test := range tests {
+		resp := send(t, "PUT", url+test.path, strings.NewReader(test.body), test.header)
+		if resp.StatusCode != test.status || !strings.Contains(resp.Text, test.want) {
+			t.Errorf("%s: %d %s\nwant %d %s", test.name, resp.StatusCode, resp.Text, test.status, test.want)
+		}
+	}
+}
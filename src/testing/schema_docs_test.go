@@ -0,0 +1,206 @@
+package testing
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	gotesting "testing"
+
+	"github.com/syntropysoft/syntrogo/src/application"
+	"github.com/syntropysoft/syntrogo/src/codegen"
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+type annotatedModel struct {
+	Name     string   `json:"name" doc:"Display name" example:"Ada"`
+	Age      int      `json:"age" example:"36" default:"18"`
+	Ratio    float64  `json:"ratio" default:"0.5"`
+	Active   bool     `json:"active" default:"true"`
+	Tags     []string `json:"tags" example:"[\"a\",\"b\"]"`
+	Born     string   `json:"born" format:"date"`
+	Note     *string  `json:"note"`
+	Nick     string   `json:"nick" openapi:"nullable"`
+	ID       int64    `json:"id" openapi:"readOnly"`
+	Password string   `json:"password" openapi:"writeonly"`
+}
+
+func TestSchemaFieldTags(t *gotesting.T) {
+	common := map[string]string{
+		"name":     `{"description":"Display name","example":"Ada","type":"string"}`,
+		"age":      `{"default":18,"example":36,"type":"integer"}`,
+		"ratio":    `{"default":0.5,"format":"double","type":"number"}`,
+		"active":   `{"default":true,"type":"boolean"}`,
+		"tags":     `{"example":["a","b"],"items":{"type":"string"},"type":"array"}`,
+		"born":     `{"format":"date","type":"string"}`,
+		"id":       `{"format":"int64","readOnly":true,"type":"integer"}`,
+		"password": `{"type":"string","writeOnly":true}`,
+	}
+	tests := []struct {
+		version string
+		want    map[string]string
+	}{
+		{domain.OpenAPI30, map[string]string{
+			"note": `{"nullable":true,"type":"string"}`,
+			"nick": `{"nullable":true,"type":"string"}`,
+		}},
+		{domain.OpenAPI31, map[string]string{
+			"note": `{"type":["string","null"]}`,
+			"nick": `{"type":["string","null"]}`,
+		}},
+	}
+
+	for _, test := range tests {
+		builder := application.NewSchemaBuilder()
+		builder.SetOpenAPIVersion(test.version)
+		properties := builder.Build(reflect.TypeOf(annotatedModel{}))["properties"].(map[string]interface{})
+		for name, want := range common {
+			test.want[name] = want
+		}
+		for name, want := range test.want {
+			if got := compactJSON(t, properties[name]); got != want {
+				t.Errorf("%s %s: %s, want %s", test.version, name, got, want)
+			}
+		}
+	}
+}
+
+// docsPackage is the package scanned by `syntrogo gen docs`.
+const docsPackage = `package docspkg
+
+// Customer is a buyer of the shop.
+type Customer struct {
+	// Name is printed on invoices.
+	Name  string ` + "`json:\"name\"`" + `
+	Email string ` + "`json:\"email\"`" + ` // Contact address
+	Nick  string ` + "`json:\"nick\" doc:\"Tag wins\"`" + ` // Comment loses
+	Plain string ` + "`json:\"plain\"`" + `
+}
+
+// Grouped declarations take the comment on their own spec.
+type (
+	// Order groups the items of one purchase.
+	Order struct {
+		ID int ` + "`json:\"id\"`" + `
+	}
+	Undocumented struct {
+		X int
+	}
+)
+
+// Box is generic and cannot be registered.
+type Box[T any] struct {
+	Value T
+}
+`
+
+// docsPackageTest is ignored by the scanner and checks the registered docs reach the schema.
+const docsPackageTest = `package docspkg
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/syntropysoft/syntrogo/src/application"
+)
+
+// Hidden is documented but declared in a test file.
+type Hidden struct{}
+
+func TestDocs(t *testing.T) {
+	schema := application.NewSchemaBuilder().Build(reflect.TypeOf(Customer{}))
+	data, _ := json.Marshal(schema)
+	want := ` + "`" + `{"description":"Customer is a buyer of the shop.","properties":{"email":{"description":"Contact address","type":"string"},` +
+	`"name":{"description":"Name is printed on invoices.","type":"string"},"nick":{"description":"Tag wins","type":"string"},"plain":{"type":"string"}},` +
+	`"title":"Customer","type":"object"}` + "`" + `
+	if string(data) != want {
+		t.Errorf("schema:\n%s\nwant\n%s", data, want)
+	}
+	if doc := application.TypeDoc(reflect.TypeOf(Hidden{})); doc != "" {
+		t.Errorf("test file type registered: %q", doc)
+	}
+}
+`
+
+// wantDocs is the file generated for docsPackage.
+const wantDocs = `// Code generated by syntrogo gen docs. DO NOT EDIT.
+
+package docspkg
+
+import (
+	"reflect"
+
+	"github.com/syntropysoft/syntrogo/src/application"
+)
+
+func init() {
+	application.RegisterTypeDocs(reflect.TypeOf((*Customer)(nil)).Elem(), "Customer is a buyer of the shop.", map[string]string{
+		"Email": "Contact address",
+		"Name":  "Name is printed on invoices.",
+		"Nick":  "Comment loses",
+	})
+	application.RegisterTypeDocs(reflect.TypeOf((*Order)(nil)).Elem(), "Order groups the items of one purchase.", map[string]string{})
+}
+`
+
+func TestGenerateDocs(t *gotesting.T) {
+	dir, err := os.MkdirTemp(".", "docspkg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	files := map[string]string{"types.go": docsPackage, "types_test.go": docsPackageTest}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var source bytes.Buffer
+	if err := codegen.GenerateDocs(dir, &source); err != nil {
+		t.Fatal(err)
+	}
+	if source.String() != wantDocs {
+		t.Fatalf("GenerateDocs:\n%s\nwant\n%s", source.String(), wantDocs)
+	}
+
+	// Undocumented packages still get a file that compiles
+	empty := t.TempDir()
+	if err := os.WriteFile(filepath.Join(empty, "a.go"), []byte("package empty\n\ntype A struct{}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var emptySource bytes.Buffer
+	if err := codegen.GenerateDocs(empty, &emptySource); err != nil || emptySource.String() != "// Code generated by syntrogo gen docs. DO NOT EDIT.\n\npackage empty\n\n" {
+		t.Errorf("undocumented package: %q, %v", emptySource.String(), err)
+	}
+	if err := codegen.GenerateDocs(t.TempDir(), &emptySource); err == nil {
+		t.Error("directory without Go files: want an error")
+	}
+
+	// The command writes the same file; a second run ignores its own output
+	if gotesting.Short() {
+		t.Skip("builds the syntrogo command and the generated package")
+	}
+	command := filepath.Join(t.TempDir(), "syntrogo")
+	if out, err := exec.Command("go", "build", "-o", command, "github.com/syntropysoft/syntrogo/cmd/syntrogo").CombinedOutput(); err != nil {
+		t.Fatalf("build: %v\n%s", err, out)
+	}
+	for run := 1; run <= 2; run++ {
+		if out, err := exec.Command(command, "gen", "docs", "-dir", dir).CombinedOutput(); err != nil {
+			t.Fatalf("gen docs run %d: %v\n%s", run, err, out)
+		}
+		got, err := os.ReadFile(filepath.Join(dir, codegen.DocsFile))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != wantDocs {
+			t.Fatalf("gen docs run %d:\n%s", run, got)
+		}
+	}
+
+	if out, err := exec.Command("go", "test", "./"+filepath.Base(dir)).CombinedOutput(); err != nil {
+		t.Fatalf("go test generated docs: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,152 @@
+package testing
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	gotesting "testing"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/syntropysoft/syntrogo/src/application"
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+type boundsModel struct {
+	Age   int      `json:"age" validate:"gt=17,lt=130"`
+	Score float64  `json:"score" validate:"gte=0,lt=1.5"`
+	Name  string   `json:"name" validate:"gt=2,lt=10"`
+	Tags  []string `json:"tags" validate:"gt=0,lt=5"`
+	Code  string   `json:"code" validate:"len=4"`
+	Nick  string   `json:"nick" validate:"min=1,max=3"`
+}
+
+// propertySchemas builds a struct schema and returns its properties as JSON.
+func propertySchemas(t *gotesting.T, version string, v interface{}) map[string]string {
+	t.Helper()
+	builder := application.NewSchemaBuilder()
+	builder.SetOpenAPIVersion(version)
+	schema := builder.Build(reflect.TypeOf(v))
+
+	properties := map[string]string{}
+	for name, property := range schema["properties"].(map[string]interface{}) {
+		object := property.(map[string]interface{})
+		delete(object, "type")
+		data, err := json.Marshal(object)
+		if err != nil {
+			t.Fatal(err)
+		}
+		properties[name] = string(data)
+	}
+	return properties
+}
+
+func TestSchemaBounds(t *gotesting.T) {
+	tests := []struct {
+		version string
+		want    map[string]string
+	}{
+		{domain.OpenAPI30, map[string]string{
+			"age":   `{"exclusiveMaximum":true,"exclusiveMinimum":true,"maximum":130,"minimum":17}`,
+			"score": `{"exclusiveMaximum":true,"format":"double","maximum":1.5,"minimum":0}`,
+			"name":  `{"maxLength":9,"minLength":3}`,
+			"tags":  `{"items":{"type":"string"},"maxItems":4,"minItems":1}`,
+			"code":  `{"maxLength":4,"minLength":4}`,
+			"nick":  `{"maxLength":3,"minLength":1}`,
+		}},
+		{domain.OpenAPI31, map[string]string{
+			"age":   `{"exclusiveMaximum":130,"exclusiveMinimum":17}`,
+			"score": `{"exclusiveMaximum":1.5,"format":"double","minimum":0}`,
+			"name":  `{"maxLength":9,"minLength":3}`,
+			"tags":  `{"items":{"type":"string"},"maxItems":4,"minItems":1}`,
+			"code":  `{"maxLength":4,"minLength":4}`,
+			"nick":  `{"maxLength":3,"minLength":1}`,
+		}},
+	}
+
+	for _, test := range tests {
+		got := propertySchemas(t, test.version, boundsModel{})
+		for name, want := range test.want {
+			if got[name] != want {
+				t.Errorf("%s %s: %s, want %s", test.version, name, got[name], want)
+			}
+		}
+	}
+}
+
+// The documented bounds must be the ones the validator enforces.
+func TestSchemaBoundsMatchValidator(t *gotesting.T) {
+	valid := boundsModel{Age: 18, Score: 0, Name: "abc", Tags: []string{"a"}, Code: "abcd", Nick: "a"}
+	tests := []struct {
+		name   string
+		mutate func(m *boundsModel)
+		field  string // Empty when still valid
+	}{
+		{"schema minimums", func(m *boundsModel) {}, ""},
+		{"schema maximums", func(m *boundsModel) {
+			m.Age, m.Score, m.Name, m.Tags, m.Nick = 129, 1.49, "abcdefghi", []string{"a", "b", "c", "d"}, "abc"
+		}, ""},
+		{"age at exclusive minimum", func(m *boundsModel) { m.Age = 17 }, "Age"},
+		{"age at exclusive maximum", func(m *boundsModel) { m.Age = 130 }, "Age"},
+		{"name below minLength", func(m *boundsModel) { m.Name = "ab" }, "Name"},
+		{"name above maxLength", func(m *boundsModel) { m.Name = strings.Repeat("a", 10) }, "Name"},
+		{"tags below minItems", func(m *boundsModel) { m.Tags = nil }, "Tags"},
+		{"tags above maxItems", func(m *boundsModel) { m.Tags = make([]string, 5) }, "Tags"},
+	}
+
+	validate := validator.New()
+	for _, test := range tests {
+		model := valid
+		test.mutate(&model)
+		err := validate.Struct(model)
+		switch {
+		case test.field == "" && err != nil:
+			t.Errorf("%s: %v", test.name, err)
+		case test.field != "" && (err == nil || !strings.Contains(err.Error(), "'"+test.field+"'")):
+			t.Errorf("%s: got %v, want a %s error", test.name, err, test.field)
+		}
+	}
+}
+
+type diveModel struct {
+	Tags   []string          `json:"tags" validate:"max=3,dive,min=2,max=8"`
+	Scores []int             `json:"scores" validate:"dive,gte=0,lte=100"`
+	Emails []string          `json:"emails" validate:"omitempty,dive,email"`
+	Grid   [][]int           `json:"grid" validate:"dive,max=2,dive,lt=10"`
+	Labels map[string]string `json:"labels" validate:"min=1,dive,keys,min=3,endkeys,oneof=a b"`
+	Lines  []boundsModel     `json:"lines" validate:"dive"`
+}
+
+// Rules after dive describe the elements, not the collection.
+func TestSchemaDive(t *gotesting.T) {
+	want := map[string]string{
+		"tags":   `{"items":{"maxLength":8,"minLength":2,"type":"string"},"maxItems":3}`,
+		"scores": `{"items":{"maximum":100,"minimum":0,"type":"integer"}}`,
+		"emails": `{"items":{"format":"email","type":"string"}}`,
+		"grid":   `{"items":{"items":{"exclusiveMaximum":10,"type":"integer"},"maxItems":2,"type":"array"}}`,
+		"labels": `{"additionalProperties":{"enum":["a","b"],"type":"string"},"minProperties":1}`,
+	}
+	got := propertySchemas(t, domain.OpenAPI31, diveModel{})
+	for name, want := range want {
+		if got[name] != want {
+			t.Errorf("%s: %s, want %s", name, got[name], want)
+		}
+	}
+
+	// The element rules are the ones the validator enforces
+	valid := diveModel{Tags: []string{"ab"}, Scores: []int{0, 100}, Grid: [][]int{{9, 9}}, Labels: map[string]string{"key": "a"}}
+	if err := validator.New().Struct(valid); err != nil {
+		t.Fatalf("valid model: %v", err)
+	}
+	for _, invalid := range []diveModel{
+		{Tags: []string{"a"}, Labels: valid.Labels},
+		{Scores: []int{101}, Labels: valid.Labels},
+		{Grid: [][]int{{10}}, Labels: valid.Labels},
+		{Grid: [][]int{{1, 2, 3}}, Labels: valid.Labels},
+		{Labels: map[string]string{"key": "c"}},
+	} {
+		if validator.New().Struct(invalid) == nil {
+			t.Errorf("%+v passed validation", invalid)
+		}
+	}
+}
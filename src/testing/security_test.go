@@ -0,0 +1,141 @@
+package testing
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	gotesting "testing"
+
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+	"github.com/syntropysoft/syntrogo/src/security"
+)
+
+// eagerMiddleware inspects next when it is applied, as some third-party
+// middlewares do; it panics on a nil handler.
+func eagerMiddleware(next domain.HandlerFunc) domain.HandlerFunc {
+	if next == nil {
+		panic("eagerMiddleware: nil handler")
+	}
+	return next
+}
+
+// specOf generates an app's spec as decoded JSON.
+func specOf(t *gotesting.T, app *core.App) map[string]interface{} {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := app.WriteSpec(&buf, core.SpecJSON); err != nil {
+		t.Fatal(err)
+	}
+	var spec map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &spec); err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+// operationSecurity returns the security requirement of an operation.
+func operationSecurity(spec map[string]interface{}, path, method string) interface{} {
+	operation := spec["paths"].(map[string]interface{})[path].(map[string]interface{})[method].(map[string]interface{})
+	return operation["security"]
+}
+
+func TestSecuredMiddleware(t *gotesting.T) {
+	bearer := security.BearerToken("secret")
+	both := bearer.WithScheme(security.APIKeyScheme)
+
+	if !reflect.DeepEqual(bearer.Schemes, []domain.SecurityScheme{security.BearerScheme}) {
+		t.Errorf("bearer schemes %v", bearer.Schemes)
+	}
+	if !reflect.DeepEqual(both.Schemes, []domain.SecurityScheme{security.BearerScheme, security.APIKeyScheme}) {
+		t.Errorf("WithScheme schemes %v", both.Schemes)
+	}
+	if len(bearer.Schemes) != 1 {
+		t.Errorf("WithScheme changed the original: %v", bearer.Schemes)
+	}
+	options := both.Options()
+	if len(options.Middlewares) != 1 || !reflect.DeepEqual(options.Security, both.Schemes) {
+		t.Errorf("Options: %d middlewares, security %v", len(options.Middlewares), options.Security)
+	}
+}
+
+func TestSecuritySpecDoesNotRunMiddlewares(t *gotesting.T) {
+	app := core.New()
+	app.Use(eagerMiddleware)
+	api := app.Group("/api", eagerMiddleware).Secure(security.BearerToken("secret"))
+	api.GET("/me", func(c *domain.Context) error { return c.JSON(200, "me") })
+	admin := api.Group("/admin").Secure(security.APIKey("key"))
+	admin.GET("/stats", func(c *domain.Context) error { return c.JSON(200, "stats") },
+		domain.RouteOptions{Middlewares: []domain.Middleware{eagerMiddleware}})
+	app.Group("/api", eagerMiddleware).GET("/status", func(c *domain.Context) error { return c.JSON(200, "up") })
+	app.GET("/keys", func(c *domain.Context) error { return c.JSON(200, "keys") }, security.APIKey("key").Options())
+	app.GET("/health", func(c *domain.Context) error { return c.JSON(200, "ok") })
+
+	spec := specOf(t, app) // Panics if a middleware is called
+
+	schemes := spec["components"].(map[string]interface{})["securitySchemes"].(map[string]interface{})
+	if len(schemes) != 2 || schemes["bearerAuth"] == nil || schemes["apiKeyAuth"] == nil {
+		t.Fatalf("securitySchemes %v", schemes)
+	}
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/api/me", `[{"bearerAuth":[]}]`},
+		{"/api/admin/stats", `[{"apiKeyAuth":[],"bearerAuth":[]}]`},
+		{"/api/status", `null`},
+		{"/keys", `[{"apiKeyAuth":[]}]`},
+		{"/health", `null`},
+	}
+	for _, test := range tests {
+		got, _ := json.Marshal(operationSecurity(spec, test.path, "get"))
+		if string(got) != test.want {
+			t.Errorf("%s security %s, want %s", test.path, got, test.want)
+		}
+	}
+	if spec["security"] != nil {
+		t.Errorf("top-level security %v without global security middlewares", spec["security"])
+	}
+}
+
+// Global security middlewares become the top-level requirement and are repeated
+// on operations with their own security.
+func TestGlobalSecurity(t *gotesting.T) {
+	app := core.New()
+	app.UseSecured(security.APIKey("key"))
+	app.GET("/me", func(c *domain.Context) error { return c.JSON(200, "me") })
+	app.Group("/admin").Secure(security.BearerToken("secret")).
+		GET("/stats", func(c *domain.Context) error { return c.JSON(200, "stats") })
+
+	spec := specOf(t, app)
+	for path, want := range map[string]string{
+		"":             `[{"apiKeyAuth":[]}]`,
+		"/me":          `null`,
+		"/admin/stats": `[{"apiKeyAuth":[],"bearerAuth":[]}]`,
+	} {
+		got := spec["security"]
+		if path != "" {
+			got = operationSecurity(spec, path, "get")
+		}
+		if data, _ := json.Marshal(got); string(data) != want {
+			t.Errorf("%q security %s, want %s", path, data, want)
+		}
+	}
+
+	// The documented middlewares still run
+	server := newAppServer(t, app)
+	for _, test := range []struct {
+		path   string
+		header map[string]string
+		status int
+	}{
+		{"/me", nil, 401},
+		{"/me", map[string]string{"X-API-Key": "key"}, 200},
+		{"/admin/stats", map[string]string{"X-API-Key": "key"}, 401},
+		{"/admin/stats", map[string]string{"X-API-Key": "key", "Authorization": "Bearer secret"}, 200},
+	} {
+		if resp := send(t, "GET", server.URL+test.path, nil, test.header); resp.StatusCode != test.status {
+			t.Errorf("%s %v: %d %s, want %d", test.path, test.header, resp.StatusCode, resp.Text, test.status)
+		}
+	}
+}
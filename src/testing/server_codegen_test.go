@@ -0,0 +1,285 @@
+package testing
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	gotesting "testing"
+
+	"github.com/syntropysoft/syntrogo/src/codegen"
+)
+
+// petSpec has typed and string path parameters.
+const petSpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Pets", "version": "1.0.0"},
+  "paths": {
+    "/owners/{owner}/pets/{id}": {
+      "get": {
+        "operationId": "getPet",
+        "parameters": [
+          {"name": "owner", "in": "path", "required": true, "schema": {"type": "string"}},
+          {"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "format": "int64", "minimum": 1}}
+        ],
+        "responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}
+      }
+    },
+    "/tags/{name}": {
+      "delete": {
+        "parameters": [{"name": "name", "in": "path", "required": true, "schema": {"type": "string"}}],
+        "responses": {"204": {"description": "Deleted"}}
+      }
+    }
+  },
+  "components": {"schemas": {"Pet": {"type": "object", "required": ["id", "owner"], "properties": {"id": {"type": "integer", "format": "int64"}, "owner": {"type": "string"}}}}}
+}`
+
+// petServerTest uses the generated package through the HTTP adapter.
+const petServerTest = `package petserver
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	api "github.com/syntropysoft/syntrogo"
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/infrastructure"
+)
+
+type handlers struct{ calls int }
+
+func (h *handlers) GetPet(c *api.Context, owner string, id int64) (*Pet, error) {
+	h.calls++
+	return &Pet{ID: id, Owner: owner}, nil
+}
+
+func (h *handlers) DeleteTagsByName(c *api.Context, name string) error {
+	h.calls++
+	return nil
+}
+
+func TestTypedPathParams(t *testing.T) {
+	app := core.New()
+	h := &handlers{}
+	Register(app, h)
+	if err := VerifySpec(app); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(infrastructure.NewHTTPAdapter(app.GetRouteRegistry(), app.GetMiddlewareRegistry()))
+	defer server.Close()
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/owners/ada/pets/42", 200, "{\"id\":42,\"owner\":\"ada\"}\n"},
+		{"/owners/ada/pets/abc", 400, ""},
+		{"/owners/ada/pets/0", 422, ""},
+	}
+	for _, test := range tests {
+		resp, err := http.Get(server.URL + test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != test.status || (test.body != "" && string(body) != test.body) {
+			t.Errorf("%s: got %d %q, want %d %q", test.path, resp.StatusCode, body, test.status, test.body)
+		}
+	}
+	if h.calls != 1 {
+		t.Errorf("handler called %d times, want only for the valid id", h.calls)
+	}
+}
+`
+
+func TestGeneratedServerTypedPathParams(t *gotesting.T) {
+	var spec map[string]interface{}
+	if err := json.Unmarshal([]byte(petSpec), &spec); err != nil {
+		t.Fatal(err)
+	}
+	var source bytes.Buffer
+	if err := codegen.GenerateServer("petserver", spec, &source); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"GetPet(c *api.Context, owner string, id int64) (*Pet, error)",
+		"DeleteTagsByName(c *api.Context, name string) error",
+		"if err := c.BindPath(&pathParams); err != nil {",
+		"h.GetPet(c, pathParams.Owner, pathParams.ID)",
+		`h.DeleteTagsByName(c, c.Param("name"))`,
+	} {
+		if !strings.Contains(source.String(), want) {
+			t.Errorf("generated server lacks %q", want)
+		}
+	}
+
+	// Compile the package and run its test inside this module
+	if gotesting.Short() {
+		t.Skip("builds the generated package")
+	}
+	dir, err := os.MkdirTemp(".", "petserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	files := map[string]string{"petserver.go": source.String(), "petserver_test.go": petServerTest}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out, err := exec.Command("go", "test", "./"+filepath.Base(dir)).CombinedOutput()
+	if err != nil {
+		t.Fatalf("generated server: %v\n%s", err, out)
+	}
+}
+
+// searchSpec names a path parameter like a handler argument and uses the
+// PATCH, HEAD and OPTIONS methods.
+const searchSpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Search", "version": "1.0.0"},
+  "paths": {
+    "/search/{query}": {
+      "get": {
+        "parameters": [
+          {"name": "query", "in": "path", "required": true, "schema": {"type": "string"}},
+          {"name": "limit", "in": "query", "schema": {"type": "integer"}}
+        ],
+        "responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Result"}}}}}
+      },
+      "head": {
+        "parameters": [{"name": "query", "in": "path", "required": true, "schema": {"type": "string"}}],
+        "responses": {"200": {"description": "Exists"}}
+      },
+      "options": {
+        "parameters": [{"name": "query", "in": "path", "required": true, "schema": {"type": "string"}}],
+        "responses": {"204": {"description": "Allowed methods"}}
+      }
+    },
+    "/notes/{body}": {
+      "patch": {
+        "parameters": [{"name": "body", "in": "path", "required": true, "schema": {"type": "string"}}],
+        "requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Result"}}}},
+        "responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Result"}}}}}
+      }
+    }
+  },
+  "components": {"schemas": {"Result": {"type": "object", "properties": {"text": {"type": "string"}}}}}
+}`
+
+// searchServerTest calls every generated route through the HTTP adapter.
+const searchServerTest = `package searchserver
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	api "github.com/syntropysoft/syntrogo"
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/infrastructure"
+)
+
+type handlers struct{}
+
+func (handlers) GetSearchByQuery(c *api.Context, queryParam string, query GetSearchByQueryQuery) (*Result, error) {
+	return &Result{Text: fmt.Sprintf("%s:%d", queryParam, query.Limit)}, nil
+}
+
+func (handlers) HeadSearchByQuery(c *api.Context, queryParam string) error { return nil }
+
+func (handlers) OptionsSearchByQuery(c *api.Context, queryParam string) error { return nil }
+
+func (handlers) PatchNotesByBody(c *api.Context, bodyParam string, body Result) (*Result, error) {
+	return &Result{Text: bodyParam + ":" + body.Text}, nil
+}
+
+func TestRoutes(t *testing.T) {
+	app := core.New()
+	Register(app, handlers{})
+	if err := VerifySpec(app); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(infrastructure.NewHTTPAdapter(app.GetRouteRegistry(), app.GetMiddlewareRegistry()))
+	defer server.Close()
+
+	tests := []struct {
+		method, path, body string
+		status             int
+		want               string
+	}{
+		{"GET", "/search/go?limit=3", "", 200, "{\"text\":\"go:3\"}\n"},
+		{"HEAD", "/search/go", "", 200, ""},
+		{"OPTIONS", "/search/go", "", 204, ""},
+		{"PATCH", "/notes/n1", ` + "`" + `{"text":"hi"}` + "`" + `, 200, "{\"text\":\"n1:hi\"}\n"},
+	}
+	for _, test := range tests {
+		req, _ := http.NewRequest(test.method, server.URL+test.path, strings.NewReader(test.body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != test.status || string(body) != test.want {
+			t.Errorf("%s %s: got %d %q, want %d %q", test.method, test.path, resp.StatusCode, body, test.status, test.want)
+		}
+	}
+}
+`
+
+func TestGeneratedServerNamesAndMethods(t *gotesting.T) {
+	var spec map[string]interface{}
+	if err := json.Unmarshal([]byte(searchSpec), &spec); err != nil {
+		t.Fatal(err)
+	}
+	var source bytes.Buffer
+	if err := codegen.GenerateServer("searchserver", spec, &source); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"GetSearchByQuery(c *api.Context, queryParam string, query GetSearchByQueryQuery) (*Result, error)",
+		"PatchNotesByBody(c *api.Context, bodyParam string, body Result) (*Result, error)",
+		`app.PATCH("/notes/:body", func(c *api.Context) error {`,
+		`app.HEAD("/search/:query", func(c *api.Context) error {`,
+		`app.OPTIONS("/search/:query", func(c *api.Context) error {`,
+	} {
+		if !strings.Contains(source.String(), want) {
+			t.Errorf("generated server lacks %q", want)
+		}
+	}
+
+	// Compile the package and run its test inside this module
+	if gotesting.Short() {
+		t.Skip("builds the generated package")
+	}
+	dir, err := os.MkdirTemp(".", "searchserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	files := map[string]string{"searchserver.go": source.String(), "searchserver_test.go": searchServerTest}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out, err := exec.Command("go", "test", "./"+filepath.Base(dir)).CombinedOutput()
+	if err != nil {
+		t.Fatalf("generated server: %v\n%s", err, out)
+	}
+}
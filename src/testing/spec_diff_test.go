@@ -0,0 +1,241 @@
+package testing
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	gotesting "testing"
+
+	"github.com/syntropysoft/syntrogo/src/application"
+)
+
+// usersSpec is the old version every diff case edits.
+const usersSpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Users", "version": "1.0.0"},
+  "paths": {
+    "/users": {
+      "post": {
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/NewUser"}}}},
+        "responses": {"201": {"description": "Created", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}}}
+      }
+    },
+    "/users/{id}": {
+      "get": {
+        "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+        "responses": {
+          "200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}},
+          "404": {"description": "Not found"}
+        }
+      },
+      "delete": {
+        "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+        "responses": {"204": {"description": "Deleted"}}
+      }
+    }
+  },
+  "components": {"schemas": {
+    "NewUser": {"type": "object", "required": ["name"], "properties": {
+      "name": {"type": "string"},
+      "nickname": {"type": "string"},
+      "role": {"type": "string", "enum": ["admin", "user", "guest"]}
+    }},
+    "User": {"type": "object", "required": ["id", "name"], "properties": {
+      "id": {"type": "integer"},
+      "name": {"type": "string"},
+      "email": {"type": "string"}
+    }}
+  }}
+}`
+
+// decodedUsersSpec returns a fresh copy of usersSpec.
+func decodedUsersSpec(t *gotesting.T) map[string]interface{} {
+	t.Helper()
+	var spec map[string]interface{}
+	if err := json.Unmarshal([]byte(usersSpec), &spec); err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+// at walks a decoded spec through object keys and array indexes.
+func at(v interface{}, path ...interface{}) map[string]interface{} {
+	for _, step := range path {
+		switch key := step.(type) {
+		case string:
+			v = v.(map[string]interface{})[key]
+		case int:
+			v = v.([]interface{})[key]
+		}
+	}
+	return v.(map[string]interface{})
+}
+
+func TestDiffSpecs(t *gotesting.T) {
+	schemas := func(spec map[string]interface{}, name string) map[string]interface{} {
+		return at(spec, "components", "schemas", name)
+	}
+
+	tests := []struct {
+		name   string
+		change func(spec map[string]interface{})
+		want   []string
+	}{
+		{"unchanged", func(spec map[string]interface{}) {}, nil},
+		{"operation removed", func(spec map[string]interface{}) {
+			delete(at(spec, "paths", "/users/{id}"), "delete")
+		}, []string{"BREAKING DELETE /users/{id}: operation removed"}},
+		{"operation added", func(spec map[string]interface{}) {
+			at(spec, "paths", "/users")["get"] = map[string]interface{}{"responses": map[string]interface{}{"200": map[string]interface{}{"description": "OK"}}}
+		}, []string{"info GET /users: operation added"}},
+		{"response field removed", func(spec map[string]interface{}) {
+			delete(at(schemas(spec, "User"), "properties"), "email")
+		}, []string{
+			"BREAKING GET /users/{id} response 200: email: field removed",
+			"BREAKING POST /users response 201: email: field removed",
+		}},
+		{"response field no longer required", func(spec map[string]interface{}) {
+			schemas(spec, "User")["required"] = []interface{}{"id"}
+		}, []string{
+			"BREAKING GET /users/{id} response 200: name: field is no longer always returned",
+			"BREAKING POST /users response 201: name: field is no longer always returned",
+		}},
+		{"request field removed", func(spec map[string]interface{}) {
+			delete(at(schemas(spec, "NewUser"), "properties"), "nickname")
+		}, []string{"info POST /users request body: nickname: field removed"}},
+		{"required request field added", func(spec map[string]interface{}) {
+			user := schemas(spec, "NewUser")
+			at(user, "properties")["age"] = map[string]interface{}{"type": "integer"}
+			user["required"] = []interface{}{"name", "age"}
+		}, []string{"BREAKING POST /users request body: age: new required field"}},
+		{"optional request field added", func(spec map[string]interface{}) {
+			at(schemas(spec, "NewUser"), "properties")["age"] = map[string]interface{}{"type": "integer"}
+		}, []string{"info POST /users request body: age: field added"}},
+		{"request field became required", func(spec map[string]interface{}) {
+			schemas(spec, "NewUser")["required"] = []interface{}{"name", "nickname"}
+		}, []string{"BREAKING POST /users request body: nickname: field became required"}},
+		{"response type changed", func(spec map[string]interface{}) {
+			at(schemas(spec, "User"), "properties", "id")["type"] = "string"
+		}, []string{
+			"BREAKING GET /users/{id} response 200: id: type changed from integer to string",
+			"BREAKING POST /users response 201: id: type changed from integer to string",
+		}},
+		{"parameter type widened", func(spec map[string]interface{}) {
+			at(spec, "paths", "/users/{id}", "get", "parameters", 0, "schema")["type"] = "number"
+		}, []string{"info GET /users/{id} parameter path:id: type changed from integer to number"}},
+		{"parameter type changed", func(spec map[string]interface{}) {
+			at(spec, "paths", "/users/{id}", "get", "parameters", 0, "schema")["type"] = "string"
+		}, []string{"BREAKING GET /users/{id} parameter path:id: type changed from integer to string"}},
+		{"new required parameter", func(spec map[string]interface{}) {
+			get := at(spec, "paths", "/users/{id}", "get")
+			get["parameters"] = append(get["parameters"].([]interface{}),
+				map[string]interface{}{"name": "X-Tenant", "in": "header", "required": true, "schema": map[string]interface{}{"type": "string"}})
+		}, []string{"BREAKING GET /users/{id}: new required parameter header:x-tenant"}},
+		{"request enum narrowed", func(spec map[string]interface{}) {
+			at(schemas(spec, "NewUser"), "properties", "role")["enum"] = []interface{}{"admin", "user"}
+		}, []string{"BREAKING POST /users request body: role: enum value guest removed"}},
+		{"request enum widened", func(spec map[string]interface{}) {
+			at(schemas(spec, "NewUser"), "properties", "role")["enum"] = []interface{}{"admin", "user", "guest", "owner"}
+		}, []string{"info POST /users request body: role: enum value owner added"}},
+		{"success response removed", func(spec map[string]interface{}) {
+			responses := at(spec, "paths", "/users/{id}", "get", "responses")
+			delete(responses, "200")
+			responses["204"] = map[string]interface{}{"description": "No content"}
+		}, []string{"BREAKING GET /users/{id}: response 200 removed"}},
+		{"error response removed", func(spec map[string]interface{}) {
+			delete(at(spec, "paths", "/users/{id}", "get", "responses"), "404")
+		}, nil},
+	}
+
+	for _, test := range tests {
+		newSpec := decodedUsersSpec(t)
+		test.change(newSpec)
+
+		changes := application.DiffSpecs(decodedUsersSpec(t), newSpec)
+		var got []string
+		for _, change := range changes {
+			got = append(got, change.String())
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s:\ngot  %q\nwant %q", test.name, got, test.want)
+		}
+		if breaking := strings.HasPrefix(strings.Join(test.want, "\n"), "BREAKING"); application.HasBreakingChanges(changes) != breaking {
+			t.Errorf("%s: HasBreakingChanges = %v", test.name, !breaking)
+		}
+	}
+}
+
+// `syntrogo diff` exits non-zero only for breaking changes, so it can gate CI.
+func TestDiffCommandExitCode(t *gotesting.T) {
+	if gotesting.Short() {
+		t.Skip("builds the syntrogo command")
+	}
+	dir := t.TempDir()
+	command := filepath.Join(dir, "syntrogo")
+	if out, err := exec.Command("go", "build", "-o", command, "github.com/syntropysoft/syntrogo/cmd/syntrogo").CombinedOutput(); err != nil {
+		t.Fatalf("build: %v\n%s", err, out)
+	}
+
+	writeSpec := func(name string, change func(spec map[string]interface{})) string {
+		spec := decodedUsersSpec(t)
+		change(spec)
+		data, err := json.Marshal(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	old := writeSpec("old.json", func(spec map[string]interface{}) {})
+	additive := writeSpec("additive.json", func(spec map[string]interface{}) {
+		at(spec, "components", "schemas", "User", "properties")["avatar"] = map[string]interface{}{"type": "string"}
+	})
+	breaking := writeSpec("breaking.json", func(spec map[string]interface{}) {
+		delete(at(spec, "paths", "/users/{id}"), "delete")
+		delete(at(spec, "components", "schemas", "User", "properties"), "email")
+	})
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{"same spec", []string{old, old}, 0, "", ""},
+		{"additive", []string{old, additive}, 0,
+			"info GET /users/{id} response 200: avatar: field added\ninfo POST /users response 201: avatar: field added\n", ""},
+		{"breaking", []string{old, breaking}, 1,
+			"BREAKING DELETE /users/{id}: operation removed\n" +
+				"BREAKING GET /users/{id} response 200: email: field removed\n" +
+				"BREAKING POST /users response 201: email: field removed\n",
+			"syntrogo: 3 breaking change(s)\n"},
+		{"breaking only", []string{"-breaking", old, additive}, 0, "", ""},
+		{"missing argument", []string{old}, 1, "", "syntrogo: usage: syntrogo diff [-breaking] old.json new.json\n"},
+	}
+	for _, test := range tests {
+		cmd := exec.Command(command, append([]string{"diff"}, test.args...)...)
+		var stdout, stderr strings.Builder
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		err := cmd.Run()
+
+		code := 0
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			code = exitErr.ExitCode()
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if code != test.code || stdout.String() != test.stdout || stderr.String() != test.stderr {
+			t.Errorf("%s: exit %d\nstdout %q\nstderr %q\nwant exit %d\nstdout %q\nstderr %q",
+				test.name, code, stdout.String(), stderr.String(), test.code, test.stdout, test.stderr)
+		}
+	}
+}
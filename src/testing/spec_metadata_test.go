@@ -0,0 +1,115 @@
+package testing
+
+import (
+	gotesting "testing"
+
+	api "github.com/syntropysoft/syntrogo"
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+func TestSpecInfoServersAndTags(t *gotesting.T) {
+	app := core.New().
+		Title("Shop API").
+		Version("2.1.0").
+		Description("Orders and *customers*.").
+		Contact("API team", "https://example.com/support", "api@example.com").
+		License("MIT", "").
+		Server("https://api.example.com", "Production").
+		Server("http://localhost:3000", "").
+		Tag("orders", "Order management").
+		TagDocs("customers", "", domain.ExternalDocs{URL: "https://example.com/customers", Description: "Guide"})
+	spec := specOf(t, app)
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want string
+	}{
+		{"info", spec["info"], `{"contact":{"email":"api@example.com","name":"API team","url":"https://example.com/support"},` +
+			`"description":"Orders and *customers*.","license":{"name":"MIT"},"title":"Shop API","version":"2.1.0"}`},
+		{"servers", spec["servers"], `[{"description":"Production","url":"https://api.example.com"},{"url":"http://localhost:3000"}]`},
+		{"tags", spec["tags"], `[{"description":"Order management","name":"orders"},` +
+			`{"externalDocs":{"description":"Guide","url":"https://example.com/customers"},"name":"customers"}]`},
+	}
+	for _, test := range tests {
+		if got := compactJSON(t, test.got); got != test.want {
+			t.Errorf("%s:\n%s\nwant\n%s", test.name, got, test.want)
+		}
+	}
+
+	// Without configuration only the required info fields are written
+	bare := specOf(t, core.New())
+	if got := compactJSON(t, bare["info"]); got != `{"title":"SyntroGo API","version":"1.0.0"}` {
+		t.Errorf("default info %s", got)
+	}
+	if bare["servers"] != nil || bare["tags"] != nil {
+		t.Errorf("unconfigured servers %v, tags %v", bare["servers"], bare["tags"])
+	}
+}
+
+func TestSpecOperationMetadata(t *gotesting.T) {
+	app := core.New()
+	noop := func(c *domain.Context) error { return nil }
+	app.POST("/orders", noop,
+		api.Summary("Create an order"),
+		api.Description("Creates an order.\n\nCharges the card on file."),
+		api.OperationID("createOrder"),
+		api.Tags("orders", "billing"),
+		api.ExternalDocs("https://example.com/orders", "Ordering guide"),
+		api.Body(Note{}),
+		api.RequestExample(Note{Text: "two coffees"}),
+		api.Response(201, Note{}),
+		api.ResponseExample(map[string]string{"text": "accepted"}),
+	)
+	app.GET("/orders/legacy", noop, api.Deprecated(), api.Summary("Old listing"))
+	app.GET("/orders/plain", noop)
+	spec := specOf(t, app)
+	paths := spec["paths"].(map[string]interface{})
+	operation := func(path, method string) map[string]interface{} {
+		return paths[path].(map[string]interface{})[method].(map[string]interface{})
+	}
+
+	create := operation("/orders", "post")
+	for key, want := range map[string]string{
+		"summary":      `"Create an order"`,
+		"description":  `"Creates an order.\n\nCharges the card on file."`,
+		"operationId":  `"createOrder"`,
+		"tags":         `["orders","billing"]`,
+		"externalDocs": `{"description":"Ordering guide","url":"https://example.com/orders"}`,
+	} {
+		if got := compactJSON(t, create[key]); got != want {
+			t.Errorf("POST /orders %s:\n%s\nwant\n%s", key, got, want)
+		}
+	}
+	body := create["requestBody"].(map[string]interface{})
+	response := create["responses"].(map[string]interface{})["201"].(map[string]interface{})
+	noteSchema := `"schema":{"properties":{"text":{"type":"string"}},"title":"Note","type":"object"}`
+	for name, media := range map[string]struct {
+		got  interface{}
+		want string
+	}{
+		"request":  {body["content"].(map[string]interface{})["application/json"], `{"example":{"text":"two coffees"},` + noteSchema + `}`},
+		"response": {response["content"].(map[string]interface{})["application/json"], `{"example":{"text":"accepted"},` + noteSchema + `}`},
+	} {
+		if got := compactJSON(t, media.got); got != media.want {
+			t.Errorf("POST /orders %s:\n%s\nwant\n%s", name, got, media.want)
+		}
+	}
+	if body["required"] != true || response["description"] != "Success" {
+		t.Errorf("POST /orders body required %v, response description %v", body["required"], response["description"])
+	}
+	if create["deprecated"] != nil {
+		t.Errorf("POST /orders deprecated = %v", create["deprecated"])
+	}
+
+	if legacy := operation("/orders/legacy", "get"); legacy["deprecated"] != true || legacy["summary"] != "Old listing" {
+		t.Errorf("GET /orders/legacy: %s", compactJSON(t, legacy))
+	}
+
+	// Unset metadata is left out rather than written empty
+	plain := operation("/orders/plain", "get")
+	if got := compactJSON(t, plain); got != `{"responses":{"200":{"description":"Success"}}}` {
+		t.Errorf("GET /orders/plain: %s", got)
+	}
+}
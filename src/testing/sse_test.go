@@ -0,0 +1,262 @@
+package testing
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	gotesting "testing"
+	"time"
+
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// markingEngine is a JSON engine whose output shows it was used.
+type markingEngine struct{}
+
+func (markingEngine) Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(`{"engine":true,"value":`), append(data, '}')...), nil
+}
+
+func (markingEngine) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func TestSSELineBreaks(t *gotesting.T) {
+	app := core.New()
+	sendErrors := make(chan error, 2)
+	app.GET("/events", func(c *domain.Context) error {
+		events, err := c.SSE()
+		if err != nil {
+			return err
+		}
+		if err := events.Send(domain.Event{Data: "a\rid: 666\r\nevent: admin\nretry: 1\r"}); err != nil {
+			return err
+		}
+		if err := events.Comment("note\rdata: injected"); err != nil {
+			return err
+		}
+		sendErrors <- events.Send(domain.Event{ID: "1\r", Data: "x"})
+		sendErrors <- events.Send(domain.Event{Event: "tick\rdata: injected", Data: "x"})
+		return nil
+	})
+	server := newAppServer(t, app)
+
+	resp := send(t, "GET", server.URL+"/events", nil, nil)
+	want := "data: a\ndata: id: 666\ndata: event: admin\ndata: retry: 1\ndata: \n\n" +
+		": note\n: data: injected\n\n"
+	if resp.Text != want {
+		t.Fatalf("stream %q, want %q", resp.Text, want)
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-sendErrors; err == nil {
+			t.Error("a line break in id or event was accepted")
+		}
+	}
+}
+
+func TestSSEUsesJSONEngine(t *gotesting.T) {
+	app := core.New()
+	app.JSONEngine(markingEngine{})
+	app.GET("/events", func(c *domain.Context) error {
+		events, err := c.SSE()
+		if err != nil {
+			return err
+		}
+		return events.Data(map[string]int{"n": 1})
+	})
+	server := newAppServer(t, app)
+
+	resp := send(t, "GET", server.URL+"/events", nil, nil)
+	if want := "data: {\"engine\":true,\"value\":{\"n\":1}}\n\n"; resp.Text != want {
+		t.Fatalf("stream %q, want %q", resp.Text, want)
+	}
+}
+
+// openStreamResponse starts a streaming request and returns the response with a
+// line reader; the body is closed at cleanup.
+func openStreamResponse(t *gotesting.T, url string, header map[string]string) (*http.Response, *bufio.Reader) {
+	t.Helper()
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp, bufio.NewReader(resp.Body)
+}
+
+// readUntilBlank reads lines up to the blank line ending an SSE event.
+func readUntilBlank(t *gotesting.T, reader *bufio.Reader) string {
+	t.Helper()
+	var event strings.Builder
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event: %q, %v", event.String(), err)
+		}
+		if line == "\n" {
+			return event.String()
+		}
+		event.WriteString(line)
+	}
+}
+
+// Every write reaches the client before the handler continues.
+func TestStreamFlushesWrites(t *gotesting.T) {
+	app := core.New()
+	proceed := make(chan struct{})
+	secondStream := make(chan error, 1)
+	app.GET("/export", func(c *domain.Context) error {
+		return c.Status(202).Stream("text/csv", func(w io.Writer) error {
+			if _, err := io.WriteString(w, "id,name\n"); err != nil {
+				return err
+			}
+			<-proceed // Only the flushed first row can unblock the client
+			secondStream <- c.Stream("text/plain", func(io.Writer) error { return nil })
+			_, err := io.WriteString(w, "1,ada\n")
+			return err
+		})
+	})
+	server := newAppServer(t, app)
+
+	resp, reader := openStreamResponse(t, server.URL+"/export", nil)
+	if resp.StatusCode != 202 || resp.Header.Get("Content-Type") != "text/csv" {
+		t.Fatalf("%d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if line, err := reader.ReadString('\n'); err != nil || line != "id,name\n" {
+		t.Fatalf("first row %q, %v", line, err)
+	}
+	close(proceed)
+	if rest, err := io.ReadAll(reader); err != nil || string(rest) != "1,ada\n" {
+		t.Errorf("rest %q, %v", rest, err)
+	}
+	if err := <-secondStream; err == nil || !strings.Contains(err.Error(), "already streaming") {
+		t.Errorf("second stream: %v", err)
+	}
+}
+
+// A reconnecting client resumes after the Last-Event-ID it sends.
+func TestSSEResumesFromLastEventID(t *gotesting.T) {
+	app := core.New()
+	app.GET("/events", func(c *domain.Context) error {
+		events, err := c.SSE()
+		if err != nil {
+			return err
+		}
+		last, _ := strconv.Atoi(events.LastEventID())
+		next := last + 1
+		if next == 1 {
+			if err := events.Retry(2 * time.Second); err != nil {
+				return err
+			}
+		}
+		for id := next; id <= 3; id++ {
+			if err := events.Send(domain.Event{ID: strconv.Itoa(id), Event: "tick", Data: map[string]int{"n": id}}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	server := newAppServer(t, app)
+
+	tests := []struct {
+		lastEventID string
+		want        string
+	}{
+		{"", "retry: 2000\n\nid: 1\nevent: tick\ndata: {\"n\":1}\n\nid: 2\nevent: tick\ndata: {\"n\":2}\n\nid: 3\nevent: tick\ndata: {\"n\":3}\n\n"},
+		{"2", "id: 3\nevent: tick\ndata: {\"n\":3}\n\n"},
+	}
+	for _, test := range tests {
+		header := map[string]string{}
+		if test.lastEventID != "" {
+			header["Last-Event-ID"] = test.lastEventID
+		}
+		resp := send(t, "GET", server.URL+"/events", nil, header)
+		if resp.Text != test.want {
+			t.Errorf("Last-Event-ID %q: %q, want %q", test.lastEventID, resp.Text, test.want)
+		}
+		for name, want := range map[string]string{"Content-Type": "text/event-stream", "Cache-Control": "no-cache", "X-Accel-Buffering": "no"} {
+			if got := resp.Header.Get(name); got != want {
+				t.Errorf("%s: %q, want %q", name, got, want)
+			}
+		}
+	}
+}
+
+func TestSSEHeartbeat(t *gotesting.T) {
+	app := core.New()
+	received := make(chan struct{})
+	app.GET("/events", func(c *domain.Context) error {
+		events, err := c.SSE()
+		if err != nil {
+			return err
+		}
+		events.Heartbeat(10 * time.Millisecond)
+		select {
+		case <-received:
+			return nil
+		case <-time.After(time.Second):
+			return events.Data("no heartbeat read")
+		}
+	})
+	server := newAppServer(t, app)
+
+	_, reader := openStreamResponse(t, server.URL+"/events", nil)
+	for i := 0; i < 2; i++ {
+		if event := readUntilBlank(t, reader); event != ": heartbeat\n" {
+			t.Fatalf("heartbeat %d: %q", i, event)
+		}
+	}
+	close(received)
+}
+
+// Send fails once the client has gone away, so handlers stop producing.
+func TestSSESendFailsAfterDisconnect(t *gotesting.T) {
+	app := core.New()
+	sendErr := make(chan error, 1)
+	app.GET("/events", func(c *domain.Context) error {
+		events, err := c.SSE()
+		if err != nil {
+			return err
+		}
+		if err := events.Data("first"); err != nil {
+			return err
+		}
+		select {
+		case <-events.Done():
+		case <-time.After(time.Second):
+		}
+		sendErr <- events.Data("after disconnect")
+		return nil
+	})
+	server := newAppServer(t, app)
+
+	resp, reader := openStreamResponse(t, server.URL+"/events", nil)
+	if event := readUntilBlank(t, reader); event != "data: first\n" {
+		t.Fatalf("first event %q", event)
+	}
+	resp.Body.Close()
+
+	select {
+	case err := <-sendErr:
+		if err == nil {
+			t.Error("Send succeeded after the client disconnected")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler did not notice the disconnect")
+	}
+}
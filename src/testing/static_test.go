@@ -0,0 +1,237 @@
+package testing
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	gotesting "testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+var staticModTime = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+// staticFiles is a built front end: assets with precompressed variants, an
+// entry point and a file without a modification time, as embed.FS has.
+func staticFiles() fstest.MapFS {
+	file := func(content string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(content), ModTime: staticModTime}
+	}
+	return fstest.MapFS{
+		"index.html":         file("<!doctype html><title>app</title>"),
+		"assets/app.js":      file("console.log('identity')"),
+		"assets/app.js.br":   file("br-bytes"),
+		"assets/app.js.gz":   file("gz-bytes"),
+		"assets/style.css":   file("body{}"),
+		"assets/embedded":    {Data: []byte("no modification time")},
+		"docs/guide.txt":     file("guide"),
+		"docs/a&b <x>.txt":   file("escaped"),
+		"docs/sub/index.htm": file("not the index"),
+	}
+}
+
+// newStaticServer serves staticFiles under /app next to an API route.
+func newStaticServer(t *gotesting.T, options domain.StaticOptions) string {
+	app := core.New()
+	app.Static("/app", staticFiles(), options)
+	app.GET("/app/api/health", func(c *domain.Context) error { return c.JSON(200, "ok") })
+	return newAppServer(t, app).URL + "/app"
+}
+
+func TestStaticFiles(t *gotesting.T) {
+	url := newStaticServer(t, domain.StaticOptions{MaxAge: time.Hour})
+
+	resp := send(t, "GET", url+"/assets/style.css", nil, nil)
+	etag := resp.Header.Get("ETag")
+	switch {
+	case resp.StatusCode != 200 || resp.Text != "body{}":
+		t.Fatalf("file: %d %q", resp.StatusCode, resp.Text)
+	case resp.Header.Get("Content-Type") != "text/css; charset=utf-8":
+		t.Errorf("Content-Type %q", resp.Header.Get("Content-Type"))
+	case etag == "" || resp.Header.Get("Last-Modified") != "Wed, 01 May 2024 10:00:00 GMT":
+		t.Errorf("validators ETag %q, Last-Modified %q", etag, resp.Header.Get("Last-Modified"))
+	case resp.Header.Get("Cache-Control") != "public, max-age=3600":
+		t.Errorf("Cache-Control %q", resp.Header.Get("Cache-Control"))
+	case resp.Header.Get("Vary") != "":
+		t.Errorf("Vary %q on a file without variants", resp.Header.Get("Vary"))
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		header map[string]string
+		status int
+		body   string
+	}{
+		{"HEAD", "HEAD", "/assets/style.css", nil, 200, ""},
+		{"If-None-Match", "GET", "/assets/style.css", map[string]string{"If-None-Match": etag}, 304, ""},
+		{"If-None-Match list", "GET", "/assets/style.css", map[string]string{"If-None-Match": `"other", ` + etag}, 304, ""},
+		{"If-None-Match stale", "GET", "/assets/style.css", map[string]string{"If-None-Match": `"other"`}, 200, "body{}"},
+		{"If-Modified-Since", "GET", "/assets/style.css", map[string]string{"If-Modified-Since": "Wed, 01 May 2024 10:00:00 GMT"}, 304, ""},
+		{"If-Modified-Since older", "GET", "/assets/style.css", map[string]string{"If-Modified-Since": "Tue, 30 Apr 2024 10:00:00 GMT"}, 200, "body{}"},
+		{"Range", "GET", "/assets/style.css", map[string]string{"Range": "bytes=0-3"}, 206, "body"},
+		{"suffix Range", "GET", "/assets/style.css", map[string]string{"Range": "bytes=-2"}, 206, "{}"},
+		{"unsatisfiable Range", "GET", "/assets/style.css", map[string]string{"Range": "bytes=100-"}, 416, "invalid range: failed to overlap\n"},
+		{"If-Range current", "GET", "/assets/style.css", map[string]string{"Range": "bytes=4-", "If-Range": etag}, 206, "{}"},
+		{"If-Range stale", "GET", "/assets/style.css", map[string]string{"Range": "bytes=4-", "If-Range": `"old"`}, 200, "body{}"},
+		{"missing file", "GET", "/assets/missing.css", nil, 404, ""},
+		{"route wins", "GET", "/api/health", nil, 200, "\"ok\"\n"},
+	}
+	for _, test := range tests {
+		resp := send(t, test.method, url+test.path, nil, test.header)
+		if resp.StatusCode != test.status || test.status != 404 && resp.Text != test.body {
+			t.Errorf("%s: %d %q, want %d %q", test.name, resp.StatusCode, resp.Text, test.status, test.body)
+		}
+	}
+
+	if resp := send(t, "GET", url+"/assets/style.css", nil, map[string]string{"Range": "bytes=0-3"}); resp.Header.Get("Content-Range") != "bytes 0-3/6" {
+		t.Errorf("Content-Range %q", resp.Header.Get("Content-Range"))
+	}
+}
+
+// Files without a modification time get a content hash ETag.
+func TestStaticContentHashETag(t *gotesting.T) {
+	url := newStaticServer(t, domain.StaticOptions{})
+
+	first := send(t, "GET", url+"/assets/embedded", nil, nil)
+	etag := first.Header.Get("ETag")
+	if first.StatusCode != 200 || len(etag) != 34 || first.Header.Get("Last-Modified") != "" {
+		t.Fatalf("%d ETag %q Last-Modified %q", first.StatusCode, etag, first.Header.Get("Last-Modified"))
+	}
+	if first.Header.Get("Content-Type") != "text/plain; charset=utf-8" {
+		t.Errorf("sniffed Content-Type %q", first.Header.Get("Content-Type"))
+	}
+	if again := send(t, "GET", url+"/assets/embedded", nil, nil); again.Header.Get("ETag") != etag {
+		t.Errorf("ETag changed from %s to %s", etag, again.Header.Get("ETag"))
+	}
+	if resp := send(t, "GET", url+"/assets/embedded", nil, map[string]string{"If-None-Match": etag}); resp.StatusCode != 304 {
+		t.Errorf("If-None-Match: %d", resp.StatusCode)
+	}
+}
+
+func TestStaticPrecompressed(t *gotesting.T) {
+	url := newStaticServer(t, domain.StaticOptions{})
+	identity := send(t, "GET", url+"/assets/app.js", nil, map[string]string{"Accept-Encoding": "identity"})
+
+	tests := []struct {
+		accept   string
+		body     string
+		encoding string
+	}{
+		{"deflate", "console.log('identity')", ""},
+		{"gzip, deflate, br", "br-bytes", "br"},
+		{"gzip", "gz-bytes", "gzip"},
+		{"br;q=0, gzip;q=0.5", "gz-bytes", "gzip"},
+		{"BR;q=0.1", "br-bytes", "br"},
+		{"br;q=0, gzip;q=0", "console.log('identity')", ""},
+		{"identity", "console.log('identity')", ""},
+	}
+	for _, test := range tests {
+		resp := send(t, "GET", url+"/assets/app.js", nil, map[string]string{"Accept-Encoding": test.accept})
+		switch {
+		case resp.StatusCode != 200 || resp.Text != test.body:
+			t.Errorf("%q: %d %q, want %q", test.accept, resp.StatusCode, resp.Text, test.body)
+		case resp.Header.Get("Content-Encoding") != test.encoding:
+			t.Errorf("%q: Content-Encoding %q", test.accept, resp.Header.Get("Content-Encoding"))
+		case resp.Header.Get("Content-Type") != "text/javascript; charset=utf-8":
+			t.Errorf("%q: Content-Type %q", test.accept, resp.Header.Get("Content-Type"))
+		case resp.Header.Get("Vary") != "Accept-Encoding":
+			t.Errorf("%q: Vary %q", test.accept, resp.Header.Get("Vary"))
+		case test.encoding != "" && resp.Header.Get("ETag") == identity.Header.Get("ETag"):
+			t.Errorf("%q: encoded variant shares the identity ETag", test.accept)
+		}
+	}
+
+	// A cached identity response does not validate the encoded variant
+	br := send(t, "GET", url+"/assets/app.js", nil, map[string]string{"Accept-Encoding": "br"})
+	header := map[string]string{"Accept-Encoding": "br", "If-None-Match": identity.Header.Get("ETag")}
+	if resp := send(t, "GET", url+"/assets/app.js", nil, header); resp.StatusCode != 200 || resp.Text != "br-bytes" {
+		t.Errorf("identity ETag on br request: %d %q", resp.StatusCode, resp.Text)
+	}
+	header["If-None-Match"] = br.Header.Get("ETag")
+	if resp := send(t, "GET", url+"/assets/app.js", nil, header); resp.StatusCode != 304 {
+		t.Errorf("br ETag on br request: %d", resp.StatusCode)
+	}
+
+	// Ranges apply to the encoded bytes
+	resp := send(t, "GET", url+"/assets/app.js", nil, map[string]string{"Accept-Encoding": "gzip", "Range": "bytes=0-1"})
+	if resp.StatusCode != 206 || resp.Text != "gz" {
+		t.Errorf("Range on gzip variant: %d %q", resp.StatusCode, resp.Text)
+	}
+}
+
+func TestStaticSPAAndDirectories(t *gotesting.T) {
+	spa := newStaticServer(t, domain.StaticOptions{SPA: true, MaxAge: time.Hour})
+	browse := newStaticServer(t, domain.StaticOptions{Browse: true})
+	plain := newStaticServer(t, domain.StaticOptions{})
+	const index = "<!doctype html><title>app</title>"
+
+	tests := []struct {
+		name     string
+		url      string
+		status   int
+		body     string // Substring
+		location string
+	}{
+		{"root", spa + "/", 200, index, ""},
+		{"client route", spa + "/users/42", 200, index, ""},
+		{"client route with query", spa + "/users?page=2", 200, index, ""},
+		{"missing asset", spa + "/assets/missing.js", 404, "Not Found", ""},
+		{"no SPA fallback", plain + "/users/42", 404, "Not Found", ""},
+		{"directory redirect", plain + "/docs?x=1", 301, "", "/app/docs/?x=1"},
+		{"directory without index", plain + "/docs/", 404, "Not Found", ""},
+		{"other index names ignored", plain + "/docs/sub/", 404, "Not Found", ""},
+		{"listing", browse + "/docs/", 200, `<a href="a&amp;b%20%3Cx%3E.txt">a&amp;b &lt;x&gt;.txt</a>`, ""},
+		{"listing subdirectory", browse + "/docs/", 200, `<a href="sub/">sub/</a>`, ""},
+	}
+	for _, test := range tests {
+		resp := send(t, "GET", test.url, nil, nil)
+		if resp.StatusCode != test.status || !strings.Contains(resp.Text, test.body) || resp.Header.Get("Location") != test.location {
+			t.Errorf("%s: %d %q Location %q, want %d %q %q", test.name, resp.StatusCode, resp.Text, resp.Header.Get("Location"), test.status, test.body, test.location)
+		}
+	}
+
+	// The entry point is always revalidated, so new deploys reach clients
+	if resp := send(t, "GET", spa+"/users/42", nil, nil); resp.Header.Get("Cache-Control") != "no-cache" {
+		t.Errorf("index Cache-Control %q", resp.Header.Get("Cache-Control"))
+	}
+}
+
+func TestStaticTraversal(t *gotesting.T) {
+	root := t.TempDir()
+	public := filepath.Join(root, "public")
+	if err := os.Mkdir(public, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{filepath.Join(root, "secret.txt"): "secret", filepath.Join(public, "ok.txt"): "public"} {
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	app := core.New()
+	app.Static("/files", os.DirFS(public))
+	url := newAppServer(t, app).URL
+
+	if resp := send(t, "GET", url+"/files/ok.txt", nil, nil); resp.StatusCode != 200 || resp.Text != "public" {
+		t.Fatalf("public file: %d %q", resp.StatusCode, resp.Text)
+	}
+	for _, path := range []string{
+		"/files/../secret.txt",
+		"/files/../../secret.txt",
+		"/files/..%2fsecret.txt",
+		"/files/%2e%2e/secret.txt",
+		"/files/%2e%2e%2fsecret.txt",
+		"/files/sub/../../secret.txt",
+		"/files//secret.txt",
+		"/files/..\\secret.txt",
+	} {
+		resp := send(t, "GET", url+path, nil, nil)
+		if resp.StatusCode != 404 || strings.Contains(resp.Text, "secret") {
+			t.Errorf("%s: %d %q", path, resp.StatusCode, resp.Text)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	gotesting "testing"
+	"time"
+
+	"github.com/syntropysoft/syntrogo/src/application"
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// taskErrors collects what a pool reports to OnError.
+type taskErrors struct {
+	mu     sync.Mutex
+	errors []error
+}
+
+func (e *taskErrors) add(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.errors = append(e.errors, err)
+}
+
+func (e *taskErrors) list() []error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]error(nil), e.errors...)
+}
+
+func TestTaskPoolPanicIsolation(t *gotesting.T) {
+	reported := &taskErrors{}
+	pool := application.NewTaskPool(domain.TaskConfig{Workers: 1, OnError: reported.add})
+
+	failure := errors.New("smtp down")
+	ran := make(chan struct{})
+	err := pool.Submit(
+		func(context.Context) error { panic("boom") },
+		func(context.Context) error { return failure },
+		func(context.Context) error { close(ran); return nil },
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, ran, "the task after a panic") // Same single worker
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	errs := reported.list()
+	if len(errs) != 2 {
+		t.Fatalf("reported %v, want a panic and an error", errs)
+	}
+	var panicked *domain.TaskPanicError
+	if !errors.As(errs[0], &panicked) || panicked.Value != "boom" || len(panicked.Stack) == 0 {
+		t.Errorf("first report %#v, want the recovered panic with its stack", errs[0])
+	}
+	if errs[1] != failure {
+		t.Errorf("second report %v, want %v", errs[1], failure)
+	}
+}
+
+// A panicking task added by a handler does not affect the response or later requests.
+func TestTaskPanicAfterResponse(t *gotesting.T) {
+	reported := make(chan error, 1)
+	app := core.New()
+	app.GET("/", func(c *domain.Context) error {
+		c.AddTask(func(context.Context) error { panic("task failed") })
+		return c.JSON(200, "ok")
+	})
+	app.GET("/next", func(c *domain.Context) error { return c.JSON(200, "next") })
+	server := newAppServer(t, app, domain.TaskConfig{OnError: func(err error) { reported <- err }})
+
+	if resp := send(t, "GET", server.URL+"/", nil, nil); resp.StatusCode != 200 {
+		t.Fatalf("status %d", resp.StatusCode)
+	}
+	select {
+	case err := <-reported:
+		if _, ok := err.(*domain.TaskPanicError); !ok {
+			t.Errorf("reported %v, want the panic", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the panic was not reported")
+	}
+	if resp := send(t, "GET", server.URL+"/next", nil, nil); resp.StatusCode != 200 || resp.Text != "\"next\"\n" {
+		t.Fatalf("after the panic: %d %q", resp.StatusCode, resp.Text)
+	}
+}
+
+func TestTaskPoolDrainsOnShutdown(t *gotesting.T) {
+	reported := &taskErrors{}
+	pool := application.NewTaskPool(domain.TaskConfig{Workers: 1, QueueSize: 10, OnError: reported.add})
+
+	var mu sync.Mutex
+	done := 0
+	for i := 0; i < 5; i++ {
+		err := pool.Submit(func(ctx context.Context) error {
+			time.Sleep(5 * time.Millisecond)
+			mu.Lock()
+			done++
+			mu.Unlock()
+			return ctx.Err()
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if done != 5 {
+		t.Errorf("%d of 5 queued tasks ran before Shutdown returned", done)
+	}
+	if errs := reported.list(); len(errs) != 0 {
+		t.Errorf("tasks saw %v while draining", errs)
+	}
+
+	// Closed pools reject tasks and report it
+	if err := pool.Submit(func(context.Context) error { return nil }); err != application.ErrTaskPoolClosed {
+		t.Errorf("Submit after Shutdown: %v", err)
+	}
+	if errs := reported.list(); len(errs) != 1 || errs[0] != application.ErrTaskPoolClosed {
+		t.Errorf("reported %v, want the rejection", errs)
+	}
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Errorf("second Shutdown: %v", err)
+	}
+}
+
+func TestTaskPoolShutdownTimeout(t *gotesting.T) {
+	pool := application.NewTaskPool(domain.TaskConfig{Workers: 1, OnError: func(error) {}})
+
+	started, canceled := make(chan struct{}), make(chan struct{})
+	pool.Submit(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		close(canceled)
+		return ctx.Err()
+	})
+	waitFor(t, started, "the task to start")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := pool.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Shutdown: %v, want %v", err, context.DeadlineExceeded)
+	}
+	waitFor(t, canceled, "the running task to see its context canceled")
+}
+
+func TestTaskPoolQueueFull(t *gotesting.T) {
+	pool := application.NewTaskPool(domain.TaskConfig{Workers: 1, QueueSize: 1, OnError: func(error) {}})
+
+	running, release := make(chan struct{}), make(chan struct{})
+	blocking := func(context.Context) error {
+		running <- struct{}{}
+		<-release
+		return nil
+	}
+	if err := pool.Submit(blocking); err != nil {
+		t.Fatal(err)
+	}
+	<-running // The worker is busy
+	if err := pool.Submit(blocking); err != nil {
+		t.Fatal(err) // Fills the queue
+	}
+
+	submitted := make(chan error, 1)
+	go func() { submitted <- pool.Submit(func(context.Context) error { return nil }) }()
+	select {
+	case err := <-submitted:
+		t.Fatalf("Submit returned %v with a full queue, want it to block", err)
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	release <- struct{}{} // First task ends; the queued one starts
+	<-running
+	select {
+	case err := <-submitted:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Submit still blocked after the queue had room")
+	}
+	close(release)
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// A submitter blocked on a full queue is released when Shutdown gives up.
+func TestTaskPoolQueueFullShutdown(t *gotesting.T) {
+	reported := &taskErrors{}
+	pool := application.NewTaskPool(domain.TaskConfig{Workers: 1, QueueSize: 1, OnError: reported.add})
+
+	// The task ignores cancellation, so the queue never frees up
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	stuck := func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}
+	pool.Submit(stuck)
+	waitFor(t, started, "the task to start")
+	pool.Submit(func(context.Context) error { return nil })
+
+	submitted := make(chan error, 1)
+	go func() { submitted <- pool.Submit(func(context.Context) error { return nil }) }()
+	time.Sleep(10 * time.Millisecond) // Let Submit block on the queue
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := pool.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Shutdown: %v", err)
+	}
+	select {
+	case err := <-submitted:
+		if err != application.ErrTaskPoolClosed {
+			t.Errorf("blocked Submit: %v, want %v", err, application.ErrTaskPoolClosed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Submit still blocked after Shutdown")
+	}
+}
@@ -0,0 +1,175 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	gotesting "testing"
+	"time"
+
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// Timeout tests check that work a handler does after its deadline never reaches
+// a finished request; run them with -race.
+
+// closingReader reports when the transport closes it.
+type closingReader struct {
+	*strings.Reader
+	once   sync.Once
+	closed chan struct{}
+}
+
+func newClosingReader(text string) *closingReader {
+	return &closingReader{Reader: strings.NewReader(text), closed: make(chan struct{})}
+}
+
+// Close implements io.Closer.
+func (r *closingReader) Close() error {
+	r.once.Do(func() { close(r.closed) })
+	return nil
+}
+
+var lateKey = domain.NewKey[string]("late")
+
+func TestTimedOutHandlerLateWrites(t *gotesting.T) {
+	app := core.New()
+	proceed := make(chan struct{})
+	type lateResult struct {
+		contentErr error
+		stored     bool
+	}
+	results := make(chan lateResult, 1)
+	body := newClosingReader("late report")
+
+	app.GET("/slow", func(c *domain.Context) error {
+		<-c.Context().Done()
+		<-proceed // The 504 is sent and the request released
+
+		c.Set(lateKey, "late")
+		_, stored := lateKey.Get(c)
+		err := c.Attachment(body, "report.txt")
+		_ = c.JSON(200, "too late")
+		results <- lateResult{contentErr: err, stored: stored}
+		return nil
+	}, domain.RouteOptions{Timeout: 20 * time.Millisecond})
+	server := newAppServer(t, app)
+
+	if resp := send(t, "GET", server.URL+"/slow", nil, nil); resp.StatusCode != 504 {
+		t.Fatalf("got %d, want 504", resp.StatusCode)
+	}
+	close(proceed)
+
+	result := <-results
+	if result.stored {
+		t.Error("a value set after the request ended was stored")
+	}
+	if result.contentErr == nil {
+		t.Error("Attachment after the request ended returned nil")
+	}
+	waitFor(t, body.closed, "the late attachment to be closed")
+}
+
+// The handler writes its response right after the deadline while the
+// middleware reads it; they work on separate contexts, so -race stays quiet.
+func TestTimedOutHandlerIsolatedFromMiddlewares(t *gotesting.T) {
+	app := core.New()
+	type seen struct {
+		status int
+		header string
+	}
+	observed := make(chan seen, 1)
+	app.Use(func(next domain.HandlerFunc) domain.HandlerFunc {
+		return func(c *domain.Context) error {
+			err := next(c)
+			c.SetHeader("X-Middleware", "after")
+			observed <- seen{status: c.StatusCode, header: c.Headers["X-Handler"]}
+			return err
+		}
+	})
+
+	proceed := make(chan struct{})
+	streamErrs := make(chan error, 1)
+	app.GET("/slow", func(c *domain.Context) error {
+		<-c.Context().Done()
+		c.SetHeader("X-Handler", "late")
+		_ = c.JSON(201, "too late")
+		c.AddTask(func(context.Context) error { return nil })
+
+		<-proceed // The 504 is sent and the request released
+		streamErrs <- c.Stream("text/plain", func(w io.Writer) error {
+			_, err := io.WriteString(w, "too late")
+			return err
+		})
+		return nil
+	}, domain.RouteOptions{Timeout: 10 * time.Millisecond})
+	app.GET("/fast", func(c *domain.Context) error {
+		c.SetHeader("X-Handler", "in time")
+		return c.JSON(201, "ok")
+	}, domain.RouteOptions{Timeout: time.Second})
+	server := newAppServer(t, app)
+
+	if resp := send(t, "GET", server.URL+"/slow", nil, nil); resp.StatusCode != 504 {
+		t.Fatalf("slow: got %d, want 504", resp.StatusCode)
+	}
+	if got := <-observed; got.status != 200 || got.header != "" {
+		t.Errorf("middleware saw the late handler's response: %+v", got)
+	}
+	close(proceed)
+	if err := <-streamErrs; err == nil {
+		t.Error("Stream after the request ended returned nil")
+	}
+
+	if resp := send(t, "GET", server.URL+"/fast", nil, nil); resp.StatusCode != 201 || resp.Text != "\"ok\"\n" {
+		t.Fatalf("fast: got %d %q", resp.StatusCode, resp.Text)
+	}
+	if got := <-observed; got.status != 201 || got.header != "in time" {
+		t.Errorf("middleware did not see the handler's response: %+v", got)
+	}
+}
+
+func TestContentClosedAfterHandlerError(t *gotesting.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+	}{
+		{"no timeout", 0},
+		{"with timeout", time.Second},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *gotesting.T) {
+			app := core.New()
+			body := newClosingReader("report")
+			app.GET("/report", func(c *domain.Context) error {
+				if err := c.Attachment(body, "report.txt"); err != nil {
+					return err
+				}
+				return errors.New("failed after opening the file")
+			}, domain.RouteOptions{Timeout: test.timeout})
+			server := newAppServer(t, app)
+
+			if resp := send(t, "GET", server.URL+"/report", nil, nil); resp.StatusCode != 500 {
+				t.Fatalf("got %d, want 500", resp.StatusCode)
+			}
+			waitFor(t, body.closed, "the attachment to be closed")
+		})
+	}
+}
+
+func TestContentClosedAfterResponse(t *gotesting.T) {
+	app := core.New()
+	body := newClosingReader("report")
+	app.GET("/report", func(c *domain.Context) error {
+		return c.Attachment(body, "report.txt")
+	}, domain.RouteOptions{Timeout: time.Second})
+	server := newAppServer(t, app)
+
+	if resp := send(t, "GET", server.URL+"/report", nil, nil); resp.Text != "report" {
+		t.Fatalf("got %q, want the attachment", resp.Text)
+	}
+	waitFor(t, body.closed, "the attachment to be closed")
+}
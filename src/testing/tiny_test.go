@@ -2,6 +2,7 @@ package testing
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http/httptest"
 
@@ -50,21 +51,7 @@ func (t *TinyTest) DELETE(path string, handler domain.HandlerFunc, opts ...domai
 
 // registerRoute registers a route for testing.
 func (t *TinyTest) registerRoute(method, path string, handler domain.HandlerFunc, opts ...domain.RouteOptions) {
-	var merged domain.RouteOptions
-	for _, opt := range opts {
-		if opt.Body != nil {
-			merged.Body = opt.Body
-		}
-		if opt.Response != nil {
-			merged.Response = opt.Response
-		}
-		if opt.Summary != "" {
-			merged.Summary = opt.Summary
-		}
-		if len(opt.Tags) > 0 {
-			merged.Tags = opt.Tags
-		}
-	}
+	merged := domain.MergeRouteOptions(opts...)
 
 	_ = t.routeRegistry.Register(method, path, handler, merged)
 }
@@ -101,14 +88,14 @@ func (t *TinyTest) makeRequest(method, path string, body interface{}, expectErro
 	rec := httptest.NewRecorder()
 
 	// Find route
-	route := t.routeRegistry.Find(method, path)
+	route, params := t.routeRegistry.Match(method, req.URL.Path)
 	if route == nil {
 		return &TestResult{StatusCode: 404, Error: domain.NewHTTPException(404, "route not found")}
 	}
 
 	// Create context
 	ctx := &domain.Context{
-		Params:      make(map[string]string),
+		Params:      params,
 		QueryParams: make(map[string]string),
 		Headers:     make(map[string]string),
 		StatusCode:  200,
@@ -131,12 +118,14 @@ func (t *TinyTest) makeRequest(method, path string, body interface{}, expectErro
 	// Store request
 	ctx.Request = req
 	ctx.Response = rec
+	ctx.WithContext(req.Context())
+	defer ctx.Release()
 
 	// Mock binder for tests
 	ctx.Binder = nil // Tests don't use real binding
 
-	// Call handler
-	err := route.Handler(ctx)
+	// Call handler, bounded by the route timeout like the HTTP adapter does
+	err := application.ContextError(application.Timeout(route.Options.Timeout)(route.Handler)(ctx))
 	if err != nil {
 		result := &TestResult{
 			StatusCode: ctx.StatusCode,
@@ -151,6 +140,14 @@ func (t *TinyTest) makeRequest(method, path string, body interface{}, expectErro
 		return result
 	}
 
+	// Run background tasks inline so tests can assert their effects
+	var taskErrors []error
+	for _, task := range ctx.TakeTasks() {
+		if err := task(context.Background()); err != nil {
+			taskErrors = append(taskErrors, err)
+		}
+	}
+
 	// Parse response
 	var responseBody interface{}
 	if len(rec.Body.Bytes()) > 0 {
@@ -164,6 +161,7 @@ func (t *TinyTest) makeRequest(method, path string, body interface{}, expectErro
 		StatusCode: rec.Code,
 		Body:       responseBody,
 		Success:    success && !expectError,
+		TaskErrors: taskErrors,
 	}
 }
 
@@ -173,6 +171,7 @@ type TestResult struct {
 	Body       interface{}
 	Error      error
 	Success    bool
+	TaskErrors []error // Errors returned by background tasks
 }
 
 // Close cleans up test resources.
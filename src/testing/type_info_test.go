@@ -0,0 +1,131 @@
+package testing
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	gotesting "testing"
+
+	"github.com/syntropysoft/syntrogo/src/application"
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+	"github.com/syntropysoft/syntrogo/src/infrastructure"
+)
+
+// Each route type gets its own struct so no other test has cached it already.
+type (
+	preparedBody struct {
+		Name string `json:"name" validate:"required"`
+	}
+	preparedQuery struct {
+		Page int `query:"page"`
+	}
+	preparedHeaders struct {
+		Trace string `header:"X-Trace"`
+	}
+	preparedCookies struct {
+		Session string `cookie:"session"`
+	}
+	preparedForm struct {
+		Title string `form:"title"`
+	}
+	preparedResponse struct {
+		ID int `json:"id"`
+	}
+	warmBody struct {
+		Name string `json:"name"`
+	}
+	warmQuery struct {
+		Page int `query:"page"`
+	}
+)
+
+func TestRouteTypesArePreparedAtRegistration(t *gotesting.T) {
+	types := []interface{}{preparedBody{}, preparedQuery{}, preparedHeaders{}, preparedCookies{}, preparedForm{}, &preparedResponse{}}
+	for _, value := range types {
+		if application.LookupTypeInfo(reflect.TypeOf(value)) != nil {
+			t.Fatalf("%T prepared before registration", value)
+		}
+	}
+
+	app := core.New()
+	noop := func(c *domain.Context) error { return nil }
+	app.POST("/items", noop, domain.RouteOptions{Body: preparedBody{}, Query: preparedQuery{}, Headers: preparedHeaders{}, Cookies: preparedCookies{}, Response: &preparedResponse{}})
+	app.Group("/forms").POST("/upload", noop, domain.RouteOptions{Form: preparedForm{}})
+	for _, value := range types {
+		info := application.LookupTypeInfo(reflect.TypeOf(value))
+		if info == nil || info != application.TypeInfoOf(reflect.TypeOf(value)) {
+			t.Errorf("%T not prepared at registration", value)
+		}
+	}
+}
+
+// Routes registered on a bare registry are prepared by WarmUp.
+func TestWarmUpPreparesRouteTypes(t *gotesting.T) {
+	routes := application.NewRouteRegistry()
+	_ = routes.Register("POST", "/items", func(c *domain.Context) error { return nil },
+		domain.RouteOptions{Body: warmBody{}, Query: &warmQuery{}})
+	adapter := infrastructure.NewHTTPAdapter(routes, application.NewMiddlewareRegistry())
+	if application.LookupTypeInfo(reflect.TypeOf(warmBody{})) != nil {
+		t.Fatal("prepared before WarmUp")
+	}
+
+	adapter.WarmUp()
+	for _, value := range []interface{}{warmBody{}, warmQuery{}} {
+		if application.LookupTypeInfo(reflect.TypeOf(value)) == nil {
+			t.Errorf("%T not prepared by WarmUp", value)
+		}
+	}
+}
+
+// countingEngine marks its output and counts decodes.
+type countingEngine struct {
+	markingEngine
+	decodes *int32
+}
+
+func (e countingEngine) Unmarshal(data []byte, v interface{}) error {
+	atomic.AddInt32(e.decodes, 1)
+	return json.Unmarshal(data, v)
+}
+
+// The app's JSON engine handles BindJSON and c.JSON; union payloads are still
+// split by encoding/json.
+func TestJSONEngineOnBindAndRender(t *gotesting.T) {
+	decodes := new(int32)
+	app := core.New()
+	app.JSONEngine(countingEngine{decodes: decodes})
+	app.POST("/notes", func(c *domain.Context) error {
+		var note Note
+		if err := c.BindJSON(&note); err != nil {
+			return err
+		}
+		return c.JSON(201, note)
+	})
+	app.POST("/checkout", func(c *domain.Context) error {
+		var checkout Checkout
+		if err := c.BindJSON(&checkout); err != nil {
+			return err
+		}
+		return c.JSON(200, describePayment(checkout.Payment))
+	})
+	server := newAppServer(t, app)
+	header := map[string]string{"Content-Type": "application/json"}
+
+	resp := send(t, "POST", server.URL+"/notes", strings.NewReader(`{"text":"hi"}`), header)
+	if want := `{"engine":true,"value":{"text":"hi"}}`; resp.StatusCode != 201 || strings.TrimSpace(resp.Text) != want {
+		t.Errorf("notes: %d %s, want %s", resp.StatusCode, resp.Text, want)
+	}
+	if n := atomic.LoadInt32(decodes); n != 1 {
+		t.Errorf("engine decoded %d bodies, want 1", n)
+	}
+
+	resp = send(t, "POST", server.URL+"/checkout", strings.NewReader(`{"payment":{"type":"card","number":"1"}}`), header)
+	if want := `{"engine":true,"value":"Card(1)"}`; strings.TrimSpace(resp.Text) != want {
+		t.Errorf("checkout: %d %s, want %s", resp.StatusCode, resp.Text, want)
+	}
+	if n := atomic.LoadInt32(decodes); n != 1 {
+		t.Errorf("engine decoded a union payload (%d decodes)", n)
+	}
+}
@@ -0,0 +1,126 @@
+package testing
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	gotesting "testing"
+
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// updateGolden rewrites golden files: go test ./src/testing -run TypeScript -update
+var updateGolden = flag.Bool("update", false, "rewrite golden files")
+
+type Address struct {
+	Street string `json:"street" validate:"required"`
+	City   string `json:"city,omitempty"`
+}
+
+type Customer struct {
+	ID      int64             `json:"id" validate:"required"`
+	Name    string            `json:"name" validate:"required" doc:"Display name"`
+	Status  string            `json:"status" validate:"oneof=active blocked"`
+	Tags    []string          `json:"tags,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Address *Address          `json:"address"`
+}
+
+type CustomerSearch struct {
+	Limit int      `query:"limit"`
+	Tags  []string `query:"tag"`
+}
+
+type RequestTrace struct {
+	RequestID string `header:"X-Request-ID" validate:"required"`
+}
+
+// typeScriptApp covers path parameters named after the client's own arguments
+// and JavaScript reserved words.
+func typeScriptApp() *core.App {
+	noop := func(c *domain.Context) error { return nil }
+	app := core.New()
+	app.GET("/customers/:id", noop, domain.RouteOptions{Response: Customer{}, Summary: "Get a customer"})
+	app.POST("/customers", noop, domain.RouteOptions{Body: Customer{}, Response: Customer{}, ResponseStatus: 201, Headers: RequestTrace{}})
+	app.GET("/search/:query", noop, domain.RouteOptions{Query: CustomerSearch{}, Response: []Customer{}})
+	app.PUT("/files/:body/:headers/:init", noop, domain.RouteOptions{Body: Address{}})
+	app.DELETE("/classes/:class/:new", noop, domain.RouteOptions{Deprecated: true, Description: "Removes a class.\nKept for old clients."})
+	return app
+}
+
+func TestGenerateTypeScriptGolden(t *gotesting.T) {
+	var source bytes.Buffer
+	if err := typeScriptApp().GenerateTypeScript(&source); err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "api.ts.golden")
+	if *updateGolden {
+		if err := os.WriteFile(golden, source.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(source.Bytes(), want) {
+		t.Fatalf("generated TypeScript differs from %s (run with -update to accept):\n%s", golden, source.String())
+	}
+
+	// Type-check with the TypeScript compiler when it is installed
+	tsc, err := exec.LookPath("tsc")
+	if err != nil {
+		t.Skip("tsc not installed")
+	}
+	file := filepath.Join(t.TempDir(), "api.ts")
+	if err := os.WriteFile(file, source.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(tsc, "--noEmit", "--strict", "--target", "es2020", "--lib", "es2020,dom", file).CombinedOutput()
+	if err != nil {
+		t.Fatalf("tsc: %v\n%s", err, out)
+	}
+}
+
+// `syntrogo gen ts` reads YAML specs as well as JSON.
+func TestGenTSCommandReadsYAML(t *gotesting.T) {
+	if gotesting.Short() {
+		t.Skip("builds the syntrogo command")
+	}
+	dir := t.TempDir()
+	command := filepath.Join(dir, "syntrogo")
+	if out, err := exec.Command("go", "build", "-o", command, "github.com/syntropysoft/syntrogo/cmd/syntrogo").CombinedOutput(); err != nil {
+		t.Fatalf("build: %v\n%s", err, out)
+	}
+
+	app := typeScriptApp()
+	var want bytes.Buffer
+	if err := app.GenerateTypeScript(&want); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []core.SpecFormat{core.SpecJSON, core.SpecYAML} {
+		var spec bytes.Buffer
+		if err := app.WriteSpec(&spec, format); err != nil {
+			t.Fatal(err)
+		}
+		specPath, outPath := filepath.Join(dir, "openapi"), filepath.Join(dir, "api.ts")
+		if err := os.WriteFile(specPath, spec.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if out, err := exec.Command(command, "gen", "ts", "-spec", specPath, "-out", outPath).CombinedOutput(); err != nil {
+			t.Fatalf("format %v: %v\n%s", format, err, out)
+		}
+		got, err := os.ReadFile(outPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want.Bytes()) {
+			t.Errorf("format %v: gen ts output differs from App.GenerateTypeScript:\n%s", format, got)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package testing
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	gotesting "testing"
+
+	api "github.com/syntropysoft/syntrogo"
+	"github.com/syntropysoft/syntrogo/src/application"
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+)
+
+// PaymentMethod is a tagged union: Card by value, BankTransfer by pointer.
+type PaymentMethod interface {
+	paymentKind() string
+}
+
+type Card struct {
+	Type   string `json:"type"`
+	Number string `json:"number"`
+}
+
+type BankTransfer struct {
+	Type string `json:"type"`
+	IBAN string `json:"iban"`
+}
+
+func (Card) paymentKind() string          { return "card" }
+func (*BankTransfer) paymentKind() string { return "bank_transfer" }
+
+// Checkout nests the union in a field, a slice, a map and behind a pointer.
+type Checkout struct {
+	Payment  PaymentMethod            `json:"payment"`
+	Backups  []PaymentMethod          `json:"backups,omitempty"`
+	Wallets  map[string]PaymentMethod `json:"wallets,omitempty"`
+	Fallback *Refund                  `json:"fallback,omitempty"`
+}
+
+type Refund struct {
+	Method PaymentMethod `json:"method"`
+}
+
+func init() {
+	api.OneOf[PaymentMethod]("type", map[string]PaymentMethod{"card": Card{}, "bank_transfer": &BankTransfer{}})
+}
+
+// describePayment renders a decoded union value with its concrete Go type.
+func describePayment(method PaymentMethod) string {
+	switch m := method.(type) {
+	case Card:
+		return "Card(" + m.Number + ")"
+	case *BankTransfer:
+		return "*BankTransfer(" + m.IBAN + ")"
+	case nil:
+		return "nil"
+	}
+	return fmt.Sprintf("%T", method)
+}
+
+// unionApp echoes the concrete types BindJSON chose.
+func unionApp() *core.App {
+	app := core.New()
+	app.POST("/checkout", func(c *domain.Context) error {
+		var checkout Checkout
+		if err := c.BindJSON(&checkout); err != nil {
+			return err
+		}
+		parts := []string{describePayment(checkout.Payment)}
+		for _, backup := range checkout.Backups {
+			parts = append(parts, describePayment(backup))
+		}
+		for _, name := range []string{"home", "work"} {
+			if method, ok := checkout.Wallets[name]; ok {
+				parts = append(parts, name+"="+describePayment(method))
+			}
+		}
+		if checkout.Fallback != nil {
+			parts = append(parts, "fallback="+describePayment(checkout.Fallback.Method))
+		}
+		return c.Blob(200, "text/plain", []byte(strings.Join(parts, " ")))
+	}, domain.RouteOptions{Body: Checkout{}})
+	return app
+}
+
+func TestUnionBindJSON(t *gotesting.T) {
+	server := newAppServer(t, unionApp())
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		want   string
+	}{
+		{"value variant", `{"payment":{"type":"card","number":"4242"}}`, 200, "Card(4242)"},
+		{"pointer variant", `{"payment":{"type":"bank_transfer","iban":"DE89"}}`, 200, "*BankTransfer(DE89)"},
+		{"null union", `{"payment":null}`, 200, "nil"},
+		{"slice", `{"payment":{"type":"card","number":"1"},"backups":[{"type":"bank_transfer","iban":"FR76"},{"type":"card","number":"2"}]}`,
+			200, "Card(1) *BankTransfer(FR76) Card(2)"},
+		{"map", `{"payment":{"type":"card","number":"1"},"wallets":{"home":{"type":"card","number":"3"},"work":{"type":"bank_transfer","iban":"NL91"}}}`,
+			200, "Card(1) home=Card(3) work=*BankTransfer(NL91)"},
+		{"pointer", `{"payment":{"type":"card","number":"1"},"fallback":{"method":{"type":"bank_transfer","iban":"ES91"}}}`,
+			200, "Card(1) fallback=*BankTransfer(ES91)"},
+		{"missing discriminator", `{"payment":{"number":"4242"}}`, 400, `missing \"type\" discriminator`},
+		{"non-string discriminator", `{"payment":{"type":1}}`, 400, `missing \"type\" discriminator`},
+		{"unknown discriminator", `{"payment":{"type":"cash"}}`, 400, `unknown type \"cash\", expected one of: bank_transfer, card`},
+		{"unknown in slice", `{"payment":{"type":"card"},"backups":[{"type":"cash"}]}`, 400, `unknown type \"cash\"`},
+		{"unknown in map", `{"payment":{"type":"card"},"wallets":{"home":{}}}`, 400, `missing \"type\" discriminator`},
+	}
+
+	for _, test := range tests {
+		resp := send(t, "POST", server.URL+"/checkout", strings.NewReader(test.body), map[string]string{"Content-Type": "application/json"})
+		if resp.StatusCode != test.status || !strings.Contains(resp.Text, test.want) {
+			t.Errorf("%s: %d %s, want %d containing %s", test.name, resp.StatusCode, resp.Text, test.status, test.want)
+		}
+	}
+}
+
+func TestUnionSchema(t *gotesting.T) {
+	app := core.New()
+	app.POST("/checkout", func(c *domain.Context) error { return nil }, domain.RouteOptions{Body: Checkout{}})
+	spec := specOf(t, app)
+
+	union := `{"discriminator":{"mapping":{"bank_transfer":"#/components/schemas/BankTransfer","card":"#/components/schemas/Card"},"propertyName":"type"},` +
+		`"oneOf":[{"$ref":"#/components/schemas/BankTransfer"},{"$ref":"#/components/schemas/Card"}]}`
+	body := spec["paths"].(map[string]interface{})["/checkout"].(map[string]interface{})["post"].(map[string]interface{})["requestBody"]
+	properties := body.(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})["properties"].(map[string]interface{})
+	for name, want := range map[string]string{
+		"payment":  union,
+		"backups":  `{"items":` + union + `,"type":"array"}`,
+		"wallets":  `{"additionalProperties":` + union + `,"type":"object"}`,
+		"fallback": `{"nullable":true,"properties":{"method":` + union + `},"title":"Refund","type":"object"}`,
+	} {
+		if got := compactJSON(t, properties[name]); got != want {
+			t.Errorf("property %s:\n%s\nwant\n%s", name, got, want)
+		}
+	}
+
+	// Variants pin the discriminator to their own value
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	for name, want := range map[string]string{
+		"Card":         `{"properties":{"number":{"type":"string"},"type":{"enum":["card"],"type":"string"}},"required":["type"],"title":"Card","type":"object"}`,
+		"BankTransfer": `{"properties":{"iban":{"type":"string"},"type":{"enum":["bank_transfer"],"type":"string"}},"required":["type"],"title":"BankTransfer","type":"object"}`,
+	} {
+		if got := compactJSON(t, schemas[name]); got != want {
+			t.Errorf("component %s:\n%s\nwant\n%s", name, got, want)
+		}
+	}
+}
+
+// compactJSON marshals a decoded spec fragment with sorted keys.
+func compactJSON(t *gotesting.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// Bad registrations panic at startup and leave the registry untouched.
+func TestOneOfPanics(t *gotesting.T) {
+	tests := []struct {
+		name     string
+		register func()
+		want     string
+	}{
+		{"not an interface", func() { api.OneOf[Card]("type", map[string]Card{"card": {}}) }, "union type must be an interface"},
+		{"no discriminator", func() { api.OneOf[fmt.Stringer]("", map[string]fmt.Stringer{"x": nil}) }, "requires a discriminator property"},
+		{"no variants", func() { api.OneOf[fmt.Stringer]("kind", nil) }, "requires at least one variant"},
+		{"nil variant", func() { api.OneOf[fmt.Stringer]("kind", map[string]fmt.Stringer{"x": nil}) }, `variant "x" (<nil>) does not implement it`},
+	}
+
+	for _, test := range tests {
+		func() {
+			defer func() {
+				got := fmt.Sprint(recover())
+				if !strings.HasPrefix(got, "syntrogo: ") || !strings.Contains(got, test.want) {
+					t.Errorf("%s: panic %q, want %q", test.name, got, test.want)
+				}
+			}()
+			test.register()
+		}()
+	}
+	if application.LookupUnion(reflect.TypeOf((*fmt.Stringer)(nil)).Elem()) != nil {
+		t.Error("failed registration was stored")
+	}
+
+	// A variant that does not implement the interface is rejected by RegisterUnion
+	iface := reflect.TypeOf((*PaymentMethod)(nil)).Elem()
+	err := application.RegisterUnion(iface, "type", map[string]reflect.Type{"transfer": reflect.TypeOf(BankTransfer{})})
+	if err == nil || !strings.Contains(err.Error(), `variant "transfer"`) {
+		t.Errorf("value BankTransfer with pointer methods: %v", err)
+	}
+}
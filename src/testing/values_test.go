@@ -0,0 +1,150 @@
+package testing
+
+import (
+	"fmt"
+	"strings"
+	gotesting "testing"
+
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+	"github.com/syntropysoft/syntrogo/src/security"
+)
+
+var (
+	userKey  = domain.NewKey[string]("user")
+	otherKey = domain.NewKey[string]("user") // Same name, different key
+	countKey = domain.NewKey[int]("count")
+)
+
+func TestContextValues(t *gotesting.T) {
+	ctx := &domain.Context{}
+	if _, ok := userKey.Get(ctx); ok {
+		t.Error("Get on an empty context found a value")
+	}
+
+	userKey.Set(ctx, "ada")
+	countKey.Set(ctx, 2)
+	if user, ok := userKey.Get(ctx); !ok || user != "ada" {
+		t.Errorf("userKey: %q, %v", user, ok)
+	}
+	if _, ok := otherKey.Get(ctx); ok {
+		t.Error("keys with the same name collided")
+	}
+	if count, ok := domain.GetValue[int](ctx, countKey); !ok || count != 2 {
+		t.Errorf("GetValue: %d, %v", count, ok)
+	}
+	if _, ok := domain.GetValue[string](ctx, countKey); ok {
+		t.Error("GetValue with the wrong type succeeded")
+	}
+
+	// Plain keys work too
+	ctx.Set("request-id", "r1")
+	if value, ok := ctx.Get("request-id"); !ok || value != "r1" {
+		t.Errorf("Get: %v, %v", value, ok)
+	}
+	if value := ctx.MustGet(userKey); value != "ada" {
+		t.Errorf("MustGet: %v", value)
+	}
+	if userKey.String() != "user" {
+		t.Errorf("String: %q", userKey.String())
+	}
+}
+
+func TestMustGetPanicsOnMissingKey(t *gotesting.T) {
+	defer func() {
+		if got := fmt.Sprint(recover()); got != "domain: no value for key user" {
+			t.Errorf("panic %q", got)
+		}
+	}()
+	(&domain.Context{}).MustGet(otherKey)
+}
+
+func TestReleaseRunsCleanupsAndDropsValues(t *gotesting.T) {
+	ctx := &domain.Context{}
+	order := []string{}
+	ctx.Cleanup(func() { order = append(order, "first") })
+	ctx.Cleanup(func() { order = append(order, "second") })
+	userKey.Set(ctx, "ada")
+
+	ctx.Release()
+	if strings.Join(order, ",") != "second,first" {
+		t.Errorf("cleanup order %v", order)
+	}
+	if _, ok := userKey.Get(ctx); ok {
+		t.Error("value readable after Release")
+	}
+	userKey.Set(ctx, "late")
+	if _, ok := userKey.Get(ctx); ok {
+		t.Error("Set after Release was stored")
+	}
+	ctx.Cleanup(func() { order = append(order, "late") })
+	if order[len(order)-1] != "late" {
+		t.Error("cleanup registered after Release did not run immediately")
+	}
+
+	// A store returned to the pool starts empty for the next context
+	next := &domain.Context{}
+	countKey.Set(next, 1)
+	if _, ok := userKey.Get(next); ok {
+		t.Error("pooled store kept a value from an earlier context")
+	}
+}
+
+// Values never leak between requests sharing pooled stores.
+func TestValuesArePerRequest(t *gotesting.T) {
+	app := core.New()
+	app.Use(func(next domain.HandlerFunc) domain.HandlerFunc {
+		return func(c *domain.Context) error {
+			if c.Query("user") != "" {
+				userKey.Set(c, c.Query("user"))
+			}
+			return next(c)
+		}
+	})
+	app.GET("/me", func(c *domain.Context) error {
+		user, ok := userKey.Get(c)
+		if !ok {
+			user = "anonymous"
+		}
+		return c.JSON(200, user)
+	})
+	server := newAppServer(t, app)
+
+	for i := 0; i < 20; i++ {
+		query, want := "", `"anonymous"`
+		if i%2 == 0 {
+			query, want = fmt.Sprintf("?user=u%d", i), fmt.Sprintf(`"u%d"`, i)
+		}
+		if resp := send(t, "GET", server.URL+"/me"+query, nil, nil); strings.TrimSpace(resp.Text) != want {
+			t.Fatalf("request %d: %s, want %s", i, resp.Text, want)
+		}
+	}
+}
+
+func TestSecurityMiddlewaresSetKeys(t *gotesting.T) {
+	app := core.New()
+	credentials := func(c *domain.Context) error {
+		token, hasToken := security.BearerTokenKey.Get(c)
+		key, hasKey := security.APIKeyKey.Get(c)
+		return c.JSON(200, fmt.Sprintf("token=%s/%v key=%s/%v", token, hasToken, key, hasKey))
+	}
+	app.Group("/bearer").Secure(security.BearerToken("secret")).GET("/me", credentials)
+	app.Group("/apikey").Secure(security.APIKey("sk_1")).GET("/me", credentials)
+	app.GET("/open", credentials)
+	server := newAppServer(t, app)
+
+	tests := []struct {
+		path   string
+		header map[string]string
+		want   string
+	}{
+		{"/bearer/me", map[string]string{"Authorization": "Bearer secret"}, `"token=secret/true key=/false"`},
+		{"/apikey/me", map[string]string{"X-API-Key": "sk_1"}, `"token=/false key=sk_1/true"`},
+		{"/open", map[string]string{"Authorization": "Bearer secret", "X-API-Key": "sk_1"}, `"token=/false key=/false"`},
+	}
+	for _, test := range tests {
+		if resp := send(t, "GET", server.URL+test.path, nil, test.header); strings.TrimSpace(resp.Text) != test.want {
+			t.Errorf("%s: %d %s, want %s", test.path, resp.StatusCode, resp.Text, test.want)
+		}
+	}
+}
@@ -0,0 +1,394 @@
+package testing
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	gotesting "testing"
+	"time"
+
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+	"github.com/syntropysoft/syntrogo/src/infrastructure"
+	"github.com/syntropysoft/syntrogo/src/security"
+)
+
+// WebSocket tests run the adapter on a local server and speak RFC 6455 with a
+// minimal client, so the handshake and framing are checked byte for byte.
+
+type chatMessage struct {
+	Room string `json:"room" validate:"required"`
+	Text string `json:"text" validate:"required,max=20"`
+}
+
+// wsTestClient is a raw WebSocket client: it masks what it sends and returns
+// frames as received.
+type wsTestClient struct {
+	t      *gotesting.T
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// newWSServer serves an app's routes on a local listener.
+func newWSServer(t *gotesting.T, app *core.App) (*httptest.Server, *infrastructure.HTTPAdapter) {
+	adapter := infrastructure.NewHTTPAdapter(app.GetRouteRegistry(), app.GetMiddlewareRegistry())
+	server := httptest.NewServer(adapter)
+	t.Cleanup(server.Close)
+	return server, adapter
+}
+
+// dialWS performs the opening handshake. It returns the client after a 101 and
+// the status code otherwise.
+func dialWS(t *gotesting.T, server *httptest.Server, path string, header http.Header) (*wsTestClient, int) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	nonce := make([]byte, 16)
+	_, _ = rand.Read(nonce)
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req, _ := http.NewRequest("GET", server.URL+path, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Connection", "keep-alive, Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	if req.Header.Get("Sec-WebSocket-Version") == "" {
+		req.Header.Set("Sec-WebSocket-Version", "13")
+	}
+	if err := req.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, resp.StatusCode
+	}
+	if got, want := resp.Header.Get("Sec-WebSocket-Accept"), acceptFor(key); got != want {
+		t.Fatalf("Sec-WebSocket-Accept = %q, want %q", got, want)
+	}
+	return &wsTestClient{t: t, conn: conn, reader: reader}, resp.StatusCode
+}
+
+// acceptFor computes Sec-WebSocket-Accept independently of the adapter.
+func acceptFor(key string) string {
+	h := sha1.Sum([]byte(key + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// send writes a masked frame.
+func (c *wsTestClient) send(opcode byte, payload []byte, fin bool) {
+	c.t.Helper()
+	first := opcode
+	if fin {
+		first |= 0x80
+	}
+	frame := []byte{first}
+	switch {
+	case len(payload) <= 125:
+		frame = append(frame, 0x80|byte(len(payload)))
+	case len(payload) <= 0xffff:
+		frame = binary.BigEndian.AppendUint16(append(frame, 0x80|126), uint16(len(payload)))
+	default:
+		frame = binary.BigEndian.AppendUint64(append(frame, 0x80|127), uint64(len(payload)))
+	}
+	mask := []byte{0x12, 0x34, 0x56, 0x78}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// sendJSON sends v as a text message.
+func (c *wsTestClient) sendJSON(v interface{}) {
+	data, _ := json.Marshal(v)
+	c.send(0x1, data, true)
+}
+
+// receive reads one server frame, which must not be masked.
+func (c *wsTestClient) receive() (byte, []byte) {
+	c.t.Helper()
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		c.t.Fatal(err)
+	}
+	if header[1]&0x80 != 0 {
+		c.t.Fatal("server frame is masked")
+	}
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var extended [2]byte
+		_, _ = io.ReadFull(c.reader, extended[:])
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		_, _ = io.ReadFull(c.reader, extended[:])
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		c.t.Fatal(err)
+	}
+	return header[0] & 0x0f, payload
+}
+
+// receiveData skips pings and returns the next data or close frame.
+func (c *wsTestClient) receiveData() (byte, []byte) {
+	c.t.Helper()
+	for {
+		opcode, payload := c.receive()
+		if opcode != 0x9 {
+			return opcode, payload
+		}
+	}
+}
+
+// expectClose reads until a close frame and checks its code.
+func (c *wsTestClient) expectClose(code int) {
+	c.t.Helper()
+	opcode, payload := c.receiveData()
+	if opcode != 0x8 || len(payload) < 2 {
+		c.t.Fatalf("frame %#x %q, want close %d", opcode, payload, code)
+	}
+	if got := int(binary.BigEndian.Uint16(payload)); got != code {
+		c.t.Fatalf("close code %d (%q), want %d", got, payload[2:], code)
+	}
+}
+
+// closePayload builds a close frame payload.
+func closePayload(code int, reason string) []byte {
+	return append(binary.BigEndian.AppendUint16(nil, uint16(code)), reason...)
+}
+
+func TestWebSocketJSONEcho(t *gotesting.T) {
+	closed := make(chan error, 1)
+	app := core.New()
+	app.WS("/chat/:room", func(c *domain.Context, conn domain.WSConn) error {
+		for {
+			var msg chatMessage
+			err := conn.ReadJSON(&msg)
+			var httpErr *domain.HTTPException
+			switch {
+			case errors.As(err, &httpErr):
+				if err := conn.WriteJSON(map[string]interface{}{"status": httpErr.StatusCode}); err != nil {
+					return err
+				}
+				continue
+			case err != nil:
+				closed <- err
+				return err
+			}
+			msg.Room = c.Param("room") + "/" + msg.Room
+			if err := conn.WriteJSON(msg); err != nil {
+				return err
+			}
+		}
+	}, domain.RouteOptions{Body: chatMessage{}, Response: chatMessage{}})
+	server, _ := newWSServer(t, app)
+
+	client, status := dialWS(t, server, "/chat/general", nil)
+	if client == nil {
+		t.Fatalf("handshake status %d", status)
+	}
+
+	client.sendJSON(chatMessage{Room: "a", Text: "hello"})
+	if opcode, payload := client.receiveData(); opcode != 0x1 || string(payload) != `{"room":"general/a","text":"hello"}` {
+		t.Fatalf("echo = %#x %s", opcode, payload)
+	}
+
+	// Fragmented message with a ping in between
+	client.send(0x1, []byte(`{"room":"b",`), false)
+	client.send(0x9, []byte("are you there"), true)
+	if opcode, payload := client.receive(); opcode != 0xA || string(payload) != "are you there" {
+		t.Fatalf("pong = %#x %q", opcode, payload)
+	}
+	client.send(0x0, []byte(`"text":"split"}`), true)
+	if _, payload := client.receiveData(); string(payload) != `{"room":"general/b","text":"split"}` {
+		t.Fatalf("fragmented echo = %s", payload)
+	}
+
+	// Invalid messages keep the connection open
+	client.sendJSON(chatMessage{Room: "c", Text: strings.Repeat("x", 21)})
+	if _, payload := client.receiveData(); string(payload) != `{"status":422}` {
+		t.Fatalf("validation reply = %s", payload)
+	}
+	client.send(0x1, []byte("{"), true)
+	if _, payload := client.receiveData(); string(payload) != `{"status":400}` {
+		t.Fatalf("invalid JSON reply = %s", payload)
+	}
+
+	// Client-initiated close is echoed and ends the handler's reads
+	client.send(0x8, closePayload(domain.WSCloseNormal, "bye"), true)
+	client.expectClose(domain.WSCloseNormal)
+	select {
+	case err := <-closed:
+		var closeErr *domain.WSCloseError
+		if !errors.As(err, &closeErr) || closeErr.Code != domain.WSCloseNormal || closeErr.Reason != "bye" {
+			t.Fatalf("handler read error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler did not see the close")
+	}
+}
+
+func TestWebSocketMiddlewaresAndHandshake(t *gotesting.T) {
+	app := core.New()
+	secure := app.Group("/secure").Secure(security.BearerToken("secret"))
+	secure.WS("/feed", func(c *domain.Context, conn domain.WSConn) error {
+		return conn.WriteMessage(domain.WSText, []byte("welcome "+conn.Subprotocol()))
+	}, domain.RouteOptions{WebSocket: &domain.WSOptions{Subprotocols: []string{"v2", "v1"}}})
+	app.WS("/partners", func(c *domain.Context, conn domain.WSConn) error {
+		return nil
+	}, domain.RouteOptions{WebSocket: &domain.WSOptions{Origins: []string{"https://partner.example.com"}}})
+	server, _ := newWSServer(t, app)
+
+	cases := []struct {
+		name   string
+		path   string
+		header http.Header
+		status int
+	}{
+		{"missing token", "/secure/feed", nil, 401},
+		{"foreign origin", "/secure/feed", http.Header{"Authorization": {"Bearer secret"}, "Origin": {"https://evil.example.com"}}, 403},
+		{"old version", "/secure/feed", http.Header{"Authorization": {"Bearer secret"}, "Sec-Websocket-Version": {"8"}}, 426},
+		{"origin not listed", "/partners", http.Header{"Origin": {"https://other.example.com"}}, 403},
+		{"listed origin", "/partners", http.Header{"Origin": {"https://partner.example.com"}}, 101},
+		{"same origin", "/secure/feed", http.Header{"Authorization": {"Bearer secret"}, "Origin": {server.URL}}, 101},
+	}
+	for _, tc := range cases {
+		if _, status := dialWS(t, server, tc.path, tc.header); status != tc.status {
+			t.Errorf("%s: status %d, want %d", tc.name, status, tc.status)
+		}
+	}
+
+	// A plain GET is not upgraded
+	resp, err := http.Get(server.URL + "/partners")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 426 {
+		t.Errorf("plain GET status %d, want 426", resp.StatusCode)
+	}
+
+	client, _ := dialWS(t, server, "/secure/feed", http.Header{
+		"Authorization":          {"Bearer secret"},
+		"Sec-Websocket-Protocol": {"v1, v2"},
+	})
+	if _, payload := client.receiveData(); string(payload) != "welcome v1" {
+		t.Fatalf("subprotocol greeting = %q", payload)
+	}
+	client.expectClose(domain.WSCloseNormal)
+}
+
+func TestWebSocketLimitsAndErrors(t *gotesting.T) {
+	app := core.New()
+	app.WS("/upload", func(c *domain.Context, conn domain.WSConn) error {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return err
+			}
+		}
+	}, domain.RouteOptions{WebSocket: &domain.WSOptions{ReadLimit: 1024}})
+	app.WS("/fail", func(c *domain.Context, conn domain.WSConn) error {
+		return errors.New("database down")
+	})
+	app.WS("/kick", func(c *domain.Context, conn domain.WSConn) error {
+		return &domain.WSCloseError{Code: 4001, Reason: "kicked"}
+	})
+	server, _ := newWSServer(t, app)
+
+	client, _ := dialWS(t, server, "/upload", nil)
+	client.send(0x2, make([]byte, 1000), true)
+	client.send(0x2, make([]byte, 1025), true)
+	client.expectClose(domain.WSCloseTooBig)
+
+	client, _ = dialWS(t, server, "/upload", nil)
+	client.send(0x1, []byte{0xff, 0xfe}, true)
+	client.expectClose(domain.WSCloseInvalidPayload)
+
+	client, _ = dialWS(t, server, "/fail", nil)
+	client.expectClose(domain.WSCloseInternalError)
+
+	client, _ = dialWS(t, server, "/kick", nil)
+	client.expectClose(4001)
+}
+
+func TestWebSocketKeepAlive(t *gotesting.T) {
+	ended := make(chan error, 1)
+	app := core.New()
+	app.WS("/live", func(c *domain.Context, conn domain.WSConn) error {
+		<-conn.Done()
+		_, _, err := conn.ReadMessage()
+		ended <- c.Context().Err()
+		return err
+	}, domain.RouteOptions{WebSocket: &domain.WSOptions{PingInterval: 50 * time.Millisecond, PongTimeout: 50 * time.Millisecond}})
+	server, _ := newWSServer(t, app)
+
+	// Answering pings keeps the connection open past the pong timeout
+	client, _ := dialWS(t, server, "/live", nil)
+	for i := 0; i < 5; i++ {
+		opcode, payload := client.receive()
+		if opcode != 0x9 {
+			t.Fatalf("frame %#x, want ping", opcode)
+		}
+		client.send(0xA, payload, true)
+	}
+	select {
+	case <-ended:
+		t.Fatal("connection dropped while the client answered pings")
+	default:
+	}
+
+	// A silent client is dropped and the handler's context canceled
+	select {
+	case err := <-ended:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("handler context error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("silent client was not dropped")
+	}
+}
+
+func TestWebSocketShutdown(t *gotesting.T) {
+	app := core.New()
+	app.WS("/live", func(c *domain.Context, conn domain.WSConn) error {
+		_, _, err := conn.ReadMessage()
+		return err
+	})
+	server, adapter := newWSServer(t, app)
+
+	client, _ := dialWS(t, server, "/live", nil)
+	done := make(chan error, 1)
+	go func() { done <- adapter.Shutdown(context.Background()) }()
+	client.expectClose(domain.WSCloseGoingAway)
+	client.send(0x8, closePayload(domain.WSCloseGoingAway, ""), true)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
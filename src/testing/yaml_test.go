@@ -0,0 +1,195 @@
+package testing
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	gotesting "testing"
+
+	"github.com/syntropysoft/syntrogo/src/core"
+	"github.com/syntropysoft/syntrogo/src/domain"
+	"github.com/syntropysoft/syntrogo/src/infrastructure"
+)
+
+// handWrittenSpec uses the YAML features people put in real specs.
+const handWrittenSpec = `# Orders API
+openapi: 3.0.3
+info:
+  title: 'Orders: it''s an API'
+  version: "1.0"
+  description: |
+    Line one.
+      Indented line.
+    Last line.
+  summary: >-
+    Folded
+    text.
+paths:
+  /orders/{id}:
+    get:
+      tags: [orders, "read only"]
+      parameters:
+        - &idParam
+          name: id
+          in: path
+          required: true
+          schema: {type: string, pattern: "^[a-z]+\\d*$"}
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  matrix:
+                    type: array
+                    items:
+                      type: array
+                      items: {type: number}
+                    example:
+                      - [1, 2.5]
+                      - - 3
+                        - -4
+                  note: {type: string, nullable: true, example: ~}
+    delete:
+      parameters:
+        - *idParam
+      responses:
+        "204": {description: Deleted}
+`
+
+const handWrittenJSON = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "Orders: it's an API",
+    "version": "1.0",
+    "description": "Line one.\n  Indented line.\nLast line.\n",
+    "summary": "Folded text."
+  },
+  "paths": {
+    "/orders/{id}": {
+      "get": {
+        "tags": ["orders", "read only"],
+        "parameters": [
+          {"name": "id", "in": "path", "required": true, "schema": {"type": "string", "pattern": "^[a-z]+\\d*$"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {"application/json": {"schema": {
+              "type": "object",
+              "properties": {
+                "matrix": {"type": "array", "items": {"type": "array", "items": {"type": "number"}}, "example": [[1, 2.5], [3, -4]]},
+                "note": {"type": "string", "nullable": true, "example": null}
+              }
+            }}}
+          }
+        }
+      },
+      "delete": {
+        "parameters": [
+          {"name": "id", "in": "path", "required": true, "schema": {"type": "string", "pattern": "^[a-z]+\\d*$"}}
+        ],
+        "responses": {"204": {"description": "Deleted"}}
+      }
+    }
+  }
+}`
+
+func TestDecodeYAMLSpec(t *gotesting.T) {
+	got, err := infrastructure.DecodeSpec([]byte(handWrittenSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := infrastructure.DecodeSpec([]byte(handWrittenJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.MarshalIndent(got, "", "  ")
+		t.Fatalf("decoded spec:\n%s\nwant:\n%s", gotJSON, handWrittenJSON)
+	}
+}
+
+func TestDecodeYAMLErrors(t *gotesting.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string // Substring of the error
+	}{
+		{"multiple documents", "a: 1\n---\nb: 2\n", "multiple documents"},
+		{"bad indentation", "a:\n  b: 1\n c: 2\n", "did not find expected key"},
+		{"unknown alias", "a: *missing\n", "unknown anchor"},
+		{"unclosed flow", "a: [1, 2\n", "line"},
+		{"top-level sequence", "- a\n- b\n", "mapping"},
+	}
+	for _, test := range tests {
+		_, err := infrastructure.DecodeSpec([]byte(test.yaml))
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.name, err, test.want)
+		}
+	}
+
+	if document, err := infrastructure.DecodeYAML([]byte("# only a comment\n")); document != nil || err != nil {
+		t.Errorf("empty document: %v, %v", document, err)
+	}
+}
+
+// Strings the encoder must quote to read back unchanged.
+func TestYAMLRoundTripScalars(t *gotesting.T) {
+	value := map[string]interface{}{
+		"reserved":  []interface{}{"yes", "No", "on", "null", "~", "true", "False"},
+		"numeric":   []interface{}{"1", "1.0", "0x1F", "1e3", "-2", ".5"},
+		"syntax":    []interface{}{"a: b", "# c", "- d", "[e]", "{f}", "*g", "&h", "!i", "|", ">", "'j'", `"k"`, "l #m", "%n", "@o", "`p"},
+		"spacing":   []interface{}{"", " lead", "trail ", "two\nlines", "tab\there", "\r\n", "ünïcode ✓"},
+		"numbers":   []interface{}{0.0, -1.0, 2.5, 1e21, 123456789.0},
+		"nested":    []interface{}{[]interface{}{[]interface{}{"deep"}}, []interface{}{}, map[string]interface{}{}},
+		"200":       map[string]interface{}{"description": "status code key"},
+		"key: with": "colon",
+		"nothing":   nil,
+		"flag":      false,
+	}
+
+	var buf bytes.Buffer
+	if err := infrastructure.EncodeYAML(&buf, value); err != nil {
+		t.Fatal(err)
+	}
+	got, err := infrastructure.DecodeYAML(buf.Bytes())
+	if err != nil {
+		t.Fatalf("%v\n%s", err, buf.String())
+	}
+	if !reflect.DeepEqual(got, value) {
+		t.Fatalf("round trip changed the value:\n%s", buf.String())
+	}
+}
+
+// A generated spec written as YAML reads back as the JSON spec.
+func TestYAMLRoundTripSpec(t *gotesting.T) {
+	type Order struct {
+		ID    string   `json:"id" validate:"required"`
+		Items []string `json:"items" validate:"min=1"`
+		Note  string   `json:"note,omitempty" description:"Free text: may contain 'quotes' and #hashes"`
+	}
+	app := core.New()
+	app.GET("/orders/:id", func(c *domain.Context) error { return c.JSON(200, Order{}) },
+		domain.RouteOptions{Summary: "Get an order", Description: "Multi-line\ndescription: with colon", Response: Order{}})
+	app.POST("/orders", func(c *domain.Context) error { return c.JSON(201, Order{}) },
+		domain.RouteOptions{Body: Order{}, Response: Order{}})
+
+	var yamlSpec bytes.Buffer
+	if err := app.WriteSpec(&yamlSpec, core.SpecYAML); err != nil {
+		t.Fatal(err)
+	}
+	got, err := infrastructure.DecodeSpec(yamlSpec.Bytes())
+	if err != nil {
+		t.Fatalf("%v\n%s", err, yamlSpec.String())
+	}
+	if want := specOf(t, app); !reflect.DeepEqual(got, want) {
+		t.Fatalf("YAML spec differs from the JSON spec:\n%s", yamlSpec.String())
+	}
+	if err := app.VerifySpec(yamlSpec.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+}
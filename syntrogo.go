@@ -23,6 +23,10 @@
 package syntrogo
 
 import (
+	"reflect"
+	"time"
+
+	"github.com/syntropysoft/syntrogo/src/application"
 	"github.com/syntropysoft/syntrogo/src/core"
 	"github.com/syntropysoft/syntrogo/src/domain"
 )
@@ -38,11 +42,87 @@ type (
 	Context     = domain.Context
 	Handler     = domain.HandlerFunc
 	RouteOptions = domain.RouteOptions
+	SpecFormat  = core.SpecFormat
+	SpecChange  = application.SpecChange
+	MockConfig  = domain.MockConfig
+	Scope       = domain.Scope
+	Task        = domain.Task
+	TaskConfig  = domain.TaskConfig
+	FormFile    = domain.FormFile
+	UploadLimits = domain.UploadLimits
+	Codec       = domain.Codec
+	JSONEngine  = domain.JSONEngine
+	Event       = domain.Event
+	EventStream = domain.EventStream
+	WSHandler   = domain.WSHandler
+	WSConn      = domain.WSConn
+	WSOptions   = domain.WSOptions
+	WSCloseError = domain.WSCloseError
+	LineError   = domain.LineError
+	StaticOptions = domain.StaticOptions
+	Content     = domain.Content
+	SecuredMiddleware = domain.SecuredMiddleware
+	SecurityScheme = domain.SecurityScheme
+)
+
+// WebSocket message types for WSConn.WriteMessage
+const (
+	WSText   = domain.WSText
+	WSBinary = domain.WSBinary
+)
+
+// Streamed media types for Stream and StreamBody
+const (
+	NDJSONType      = domain.NDJSONType
+	EventStreamType = domain.EventStreamType
+)
+
+// Dependency scopes for app.Provide
+const (
+	ScopeRequest   = domain.ScopeRequest
+	ScopeSingleton = domain.ScopeSingleton
+	ScopeTransient = domain.ScopeTransient
+)
+
+// Spec formats for app.WriteSpec
+const (
+	SpecJSON = core.SpecJSON
+	SpecYAML = core.SpecYAML
+)
+
+// OpenAPI versions for app.OpenAPIVersion
+const (
+	OpenAPI30 = domain.OpenAPI30
+	OpenAPI31 = domain.OpenAPI31
 )
 
 // Context represents the request context.
 // Re-exported from domain for user convenience
 
+// NewKey creates a collision-safe key for request values of type T.
+// Usage: var UserKey = api.NewKey[*User]("user"); UserKey.Set(ctx, u)
+func NewKey[T any](name string) *domain.Key[T] {
+	return domain.NewKey[T](name)
+}
+
+// GetValue returns a request value as a T; false when missing or of another type.
+// Usage: user, ok := api.GetValue[*User](ctx, UserKey)
+func GetValue[T any](ctx *Context, key interface{}) (T, bool) {
+	return domain.GetValue[T](ctx, key)
+}
+
+// BindJSONStream reads a JSON Lines request body one validated T at a time.
+// Usage: items := api.BindJSONStream[Order](ctx); for items.Next() { order, err := items.Item() }
+func BindJSONStream[T any](ctx *Context) *domain.JSONLines[T] {
+	return domain.BindJSONStream[T](ctx)
+}
+
+// StreamJSONLinesFrom streams the items of a channel as a JSON Lines response.
+// Usage: return api.StreamJSONLinesFrom(ctx, orders)
+func StreamJSONLinesFrom[T any](ctx *Context, items <-chan T) error {
+	return domain.StreamJSONLinesFrom(ctx, items)
+}
+
 // Helper functions for route options
 
 // Body specifies the request body type.
@@ -52,7 +132,7 @@ func Body(typ interface{}) RouteOptions {
 
 // Response specifies the response type and status code.
 func Response(statusCode int, typ interface{}) RouteOptions {
-	return RouteOptions{Response: typ}
+	return RouteOptions{Response: typ, ResponseStatus: statusCode}
 }
 
 // Summary sets the endpoint summary for Swagger.
@@ -60,6 +140,38 @@ func Summary(text string) RouteOptions {
 	return RouteOptions{Summary: text}
 }
 
+// Description sets the long endpoint description for Swagger (Markdown allowed).
+func Description(text string) RouteOptions {
+	return RouteOptions{Description: text}
+}
+
+// OperationID sets the unique OpenAPI operationId, used by client generators.
+func OperationID(id string) RouteOptions {
+	return RouteOptions{OperationID: id}
+}
+
+// Deprecated marks the endpoint as deprecated in Swagger.
+func Deprecated() RouteOptions {
+	return RouteOptions{Deprecated: true}
+}
+
+// RequestExample sets an example request body.
+// Use as: RequestExample(UserRequest{Name: "Ada"})
+func RequestExample(example interface{}) RouteOptions {
+	return RouteOptions{RequestExample: example}
+}
+
+// ResponseExample sets an example response body.
+// Use as: ResponseExample(UserResponse{ID: 1, Name: "Ada"})
+func ResponseExample(example interface{}) RouteOptions {
+	return RouteOptions{ResponseExample: example}
+}
+
+// ExternalDocs links the endpoint to external documentation.
+func ExternalDocs(url, description string) RouteOptions {
+	return RouteOptions{ExternalDocs: &domain.ExternalDocs{URL: url, Description: description}}
+}
+
 // Tags sets the OpenAPI tags for Swagger.
 func Tags(tags ...string) RouteOptions {
 	return RouteOptions{Tags: tags}
@@ -79,8 +191,94 @@ func Params(spec map[string]string) RouteOptions {
 	return RouteOptions{Params: paramSpec}
 }
 
+// Timeout bounds the handler: its Context() gets a deadline and the request
+// fails with 504 when it passes. Use as: Timeout(2 * time.Second)
+func Timeout(d time.Duration) RouteOptions {
+	return RouteOptions{Timeout: d}
+}
+
+// Query declares the query parameters struct, read from `query:"..."` tags.
+// Use as: Query(ListUsersQuery{}) and bind with ctx.BindQuery(&q)
+func Query(typ interface{}) RouteOptions {
+	return RouteOptions{Query: typ}
+}
+
+// Headers declares the header parameters struct, read from `header:"..."` tags.
+// Use as: Headers(TraceHeaders{}) and bind with ctx.BindHeader(&h)
+func Headers(typ interface{}) RouteOptions {
+	return RouteOptions{Headers: typ}
+}
+
+// Cookies declares the cookie parameters struct, read from `cookie:"..."` tags.
+// Use as: Cookies(SessionCookies{}) and bind with ctx.BindCookie(&c)
+func Cookies(typ interface{}) RouteOptions {
+	return RouteOptions{Cookies: typ}
+}
+
+// Form declares the form body struct, read from `form:"..."` tags; *FormFile fields
+// make it multipart/form-data. Use as: Form(AvatarForm{}) and bind with ctx.BindForm(&f)
+func Form(typ interface{}) RouteOptions {
+	return RouteOptions{Form: typ}
+}
+
+// Upload sets the multipart limits of a route.
+// Use as: Upload(UploadLimits{MaxFiles: 1, MaxFileSize: 5 << 20, AllowedTypes: []string{"image/*"}})
+func Upload(limits UploadLimits) RouteOptions {
+	return RouteOptions{Upload: &limits}
+}
+
+// Stream documents a streamed response of the given media type, written with
+// ctx.Stream. Combine with Response to describe each chunk.
+// Use as: Stream("application/x-ndjson")
+func Stream(contentType string) RouteOptions {
+	return RouteOptions{Stream: contentType}
+}
+
+// StreamBody documents a streamed request body of the given media type, read with
+// BindJSONStream. Combine with Body to describe each item.
+// Use as: StreamBody(NDJSONType), Body(Order{})
+func StreamBody(contentType string) RouteOptions {
+	return RouteOptions{BodyStream: contentType}
+}
+
+// SSE documents a Server-Sent Events response written with ctx.SSE; the Response
+// type, if any, describes the data of each event.
+// Use as: SSE(), Response(200, PriceUpdate{})
+func SSE() RouteOptions {
+	return RouteOptions{Stream: domain.EventStreamType}
+}
+
+// WebSocket sets the connection options of an app.WS route.
+// Use as: WebSocket(WSOptions{ReadLimit: 64 << 10, Origins: []string{"https://app.example.com"}})
+func WebSocket(options WSOptions) RouteOptions {
+	return RouteOptions{WebSocket: &options}
+}
+
+// OneOf registers the concrete implementations of an interface type (tagged union).
+// The discriminator JSON property selects the variant: OpenAPI gets oneOf + discriminator
+// and BindJSON decodes into the matching concrete type.
+// Use as: OneOf[PaymentMethod]("type", map[string]PaymentMethod{"card": Card{}, "bank_transfer": BankTransfer{}})
+// Panics if T is not an interface, like other registration errors caught at startup.
+func OneOf[T any](discriminator string, variants map[string]T) {
+	iface := reflect.TypeOf((*T)(nil)).Elem()
+	types := make(map[string]reflect.Type, len(variants))
+	for value, variant := range variants {
+		types[value] = reflect.TypeOf(variant)
+	}
+
+	if err := application.RegisterUnion(iface, discriminator, types); err != nil {
+		panic("syntrogo: " + err.Error())
+	}
+}
+
 // Middleware applies a middleware to this specific route.
 func Middleware(mw domain.Middleware) RouteOptions {
 	return RouteOptions{Middlewares: []domain.Middleware{mw}}
 }
 
+// Secured applies a security middleware to this specific route and documents its schemes.
+// Use as: Secured(security.BearerToken("token"))
+func Secured(mw domain.SecuredMiddleware) RouteOptions {
+	return mw.Options()
+}
+